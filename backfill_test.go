@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestComposeAt(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(24 * time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithBackfillMachineID(1023))
+
+	event := epoch.Add(90 * time.Minute)
+	id, err := sf.ComposeAt(event, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := sf.Decompose(uint64(id))
+	if !p.Time.Equal(event) || p.MachineID != 1023 || p.Sequence != 42 {
+		t.Fatalf("Decompose = %+v", p)
+	}
+
+	if _, err := sf.ComposeAt(clock.Now().Add(time.Millisecond), 0); !errors.Is(err, ErrPartRange) {
+		t.Errorf("future time error = %v", err)
+	}
+	if _, err := sf.ComposeAt(event, 4096); !errors.Is(err, ErrPartRange) {
+		t.Errorf("sequence overflow error = %v", err)
+	}
+
+	own := NewSnowflake(epoch, 7, WithClock(clock))
+	if id, _ := own.ComposeAt(event, 0); own.Decompose(uint64(id)).MachineID != 7 {
+		t.Errorf("ComposeAt without a backfill machine used machine %d", own.Decompose(uint64(id)).MachineID)
+	}
+
+	for _, mid := range []uint64{1, 1024} {
+		if NewSnowflake(epoch, 1, WithClock(clock), WithBackfillMachineID(mid)) != nil {
+			t.Errorf("backfill machine id %d was accepted", mid)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+// WithMaxBackwardTolerance changes how the generator reacts to a clock that
+// is behind the last issued timestamp. Lags up to d, such as an NTP slew,
+// are absorbed by waiting for the clock to catch up; larger lags make
+// NextID return ErrClockMovedBackwards. d should be at least one tick.
+//
+// Without this option the generator keeps issuing IDs from the last
+// timestamp, borrowing time until the sequence rolls over.
+func WithMaxBackwardTolerance(d time.Duration) Option {
+	return func(sf *Snowflake) {
+		sf.maxBackward = d
+		sf.checkBackward = true
+	}
+}
+
+// tick reads the clock, applying the backward tolerance if one is set.
+// It must be called with sf.mutex held.
+func (sf *Snowflake) tick(ctx context.Context, req *idRequest) (int64, error) {
+	currentTimestamp := sf.observe()
+	if !sf.checkBackward {
+		return currentTimestamp, nil
+	}
+
+	lag := sf.lastTimestamp - currentTimestamp
+	if lag <= 0 || time.Duration(lag)*sf.unit <= sf.maxBorrow {
+		return currentTimestamp, nil
+	}
+
+	if d := time.Duration(lag) * sf.unit; d > sf.maxBackward {
+		return 0, sf.generationError(fmt.Errorf("%w: by %s", ErrClockMovedBackwards, d))
+	}
+
+	if req.noWait {
+		return 0, errWouldBlock
+	}
+	if err := sf.waitFor(ctx, req, sf.lastTimestamp); err != nil {
+		return 0, err
+	}
+
+	return sf.observe(), nil
+}
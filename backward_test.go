@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestMaxBackwardTolerance(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithMaxBackwardTolerance(5*time.Millisecond))
+
+	last, _ := sf.NextID()
+
+	// A small slew is absorbed by waiting until the clock catches up.
+	clock.Advance(-3 * time.Millisecond)
+	id, err := sf.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= last {
+		t.Errorf("id %d is not greater than %d", id, last)
+	}
+	if got := clock.Now(); got.Before(epoch.Add(time.Hour)) {
+		t.Errorf("generator should have waited for the clock, now %s", got)
+	}
+
+	clock.Advance(-10 * time.Millisecond)
+	if _, err := sf.NextID(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("expected ErrClockMovedBackwards, got %v", err)
+	}
+	if _, err := sf.ReserveBlock(1); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("expected ErrClockMovedBackwards from ReserveBlock, got %v", err)
+	}
+
+	// Without the option the generator borrows time instead.
+	legacy := NewSnowflake(epoch, 1, WithClock(clock))
+	legacy.NextID()
+	clock.Advance(-10 * time.Millisecond)
+	if _, err := legacy.NextID(); err != nil {
+		t.Errorf("legacy behavior should not fail, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+)
+
+// Weighted is a generator of a Balancer and its share of the IDs.
+type Weighted struct {
+	Generator *Snowflake
+	Weight    int
+}
+
+// Balancer spreads NextID calls over several generators with different
+// machine IDs, e.g. when one service owns several machine IDs, in
+// proportion to their weights. Generators under sequence pressure get a
+// smaller share: the weight of each generator is scaled by the part of its
+// current tick still unused, and exhausted generators are skipped until
+// their next tick, so callers wait for a rollover only once all of them
+// are exhausted.
+type Balancer struct {
+	mu      sync.Mutex
+	members []balanced
+}
+
+type balanced struct {
+	Weighted
+	current int // smooth weighted round robin state
+}
+
+// NewBalancer returns a Balancer over members. It returns ErrSameMachineID
+// if two generators share a machine ID.
+func NewBalancer(members ...Weighted) (*Balancer, error) {
+	if len(members) == 0 {
+		return nil, errors.New("balancer needs at least one generator")
+	}
+
+	b := &Balancer{members: make([]balanced, len(members))}
+	seen := make(map[uint64]bool)
+	for i, m := range members {
+		if m.Generator == nil || m.Weight <= 0 {
+			return nil, errors.New("balancer needs generators with positive weights")
+		}
+		mid := m.Generator.MachineID()
+		if seen[mid] {
+			return nil, ErrSameMachineID
+		}
+		seen[mid] = true
+		b.members[i].Weighted = m
+	}
+
+	return b, nil
+}
+
+// NextID issues an ID from the next generator in line.
+func (b *Balancer) NextID() (uint64, error) {
+	return b.pick().NextID()
+}
+
+// pick chooses a generator by smooth weighted round robin over the
+// pressure adjusted weights.
+func (b *Balancer) pick() *Snowflake {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	weights := make([]int, len(b.members))
+	total := 0
+	for i, m := range b.members {
+		perTick := m.Generator.layout.MaxSequence() + 1
+		if remaining := m.Generator.Capacity(); remaining > 0 {
+			weights[i] = max(int(uint64(m.Weight)*remaining/perTick), 1)
+		}
+		total += weights[i]
+	}
+	if total == 0 {
+		// Everyone is exhausted; keep to the configured shares.
+		for i, m := range b.members {
+			weights[i] = m.Weight
+			total += m.Weight
+		}
+	}
+
+	best := -1
+	for i := range b.members {
+		if weights[i] == 0 {
+			continue
+		}
+		b.members[i].current += weights[i]
+		if best < 0 || b.members[i].current > b.members[best].current {
+			best = i
+		}
+	}
+	b.members[best].current -= total
+
+	return b.members[best].Generator
+}
@@ -0,0 +1,71 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestBalancer(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	var members []Weighted
+	for mid := 1; mid <= 3; mid++ {
+		members = append(members, Weighted{Generator: NewSnowflake(epoch, mid, WithClock(clock)), Weight: mid})
+	}
+	b, err := NewBalancer(members...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[uint64]int)
+	for range 600 {
+		id, err := b.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[members[0].Generator.Decompose(id).MachineID]++
+		clock.Advance(time.Millisecond)
+	}
+	if counts[1] != 100 || counts[2] != 200 || counts[3] != 300 {
+		t.Errorf("unexpected shares %v", counts)
+	}
+
+	if _, err := NewBalancer(members[0], members[0]); !errors.Is(err, ErrSameMachineID) {
+		t.Errorf("expected ErrSameMachineID, got %v", err)
+	}
+	if _, err := NewBalancer(); err == nil {
+		t.Error("expected an error without generators")
+	}
+}
+
+func TestBalancerPressure(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := clocktest.NewFakeClock(start)
+	small := WithLayout(Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 2})
+	busy := NewSnowflake(epoch, 1, WithClock(clock), small)
+	idle := NewSnowflake(epoch, 2, WithClock(clock), small)
+	b, _ := NewBalancer(Weighted{busy, 10}, Weighted{idle, 1})
+
+	// Another caller exhausts busy's tick.
+	for range 4 {
+		busy.NextID()
+	}
+	for range 4 {
+		id, err := b.NextID()
+		if err != nil || idle.Decompose(id).MachineID != 2 {
+			t.Fatalf("expected an ID from the idle generator, got %d, %v", id, err)
+		}
+	}
+	if !clock.Now().Equal(start) {
+		t.Error("waited for a rollover while a generator had capacity")
+	}
+
+	// With both exhausted, the balancer waits for the next tick.
+	if _, err := b.NextID(); err != nil || clock.Now().Equal(start) {
+		t.Errorf("expected a rollover, got %v at %s", err, clock.Now())
+	}
+}
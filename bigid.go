@@ -0,0 +1,104 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// BigID is a 160-bit identifier: a snowflake ID in the high 64 bits
+// followed by 96 bits of random entropy. BigIDs sort by time like the
+// embedded ID but cannot be guessed from neighbouring values.
+type BigID [20]byte
+
+// bigIDBase62Len is the length of a base62 encoded BigID; 62^27 > 2^160.
+const bigIDBase62Len = 27
+
+// WithEntropy sets the source of the random bytes appended by NextBigID.
+// The default is crypto/rand.
+func WithEntropy(r io.Reader) Option {
+	return func(sf *Snowflake) {
+		sf.entropy = r
+	}
+}
+
+// NextBigID returns a new ID extended with random entropy.
+func (sf *Snowflake) NextBigID() (BigID, error) {
+	var b BigID
+
+	id, err := sf.NextID()
+	if err != nil {
+		return b, err
+	}
+
+	entropy := sf.entropy
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	if _, err := io.ReadFull(entropy, b[8:]); err != nil {
+		return b, err
+	}
+	binary.BigEndian.PutUint64(b[:8], id)
+
+	return b, nil
+}
+
+// ID returns the snowflake ID stored in the high 64 bits.
+func (b BigID) ID() ID {
+	return ID(binary.BigEndian.Uint64(b[:8]))
+}
+
+// String returns the fixed-width 27-character base62 form. The encoding
+// sorts in the same order as the raw bytes.
+func (b BigID) String() string {
+	// Big-endian 32-bit words, repeatedly divided by 62.
+	var words [5]uint32
+	for i := range words {
+		words[i] = binary.BigEndian.Uint32(b[i*4:])
+	}
+
+	var buf [bigIDBase62Len]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		var rem uint64
+		for j := range words {
+			v := rem<<32 | uint64(words[j])
+			words[j] = uint32(v / 62)
+			rem = v % 62
+		}
+		buf[i] = base62Alphabet[rem]
+	}
+
+	return string(buf[:])
+}
+
+// ParseBigID decodes a BigID encoded by BigID.String.
+func ParseBigID(s string) (BigID, error) {
+	var b BigID
+	if len(s) != bigIDBase62Len {
+		return b, fmt.Errorf("%w: big id must be %d characters", ErrInvalidEncoding, bigIDBase62Len)
+	}
+
+	var words [5]uint32
+	for i := 0; i < len(s); i++ {
+		d := base62Digit(s[i])
+		if d < 0 {
+			return b, fmt.Errorf("%w: invalid base62 digit %q", ErrInvalidEncoding, s[i])
+		}
+		carry := uint64(d)
+		for j := len(words) - 1; j >= 0; j-- {
+			v := uint64(words[j])*62 + carry
+			words[j] = uint32(v)
+			carry = v >> 32
+		}
+		if carry != 0 {
+			return b, fmt.Errorf("%w: base62 value out of range", ErrInvalidEncoding)
+		}
+	}
+
+	for i, w := range words {
+		binary.BigEndian.PutUint32(b[i*4:], w)
+	}
+
+	return b, nil
+}
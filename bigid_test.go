@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextBigID(t *testing.T) {
+	sf := NewSnowflake(time.Time{}, 7, WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xab}, 12*100))))
+
+	var prev string
+	for i := 0; i < 100; i++ {
+		b, err := sf.NextBigID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.ID().Machine() != 7 {
+			t.Errorf("expected machine 7, got %d", b.ID().Machine())
+		}
+		if !bytes.Equal(b[8:], bytes.Repeat([]byte{0xab}, 12)) {
+			t.Errorf("entropy not appended: %x", b[8:])
+		}
+
+		s := b.String()
+		if len(s) != bigIDBase62Len {
+			t.Fatalf("expected %d characters, got %q", bigIDBase62Len, s)
+		}
+		if s <= prev {
+			t.Errorf("strings out of order: %q <= %q", s, prev)
+		}
+		prev = s
+
+		parsed, err := ParseBigID(s)
+		if err != nil || parsed != b {
+			t.Fatalf("ParseBigID(%q) = %x, %v, want %x", s, parsed, err, b)
+		}
+	}
+
+	if _, err := sf.NextBigID(); err == nil {
+		t.Error("expected an error once entropy is exhausted")
+	}
+}
+
+func TestParseBigIDErrors(t *testing.T) {
+	var max BigID
+	for i := range max {
+		max[i] = 0xff
+	}
+	if got, err := ParseBigID(max.String()); err != nil || got != max {
+		t.Errorf("max value did not round-trip: %x, %v", got, err)
+	}
+
+	for _, s := range []string{"", "abc", strings.Repeat("z", bigIDBase62Len), strings.Repeat("-", bigIDBase62Len)} {
+		if _, err := ParseBigID(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("ParseBigID(%q): expected ErrInvalidEncoding, got %v", s, err)
+		}
+	}
+}
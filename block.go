@@ -0,0 +1,131 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidBlockSize = errors.New("invalid block size")
+
+// Block is a range of sequence numbers within one tick that was reserved on
+// a generator. The holder may mint the IDs of the block itself, e.g. on a
+// remote client, without any risk of the generator issuing them again.
+type Block struct {
+	// Layout of the generator the block was reserved on. The zero Layout
+	// stands for DefaultLayout.
+	Layout Layout
+
+	Timestamp     int64
+	MachineID     uint64
+	FirstSequence uint16
+	LastSequence  uint16
+}
+
+// Len returns the number of IDs in the block.
+func (b Block) Len() int {
+	return int(b.LastSequence) - int(b.FirstSequence) + 1
+}
+
+// ID returns the i-th ID of the block.
+func (b Block) ID(i int) uint64 {
+	return b.Layout.orDefault().Compose(uint64(b.Timestamp), b.MachineID, uint64(b.FirstSequence)+uint64(i))
+}
+
+// ReserveBlock reserves n consecutive sequence numbers. If the current tick
+// has fewer than n left, the block is taken from the next tick, waiting for
+// it like NextID does on rollover.
+func (sf *Snowflake) ReserveBlock(n int) (Block, error) {
+	var req idRequest
+	b, err := sf.reserveBlock(context.Background(), &req, n)
+	if sf.hooks != nil {
+		sf.hooks.runBlock(b, req.events, err)
+	}
+
+	return b, err
+}
+
+// reserveBlock is ReserveBlock without the hooks, collecting their events
+// in req.
+func (sf *Snowflake) reserveBlock(ctx context.Context, req *idRequest, n int) (Block, error) {
+	if n < 1 || uint64(n) > sf.layout.MaxSequence()+1 {
+		return Block{}, fmt.Errorf("%w: %d", ErrInvalidBlockSize, n)
+	}
+	if sf.drift != nil && !sf.drift.Synced() {
+		return Block{}, ErrClockUnsynced
+	}
+
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	if sf.closed {
+		return Block{}, ErrClosed
+	}
+	if sf.limiter != nil {
+		if err := sf.takeTokens(ctx, req, n); err != nil {
+			return Block{}, err
+		}
+	}
+
+	currentTimestamp, err := sf.tick(ctx, req)
+	if err != nil {
+		return Block{}, err
+	}
+
+	var first int
+	if sf.lastTimestamp < currentTimestamp {
+		sf.lastTimestamp = currentTimestamp
+	} else if sf.randomizedSequence() {
+		// Random sequences are scattered over the tick; use a fresh one
+		first = int(sf.seqMask) + 1
+	} else {
+		first = int(sf.seqIndex) + 1
+	}
+
+	if uint64(first+n) > sf.layout.MaxSequence()+1 {
+		if sf.maxBorrow != 0 {
+			if err := sf.checkBorrow(currentTimestamp); err != nil {
+				return Block{}, err
+			}
+		}
+		sf.rollover(req)
+		if sf.maxBorrow == 0 {
+			if err := sf.waitFor(ctx, req, sf.lastTimestamp+1); err != nil {
+				return Block{}, err
+			}
+		}
+		sf.lastTimestamp++
+		first = 0
+	}
+
+	if uint64(sf.lastTimestamp) > sf.layout.MaxTimestamp() {
+		return Block{}, sf.generationError(ErrEpochExhausted)
+	}
+
+	if sf.thresholds != nil {
+		sf.checkEpochThresholds()
+	}
+	if sf.highWater != nil {
+		if err := sf.guardHighWater(); err != nil {
+			return Block{}, err
+		}
+	}
+
+	sf.sequence = uint16(first + n - 1)
+	sf.seqIndex = sf.sequence
+	if sf.randomizedSequence() {
+		sf.seqIndex = sf.seqMask
+	}
+	sf.record(n)
+	if sf.journal != nil {
+		sf.addJournal(sf.layout.Compose(uint64(sf.lastTimestamp), sf.machineID, uint64(first)), n, "")
+	}
+
+	return Block{
+		Layout:        sf.layout,
+		Timestamp:     sf.lastTimestamp,
+		MachineID:     sf.machineID,
+		FirstSequence: uint16(first),
+		LastSequence:  sf.sequence,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestReserveBlock(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 3, WithClock(clock))
+
+	before, _ := sf.NextID()
+
+	b, err := sf.ReserveBlock(4000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 4000 || b.FirstSequence != 1 {
+		t.Fatalf("unexpected block %+v", b)
+	}
+	if b.ID(0) <= before {
+		t.Error("block must start after previously issued ids")
+	}
+
+	// Not enough room left in this tick, so the block moves to the next one.
+	b2, err := sf.ReserveBlock(200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b2.Timestamp != b.Timestamp+1 || b2.FirstSequence != 0 {
+		t.Fatalf("expected block in the next tick, got %+v", b2)
+	}
+	if b2.ID(0) <= b.ID(b.Len()-1) {
+		t.Error("blocks must not overlap")
+	}
+
+	after, _ := sf.NextID()
+	if after <= b2.ID(b2.Len()-1) {
+		t.Error("generator reissued a reserved id")
+	}
+
+	if _, err := sf.ReserveBlock(1<<SequenceBits + 1); !errors.Is(err, ErrInvalidBlockSize) {
+		t.Errorf("expected ErrInvalidBlockSize, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTimeBorrowExceeded = errors.New("time borrow limit exceeded")
+
+// BorrowMetrics can be implemented by a Metrics to follow how far a
+// generator with WithMaxTimeBorrow runs ahead of its clock.
+type BorrowMetrics interface {
+	// TimeBorrowed is called with the new borrow whenever it changes.
+	TimeBorrowed(d time.Duration)
+}
+
+// WithMaxTimeBorrow makes NextID and ReserveBlock continue in the next
+// tick on rollover without sleeping for it, borrowing time from the future,
+// as long as the last ID is at most d ahead of the clock. Beyond that they
+// return ErrTimeBorrowExceeded instead, so embedded timestamps are never
+// more than d wrong. Combined with WithMaxBackwardTolerance, lagging
+// behind the last ID by up to d is not treated as the clock moving
+// backwards. NewSnowflake returns nil if d is shorter than one tick.
+func WithMaxTimeBorrow(d time.Duration) Option {
+	return func(sf *Snowflake) {
+		sf.maxBorrow = d
+	}
+}
+
+// Borrowed returns how far the last issued ID is ahead of the clock, or 0.
+// Like Capacity it does not take the generator lock.
+func (sf *Snowflake) Borrowed() time.Duration {
+	tick, _ := unpackTick(sf.current.Load())
+	if ahead := tick - sf.elapsedTime(); ahead > 0 {
+		return time.Duration(ahead) * sf.unit
+	}
+
+	return 0
+}
+
+// checkBorrow returns ErrTimeBorrowExceeded if moving to the tick after
+// sf.lastTimestamp would take the generator further ahead of
+// currentTimestamp than WithMaxTimeBorrow allows. It must be called with
+// sf.mutex held.
+func (sf *Snowflake) checkBorrow(currentTimestamp int64) error {
+	if ahead := time.Duration(sf.lastTimestamp+1-currentTimestamp) * sf.unit; ahead > sf.maxBorrow {
+		return sf.generationError(fmt.Errorf("%w: %s ahead of the clock", ErrTimeBorrowExceeded, ahead))
+	}
+
+	return nil
+}
+
+// reportBorrow passes a changed borrow to the BorrowMetrics. It must be
+// called with sf.mutex held.
+func (sf *Snowflake) reportBorrow() {
+	ahead := max(sf.lastTimestamp-sf.lastObserved, 0)
+	if ahead != sf.borrowed {
+		sf.borrowed = ahead
+		sf.borrowMetrics.TimeBorrowed(time.Duration(ahead) * sf.unit)
+	}
+}
@@ -0,0 +1,71 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+type borrowMetrics struct {
+	nopMetrics
+	borrowed []time.Duration
+}
+
+func (m *borrowMetrics) TimeBorrowed(d time.Duration) { m.borrowed = append(m.borrowed, d) }
+
+func TestMaxTimeBorrow(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := clocktest.NewFakeClock(start)
+	m := new(borrowMetrics)
+
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithLayout(Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 2}),
+		WithMaxTimeBorrow(2*time.Millisecond), WithMaxBackwardTolerance(0), WithMetrics(m))
+	var last uint64
+	for i := 0; i < 12; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatalf("ID %d: %v", i, err)
+		}
+		if id <= last {
+			t.Fatalf("ID %d not increasing", i)
+		}
+		last = id
+	}
+	if !clock.Now().Equal(start) {
+		t.Errorf("slept until %s", clock.Now())
+	}
+	if got := sf.IDToTime(last); !got.Equal(start.Add(2 * time.Millisecond)) {
+		t.Errorf("last ID at %s, want 2ms ahead", got)
+	}
+	if got := sf.Stats().Borrowed; got != 2*time.Millisecond {
+		t.Errorf("borrowed %s, want 2ms", got)
+	}
+	if len(m.borrowed) != 2 || m.borrowed[1] != 2*time.Millisecond {
+		t.Errorf("unexpected reports %v", m.borrowed)
+	}
+
+	if _, err := sf.NextID(); !errors.Is(err, ErrTimeBorrowExceeded) {
+		t.Errorf("expected ErrTimeBorrowExceeded, got %v", err)
+	}
+	if _, err := sf.ReserveBlock(2); !errors.Is(err, ErrTimeBorrowExceeded) {
+		t.Errorf("expected ErrTimeBorrowExceeded from ReserveBlock, got %v", err)
+	}
+
+	clock.Advance(time.Millisecond)
+	if id, err := sf.NextID(); err != nil || id <= last {
+		t.Errorf("expected a new ID once the clock caught up, got %d, %v", id, err)
+	}
+
+	clock.Advance(time.Second)
+	sf.NextID()
+	if sf.Borrowed() != 0 || m.borrowed[len(m.borrowed)-1] != 0 {
+		t.Errorf("borrow not reset: %s, %v", sf.Borrowed(), m.borrowed)
+	}
+
+	if NewSnowflake(epoch, 1, WithClock(clock), WithMaxTimeBorrow(time.Microsecond)) != nil {
+		t.Error("expected nil for a borrow shorter than a tick")
+	}
+}
@@ -0,0 +1,44 @@
+package snowflake
+
+import "time"
+
+// Bucket returns the number of the time bucket of width d holding the time
+// embedded in id, e.g. for hourly or daily partitions. Buckets are counted
+// from the Unix epoch, not the generator's, so daily buckets start at
+// midnight UTC. Bucket returns 0 if d is not positive.
+func (sf *Snowflake) Bucket(id uint64, d time.Duration) int64 {
+	return bucketOf(sf.IDToTime(id), d)
+}
+
+// BucketRange returns the first and last bucket of width d overlapping the
+// time range from to to, inclusive.
+func (sf *Snowflake) BucketRange(from, to time.Time, d time.Duration) (first, last int64) {
+	return bucketOf(from, d), bucketOf(to, d)
+}
+
+// BucketStart returns the time bucket b of width d begins at.
+func (sf *Snowflake) BucketStart(b int64, d time.Duration) time.Time {
+	return time.Unix(0, b*int64(d)).UTC()
+}
+
+// BucketIDs returns the smallest and largest ID the generator's epoch can
+// produce within bucket b of width d, e.g. as partition bounds.
+func (sf *Snowflake) BucketIDs(b int64, d time.Duration) (first, last uint64) {
+	start := sf.BucketStart(b, d)
+
+	return sf.FirstIDForTime(start), sf.LastIDForTime(start.Add(d - 1))
+}
+
+func bucketOf(t time.Time, d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+
+	ns := t.UnixNano()
+	b := ns / int64(d)
+	if ns%int64(d) < 0 {
+		b--
+	}
+
+	return b
+}
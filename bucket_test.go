@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestBucket(t *testing.T) {
+	// An epoch that is not aligned to midnight must not shift the buckets.
+	epoch := time.Date(2020, 1, 1, 7, 30, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	clock := clocktest.NewFakeClock(time.Date(2024, 3, 10, 23, 59, 59, 999e6, time.UTC))
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+
+	before, _ := sf.NextID()
+	clock.Advance(time.Millisecond)
+	after, _ := sf.NextID()
+
+	b := sf.Bucket(before, day)
+	if sf.Bucket(after, day) != b+1 {
+		t.Errorf("expected midnight to start a new bucket: %d, %d", b, sf.Bucket(after, day))
+	}
+	if got := sf.BucketStart(b+1, day); !got.Equal(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("bucket %d starts at %s", b+1, got)
+	}
+
+	first, last := sf.BucketIDs(b, day)
+	if before < first || before > last || after <= last {
+		t.Errorf("bucket %d ids [%d, %d] do not contain %d but not %d", b, first, last, before, after)
+	}
+	if next, _ := sf.BucketIDs(b+1, day); next <= last {
+		t.Errorf("consecutive buckets overlap: %d after %d", next, last)
+	}
+
+	from := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	if f, l := sf.BucketRange(from, from.Add(48*time.Hour), day); l-f != 2 {
+		t.Errorf("expected 3 daily buckets, got %d to %d", f, l)
+	}
+	if f, l := sf.BucketRange(from, from.Add(90*time.Minute), time.Hour); l-f != 1 {
+		t.Errorf("expected 2 hourly buckets, got %d to %d", f, l)
+	}
+
+	if got := bucketOf(time.Unix(-1, 0), time.Hour); got != -1 {
+		t.Errorf("times before 1970 must round down, got bucket %d", got)
+	}
+	if sf.Bucket(before, 0) != 0 {
+		t.Error("expected bucket 0 for a zero width")
+	}
+}
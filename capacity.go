@@ -0,0 +1,74 @@
+package snowflake
+
+import "time"
+
+// Stats describes how close a generator is to exhausting its sequence space.
+type Stats struct {
+	// Remaining is the number of IDs that can be issued in the current tick
+	// before NextID has to wait for the next one.
+	Remaining uint64
+	// Issued is the number of IDs issued or reserved since creation.
+	Issued uint64
+	// Rollovers is the number of times a tick's sequence space ran out.
+	Rollovers uint64
+	// PeakPerTick is the largest number of IDs issued in a single tick, i.e.
+	// per millisecond with the default time unit.
+	PeakPerTick uint64
+	// Degraded is the number of IDs issued by WithRandomFallback.
+	Degraded uint64
+	// Borrowed is how far the last ID is ahead of the clock; see
+	// WithMaxTimeBorrow.
+	Borrowed time.Duration
+}
+
+// Capacity returns the number of IDs that can be issued before the
+// generator has to wait for the next tick. It does not take the generator
+// lock, so it is cheap to call on every request; the result may already be
+// stale when it returns.
+func (sf *Snowflake) Capacity() uint64 {
+	tick, seq := unpackTick(sf.current.Load())
+	if sf.elapsedTime() > tick {
+		return sf.layout.MaxSequence() + 1
+	}
+
+	return sf.layout.MaxSequence() - seq
+}
+
+// Stats returns the generator's capacity and issuance counters. Like
+// Capacity it does not take the generator lock.
+func (sf *Snowflake) Stats() Stats {
+	return Stats{
+		Remaining:   sf.Capacity(),
+		Issued:      sf.issued.Load(),
+		Rollovers:   sf.rollovers.Load(),
+		PeakPerTick: sf.peak.Load(),
+		Degraded:    sf.degraded.Load(),
+		Borrowed:    sf.Borrowed(),
+	}
+}
+
+// record updates the counters after n IDs were issued at the current tick
+// and sequence index. It must be called with sf.mutex held.
+func (sf *Snowflake) record(n int) {
+	sf.current.Store(uint64(sf.lastTimestamp)<<16 | uint64(sf.seqIndex))
+	sf.issued.Add(uint64(n))
+	if inTick := uint64(sf.seqIndex) + 1; inTick > sf.peak.Load() {
+		sf.peak.Store(inTick)
+	}
+
+	sf.metrics.IDsGenerated(n)
+	if sf.borrowMetrics != nil {
+		sf.reportBorrow()
+	}
+}
+
+// rollover counts a sequence rollover. It must be called with sf.mutex held.
+func (sf *Snowflake) rollover(req *idRequest) {
+	sf.rollovers.Add(1)
+	sf.metrics.SequenceRollover()
+	req.events.rollover = true
+}
+
+func unpackTick(v uint64) (int64, uint64) {
+	return int64(v >> 16), v & 0xffff
+}
@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestCapacity(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithLayout(Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 3}))
+
+	if got := sf.Capacity(); got != 8 {
+		t.Fatalf("expected a fresh generator to have 8 ids, got %d", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		sf.NextID()
+	}
+	if got := sf.Capacity(); got != 5 {
+		t.Errorf("expected 5 ids left, got %d", got)
+	}
+
+	if _, err := sf.ReserveBlock(5); err != nil {
+		t.Fatal(err)
+	}
+	if got := sf.Capacity(); got != 0 {
+		t.Errorf("expected the tick to be exhausted, got %d", got)
+	}
+
+	// Rolls over and sleeps into the next tick.
+	sf.NextID()
+
+	want := Stats{Remaining: 7, Issued: 9, Rollovers: 1, PeakPerTick: 8}
+	if got := sf.Stats(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	clock.Advance(time.Millisecond)
+	if got := sf.Capacity(); got != 8 {
+		t.Errorf("expected a new tick to have 8 ids, got %d", got)
+	}
+}
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time used by a generator.
+type Clock interface {
+	Now() time.Time
+}
+
+// Sleeper may be implemented by a Clock to control how the generator waits
+// for the next tick after a sequence rollover or for rate limit tokens.
+// Fake clocks implement it to advance their time instead of blocking. Waits
+// on a Sleeper cannot be interrupted by Shutdown or a NextIDContext context.
+type Sleeper interface {
+	Sleep(d time.Duration)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// WithClock makes the generator read time from c instead of the system clock.
+func WithClock(c Clock) Option {
+	return func(sf *Snowflake) {
+		sf.clock = c
+	}
+}
+
+// wait sleeps for d on a timer that Shutdown and ctx interrupt, or on the
+// clock's Sleeper. The timer is created on the first wait and reused, so
+// rollovers do not allocate. It must be called with sf.mutex held.
+func (sf *Snowflake) wait(ctx context.Context, d time.Duration) error {
+	if s, ok := sf.sleeper(); ok {
+		s.Sleep(d)
+		return nil
+	}
+
+	if sf.timer == nil {
+		sf.timer = time.NewTimer(d)
+	} else {
+		sf.timer.Reset(d)
+	}
+	defer sf.timer.Stop()
+	select {
+	case <-sf.timer.C:
+		return nil
+	case <-sf.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sleeper returns the Sleeper of the clock, looking through WithTimeOffset.
+func (sf *Snowflake) sleeper() (Sleeper, bool) {
+	c := sf.clock
+	if o, ok := c.(offsetClock); ok {
+		c = o.Clock
+	}
+	s, ok := c.(Sleeper)
+
+	return s, ok
+}
+
+// WithTimeOffset shifts the time the generator reads from its clock by d,
+// e.g. to test behavior close to the end of the epoch without moving the
+// epoch or the system clock. It applies on top of WithClock.
+func WithTimeOffset(d time.Duration) Option {
+	return func(sf *Snowflake) {
+		sf.timeOffset = d
+	}
+}
+
+type offsetClock struct {
+	Clock
+	offset time.Duration
+}
+
+func (c offsetClock) Now() time.Time { return c.Clock.Now().Add(c.offset) }
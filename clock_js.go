@@ -0,0 +1,49 @@
+//go:build js && wasm
+
+package snowflake
+
+import (
+	"math"
+	"syscall/js"
+	"time"
+)
+
+// BrowserClock is a Clock for generators compiled with GOOS=js, e.g. to
+// issue provisional IDs client side. It reads performance.timeOrigin plus
+// performance.now(), which unlike Date.now(), the source of time.Now under
+// js, does not move when the system clock is adjusted while the page is
+// open.
+//
+// Browsers coarsen performance.now() to between 5µs and 1ms, and timers of
+// background tabs fire late, so a rollover may sleep well beyond the next
+// tick. For a reduced-resolution mode that rarely sleeps, combine the clock
+// with WithLowPrecision(TimeUnit10Milliseconds).
+type BrowserClock struct {
+	perf     js.Value
+	originMs int64   // whole milliseconds of performance.timeOrigin
+	fraction float64 // and the rest
+}
+
+// NewBrowserClock returns a BrowserClock, or nil if the JavaScript
+// environment has no performance object.
+func NewBrowserClock() *BrowserClock {
+	perf := js.Global().Get("performance")
+	if perf.Type() != js.TypeObject {
+		return nil
+	}
+
+	origin := perf.Get("timeOrigin").Float()
+	whole := math.Floor(origin)
+
+	return &BrowserClock{perf: perf, originMs: int64(whole), fraction: origin - whole}
+}
+
+// Now returns the time origin of the page plus the time since then. The
+// split avoids the precision a float64 of Unix nanoseconds would lose.
+func (c *BrowserClock) Now() time.Time {
+	elapsed := c.fraction + c.perf.Call("now").Float()
+
+	return time.UnixMilli(c.originMs).Add(time.Duration(elapsed * float64(time.Millisecond)))
+}
+
+func (c *BrowserClock) Sleep(d time.Duration) { time.Sleep(d) }
@@ -0,0 +1,31 @@
+//go:build js && wasm
+
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBrowserClock(t *testing.T) {
+	c := NewBrowserClock()
+	if c == nil {
+		t.Skip("no performance object")
+	}
+	if d := c.Now().Sub(time.Now()); d < -time.Second || d > time.Second {
+		t.Errorf("clock is %s off the system clock", d)
+	}
+
+	sf := NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1, WithClock(c), WithLowPrecision(TimeUnit10Milliseconds))
+	var last uint64
+	for i := 0; i < 100000; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("ID %d not above %d", id, last)
+		}
+		last = id
+	}
+}
@@ -0,0 +1,199 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestFakeClockRollover(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	sf := NewSnowflake(epoch, 34, WithClock(clock))
+
+	var lastID uint64
+	for i := 0; i < 1<<SequenceBits+1; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= lastID {
+			t.Fatalf("id %d is not greater than %d", id, lastID)
+		}
+		lastID = id
+	}
+
+	ts, _, seq := DecomposeParts(lastID)
+	if ts != uint64(time.Hour/time.Millisecond)+1 || seq != 0 {
+		t.Errorf("expected rollover into the next millisecond, got ts %d seq %d", ts, seq)
+	}
+
+	if got := clock.Now(); !got.Equal(epoch.Add(time.Hour + time.Millisecond)) {
+		t.Errorf("generator should have slept one millisecond on the fake clock, now %s", got)
+	}
+}
+
+func TestStepClockOrdering(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewStepClock(epoch.Add(time.Hour), 250*time.Microsecond)
+
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+
+	var lastID uint64
+	for i := 0; i < 100; i++ {
+		id, _ := sf.NextID()
+		if id <= lastID {
+			t.Fatalf("id %d is not greater than %d", id, lastID)
+		}
+		lastID = id
+	}
+
+	if ts, _, seq := DecomposeParts(lastID); seq > 3 {
+		t.Errorf("expected at most 4 ids per millisecond, got seq %d at ts %d", seq, ts)
+	}
+}
+
+func TestTimeOffset(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	lifetime := DefaultLayout.Lifetime(time.Millisecond)
+
+	// One millisecond before the end of the epoch, the last tick is usable.
+	offset := lifetime - time.Hour - time.Millisecond
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithTimeOffset(offset))
+	if sf == nil {
+		t.Fatal("NewSnowflake returned nil")
+	}
+	id, err := sf.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, _, _ := DecomposeParts(id); ts != DefaultLayout.MaxTimestamp() {
+		t.Errorf("expected the last timestamp, got %d", ts)
+	}
+	if !sf.Epoch().Equal(epoch) {
+		t.Errorf("the offset must not move the epoch, got %s", sf.Epoch())
+	}
+
+	// Rollover waits still go through the fake clock.
+	for i := 0; i < 1<<SequenceBits-1; i++ {
+		sf.NextID()
+	}
+	if _, err := sf.NextID(); err == nil {
+		t.Error("expected the epoch to run out")
+	}
+	if got := clock.Now(); !got.Equal(epoch.Add(time.Hour + time.Millisecond)) {
+		t.Errorf("expected a one millisecond wait on the fake clock, now %s", got)
+	}
+}
+
+// scriptedClock returns its readings in turn, repeating the last one. It is
+// not a Sleeper, so the generator waits on real timers.
+type scriptedClock struct {
+	mu       sync.Mutex
+	readings []time.Time
+}
+
+func (c *scriptedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.readings[0]
+	if len(c.readings) > 1 {
+		c.readings = c.readings[1:]
+	}
+
+	return t
+}
+
+func (c *scriptedClock) push(ts ...time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readings = append(c.readings, ts...)
+}
+
+func exhaustTick(t *testing.T, sf *Snowflake) {
+	t.Helper()
+	for range 1 << SequenceBits {
+		if _, err := sf.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWaitInterrupted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(time.Hour)
+	sf := NewSnowflake(epoch, 1, WithClock(&scriptedClock{readings: []time.Time{now}}))
+	exhaustTick(t, sf)
+
+	// The frozen clock never reaches the next tick.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := sf.NextIDContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("NextIDContext = %v", err)
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := sf.NextID()
+		errc <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	sf.Close()
+	if err := <-errc; !errors.Is(err, ErrClosed) {
+		t.Fatalf("NextID during Close = %v", err)
+	}
+}
+
+func TestWaitClockSteppedBack(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(time.Hour)
+	clock := &scriptedClock{readings: []time.Time{now}}
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+	exhaustTick(t, sf)
+
+	// An early wake-up waits again, a step back past the start gives up.
+	clock.push(now.Add(300*time.Microsecond), now.Add(-time.Second))
+	if _, err := sf.NextID(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Fatalf("NextID = %v", err)
+	}
+
+	// The generator stays in the exhausted tick and continues after it.
+	clock.push(now.Add(time.Millisecond))
+	id, err := sf.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, _, seq := DefaultLayout.Decompose(id); ts != uint64(time.Hour/time.Millisecond)+1 || seq != 0 {
+		t.Errorf("got ts %d seq %d after the interrupted wait", ts, seq)
+	}
+}
+
+func TestWaitClockSteppedBackMonotonic(t *testing.T) {
+	// Readings derived from time.Now carry a monotonic reading, which
+	// Before would compare instead of the wall time. Start on a tick
+	// boundary so the early wake-up stays within the tick.
+	now := time.Now()
+	now = now.Add(-time.Duration(now.UnixNano() % int64(time.Millisecond)))
+	epoch := now.Add(-time.Hour)
+	clock := &scriptedClock{readings: []time.Time{now}}
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+	exhaustTick(t, sf)
+
+	clock.push(now.Add(100*time.Microsecond), now.Add(-time.Second))
+	if _, err := sf.NextID(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Fatalf("NextID = %v", err)
+	}
+
+	clock.push(now.Add(time.Millisecond))
+	if _, err := sf.NextID(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,81 @@
+// Package clocktest provides deterministic clocks for testing code that uses
+// snowflake generators.
+package clocktest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock that only moves when told to. Sleeping on it advances
+// its time by the requested duration without blocking.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Set moves the clock to t, which may be in the past.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = t
+}
+
+// Advance moves the clock by d, which may be negative.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func (c *FakeClock) Sleep(d time.Duration) {
+	if d > 0 {
+		c.Advance(d)
+	}
+}
+
+// StepClock is a clock that advances by a fixed step every time it is read.
+type StepClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func NewStepClock(start time.Time, step time.Duration) *StepClock {
+	return &StepClock{now: start, step: step}
+}
+
+// Now returns the current time and then advances the clock by one step.
+func (c *StepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.now
+	c.now = c.now.Add(c.step)
+
+	return t
+}
+
+func (c *StepClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
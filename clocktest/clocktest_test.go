@@ -0,0 +1,34 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	if !c.Now().Equal(start) {
+		t.Error("clock should not move on its own")
+	}
+
+	c.Sleep(time.Millisecond)
+	c.Advance(-2 * time.Millisecond)
+
+	if want := start.Add(-time.Millisecond); !c.Now().Equal(want) {
+		t.Errorf("got %s, want %s", c.Now(), want)
+	}
+}
+
+func TestStepClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewStepClock(start, time.Millisecond)
+
+	c.Now()
+	c.Sleep(time.Second)
+
+	if want := start.Add(time.Second + time.Millisecond); !c.Now().Equal(want) {
+		t.Errorf("got %s, want %s", c.Now(), want)
+	}
+}
@@ -0,0 +1,36 @@
+package snowflake
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestClone(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithLayout(InstagramLayout))
+	sf.NextID()
+
+	c := sf.Clone(2)
+	if c == nil {
+		t.Fatal("Clone returned nil")
+	}
+	if c.Layout() != InstagramLayout || !c.Epoch().Equal(sf.Epoch()) || c.MachineID() != 2 {
+		t.Fatalf("clone has layout %s, epoch %s, machine %d", c.Layout(), c.Epoch(), c.MachineID())
+	}
+	a, _ := sf.NextID()
+	b, _ := c.NextID()
+	if a == b {
+		t.Fatalf("clone issued the same id %d", a)
+	}
+
+	if sf.Clone(1) != nil || sf.Clone(1+1<<InstagramLayout.MachineBits) != nil {
+		t.Error("Clone accepted the generator's own machine id")
+	}
+	if NewSnowflake(epoch, 1, WithClock(clock), WithStateStore(NewFileStore(filepath.Join(t.TempDir(), "state.json")))).Clone(2) != nil {
+		t.Error("Clone shared a state store")
+	}
+}
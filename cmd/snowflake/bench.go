@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+// benchMetrics counts the generator events reported by bench.
+type benchMetrics struct {
+	rollovers atomic.Uint64
+	waited    atomic.Int64
+	backwards atomic.Uint64
+}
+
+func (m *benchMetrics) IDsGenerated(int)             {}
+func (m *benchMetrics) SequenceRollover()            { m.rollovers.Add(1) }
+func (m *benchMetrics) Waited(d time.Duration)       { m.waited.Add(int64(d)) }
+func (m *benchMetrics) ClockBackwards(time.Duration) { m.backwards.Add(1) }
+
+// seenSet detects duplicate IDs of one machine with one bit per possible
+// timestamp and sequence pair in the ticks of the run.
+type seenSet struct {
+	layout snowflake.Layout
+	first  uint64
+	words  []atomic.Uint64
+}
+
+func newSeenSet(layout snowflake.Layout, first, ticks uint64) *seenSet {
+	n := ticks << layout.SequenceBits
+
+	return &seenSet{layout: layout, first: first, words: make([]atomic.Uint64, (n+63)/64)}
+}
+
+// add marks id as seen. It reports false for a duplicate and ok false for
+// IDs outside the ticks the set covers.
+func (s *seenSet) add(id uint64) (fresh, ok bool) {
+	ts, _, seq := s.layout.Decompose(id)
+	if ts < s.first {
+		return false, false
+	}
+	i := (ts-s.first)<<s.layout.SequenceBits | seq
+	if i/64 >= uint64(len(s.words)) {
+		return false, false
+	}
+	bit := uint64(1) << (i % 64)
+
+	return s.words[i/64].Or(bit)&bit == 0, true
+}
+
+// latencies is a log-linear histogram of durations with 16 buckets per
+// power of two, accurate to about 6%.
+type latencies [64 * 16]uint64
+
+func latencyBucket(d time.Duration) int {
+	if d < 16 {
+		return int(max(d, 0))
+	}
+	exp := bits.Len64(uint64(d)) - 5
+
+	return exp*16 + int(uint64(d)>>exp)
+}
+
+func (h *latencies) add(d time.Duration) { h[latencyBucket(d)]++ }
+
+func (h *latencies) merge(o *latencies) {
+	for i := range h {
+		h[i] += o[i]
+	}
+}
+
+// quantile returns the upper bound of the bucket holding quantile q.
+func (h *latencies) quantile(q float64) time.Duration {
+	var total uint64
+	for _, n := range h {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(total-1))
+	var seen uint64
+	for i, n := range h {
+		seen += n
+		if n > 0 && seen > rank {
+			if i < 16 {
+				return time.Duration(i)
+			}
+			exp := i/16 - 1
+			return time.Duration(uint64(i%16+16+1)<<exp - 1)
+		}
+	}
+
+	return 0
+}
+
+func runBench(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	workers := fs.Int("c", runtime.GOMAXPROCS(0), "concurrent goroutines")
+	duration := fs.Duration("d", 5*time.Second, "how long to generate ids")
+	machine, epoch := generatorFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *workers <= 0 || *duration <= 0 {
+		return errors.New("-c and -d must be positive")
+	}
+
+	m := new(benchMetrics)
+	sf, err := newGenerator(*machine, *epoch, snowflake.WithMetrics(m))
+	if err != nil {
+		return err
+	}
+	defer sf.Close()
+
+	// The set starts at the tick of a first ID and leaves a second of slack
+	// for ticks issued after the deadline.
+	id, err := sf.NextID()
+	if err != nil {
+		return err
+	}
+	first, _, _ := sf.Layout().Decompose(id)
+	seen := newSeenSet(sf.Layout(), first, uint64((*duration+time.Second)/sf.TimeUnit())+1)
+	seen.add(id)
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		hist       latencies
+		issued     atomic.Uint64
+		duplicates atomic.Uint64
+		unchecked  atomic.Uint64
+		errs       []error
+	)
+	start := time.Now()
+	deadline := start.Add(*duration)
+	for range *workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var local latencies
+			var n uint64
+			var werr error
+			for {
+				t := time.Now()
+				if !t.Before(deadline) {
+					break
+				}
+				id, err := sf.NextID()
+				local.add(time.Since(t))
+				if err != nil {
+					werr = err
+					break
+				}
+				n++
+				switch fresh, ok := seen.add(id); {
+				case !ok:
+					unchecked.Add(1)
+				case !fresh:
+					duplicates.Add(1)
+				}
+			}
+
+			issued.Add(n)
+			mu.Lock()
+			hist.merge(&local)
+			if werr != nil {
+				errs = append(errs, werr)
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	n := issued.Load()
+	fmt.Fprintf(stdout, "goroutines: %d\nduration: %s\nids: %d\nthroughput: %.0f ids/s\n",
+		*workers, elapsed.Round(time.Millisecond), n, float64(n)/elapsed.Seconds())
+	fmt.Fprintf(stdout, "latency p50: %s\nlatency p99: %s\nlatency max: %s\n",
+		hist.quantile(0.5), hist.quantile(0.99), hist.quantile(1))
+	fmt.Fprintf(stdout, "rollovers: %d\nsleep: %s\nclock backwards: %d\nduplicates: %d\nunchecked: %d\n",
+		m.rollovers.Load(), time.Duration(m.waited.Load()), m.backwards.Load(), duplicates.Load(), unchecked.Load())
+
+	if len(errs) > 0 {
+		return fmt.Errorf("bench: %w", errors.Join(errs...))
+	}
+	if duplicates.Load() > 0 {
+		return fmt.Errorf("bench found %d duplicate ids", duplicates.Load())
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+// Command snowflake generates, inspects and serves snowflake IDs.
+//
+// Usage:
+//
+//	snowflake gen [-n count] [-machine id] [-epoch time]
+//	snowflake decompose [-format auto|dec|hex|base62|base32] [-epoch time] id...
+//	snowflake convert [-from auto|dec|hex|base62|base32] -to dec|hex|base62|base32 id...
+//	snowflake serve [-addr :8080] [-machine id] [-epoch time]
+//	snowflake audit [-epoch time] [-allow id,...] [-bucket 1s] [file...]
+//	snowflake vectors [-n count] [-seed n] [-snowflake-* ...]
+//	snowflake vectors -verify file
+//	snowflake bench [-c goroutines] [-d duration] [-machine id] [-epoch time]
+//
+// audit reads one ID per line from the files, or from standard input, and
+// exits with an error if it finds duplicates, time regressions or machine
+// IDs missing from -allow.
+//
+// vectors writes JSON test vectors for the layout set by the -snowflake-*
+// flags of snowflake.RegisterFlags, or verifies a vectors file, so other
+// implementations can check compatibility.
+//
+// bench generates IDs from -c goroutines for -d, checks them for
+// duplicates and reports throughput, NextID latency, sequence rollovers and
+// time spent sleeping for the next tick, to validate a host before
+// deploying.
+//
+// Epochs are given in RFC 3339 format; the package default is used when
+// -epoch is omitted.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/fethican/snowflake-go/snowflakeaudit"
+	"github.com/fethican/snowflake-go/snowflakehttp"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "snowflake:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected a command: gen, decompose, convert, serve, audit, vectors or bench")
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "gen":
+		return runGen(args, stdout)
+	case "decompose":
+		return runDecompose(args, stdout)
+	case "convert":
+		return runConvert(args, stdout)
+	case "serve":
+		return runServe(args)
+	case "audit":
+		return runAudit(args, stdout)
+	case "vectors":
+		return runVectors(args, stdout)
+	case "bench":
+		return runBench(args, stdout)
+	}
+
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+func generatorFlags(fs *flag.FlagSet) (machine *int, epoch *string) {
+	machine = fs.Int("machine", 0, "machine id")
+	epoch = fs.String("epoch", "", "custom epoch (RFC 3339)")
+
+	return machine, epoch
+}
+
+func newGenerator(machine int, epoch string, opts ...snowflake.Option) (*snowflake.Snowflake, error) {
+	if err := snowflake.DefaultLayout.ValidateMachineID(machine); err != nil {
+		return nil, err
+	}
+	var start time.Time
+	if epoch != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, epoch); err != nil {
+			return nil, err
+		}
+	}
+
+	sf := snowflake.NewSnowflake(start, machine, opts...)
+	if sf == nil {
+		return nil, errors.New("epoch must not be in the future")
+	}
+
+	return sf, nil
+}
+
+func runGen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	n := fs.Int("n", 1, "number of ids")
+	machine, epoch := generatorFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sf, err := newGenerator(*machine, *epoch)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < *n; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, id)
+	}
+
+	return nil
+}
+
+func runDecompose(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("decompose", flag.ContinueOnError)
+	format := fs.String("format", "dec", "input format: auto, dec, hex, base62 or base32")
+	_, epoch := generatorFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sf, err := newGenerator(0, *epoch)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range fs.Args() {
+		id, err := parseID(s, *format)
+		if err != nil {
+			return err
+		}
+
+		p := sf.Parser().Parse(uint64(id))
+		fmt.Fprintf(stdout, "id: %d\ntime: %s\ntimestamp: %d\nmachine id: %d\nsequence: %d\n",
+			id, p.Time.UTC().Format(time.RFC3339Nano), p.Timestamp, p.MachineID, p.Sequence)
+	}
+
+	return nil
+}
+
+func runConvert(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	from := fs.String("from", "dec", "input format: auto, dec, hex, base62 or base32")
+	to := fs.String("to", "", "output format: dec, hex, base62 or base32")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, s := range fs.Args() {
+		id, err := parseID(s, *from)
+		if err != nil {
+			return err
+		}
+
+		out, err := formatID(id, *to)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, out)
+	}
+
+	return nil
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "listen address")
+	machine, epoch := generatorFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sf, err := newGenerator(*machine, *epoch)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return snowflakehttp.ListenAndServe(ctx, *addr, snowflakehttp.NewHandler(sf))
+}
+
+func runAudit(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	allow := fs.String("allow", "", "comma-separated machine ids expected in the stream")
+	bucket := fs.Duration("bucket", time.Second, "throughput histogram bucket")
+	_, epoch := generatorFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var opts []snowflake.Option
+	machine := 0
+	if *allow != "" {
+		var allowed []uint64
+		for _, s := range strings.Split(*allow, ",") {
+			mid, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid machine id %q", s)
+			}
+			allowed = append(allowed, mid)
+		}
+		opts = append(opts, snowflake.WithAllowedMachineIDs(allowed...))
+		machine = int(allowed[0])
+	}
+
+	decoder, err := newGenerator(machine, *epoch, opts...)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		var readers []io.Reader
+		for _, name := range fs.Args() {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			readers = append(readers, f)
+		}
+		in = io.MultiReader(readers...)
+	}
+
+	rep, err := snowflakeaudit.Scan(in, decoder, *bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := rep.WriteTo(stdout); err != nil {
+		return err
+	}
+	if !rep.Clean() {
+		return errors.New("audit found anomalies")
+	}
+
+	return nil
+}
+
+func runVectors(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("vectors", flag.ContinueOnError)
+	n := fs.Int("n", 100, "number of random vectors")
+	seed := fs.Uint64("seed", 1, "random seed")
+	verify := fs.String("verify", "", "vectors file to verify")
+	cfg := snowflake.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *verify != "" {
+		f, err := os.Open(*verify)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		count, err := snowflake.VerifyTestVectors(f)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%d vectors ok\n", count)
+
+		return nil
+	}
+
+	d := cfg.LayoutDescriptor
+	if d.Epoch.IsZero() {
+		def, _ := snowflake.LookupEpoch("default")
+		d.Epoch = def.Start
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	return snowflake.WriteTestVectors(stdout, snowflake.GenerateTestVectors([]snowflake.LayoutDescriptor{d}, *n, *seed))
+}
+
+func parseID(s, format string) (snowflake.ID, error) {
+	switch format {
+	case "dec":
+		n, err := strconv.ParseUint(s, 10, 64)
+		return snowflake.ID(n), err
+	case "hex":
+		return snowflake.ParseHex(s)
+	case "base62":
+		return snowflake.ParseBase62(s)
+	case "base32":
+		return snowflake.ParseBase32(s)
+	case "auto":
+		id, _, err := snowflake.ParseAny(s)
+		return id, err
+	}
+
+	return 0, fmt.Errorf("unknown format %q", format)
+}
+
+func formatID(id snowflake.ID, format string) (string, error) {
+	switch format {
+	case "dec":
+		return strconv.FormatUint(uint64(id), 10), nil
+	case "hex":
+		return id.Hex(), nil
+	case "base62":
+		return id.Base62(), nil
+	case "base32":
+		return id.Base32(), nil
+	}
+
+	return "", fmt.Errorf("unknown format %q", format)
+}
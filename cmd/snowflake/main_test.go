@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := run([]string{"gen", "-n", "3", "-machine", "5"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if ids := strings.Fields(out.String()); len(ids) != 3 {
+		t.Fatalf("expected 3 ids, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"convert", "-to", "base62", "123456789"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "8M0kX" {
+		t.Errorf("unexpected base62 %q", got)
+	}
+
+	out.Reset()
+	if err := run([]string{"convert", "-from", "base62", "-to", "hex", "8M0kX"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "00000000075bcd15" {
+		t.Errorf("unexpected hex %q", got)
+	}
+
+	out.Reset()
+	if err := run([]string{"convert", "-from", "auto", "-to", "dec", "0x75bcd15"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "123456789" {
+		t.Errorf("unexpected decimal %q", got)
+	}
+
+	out.Reset()
+	id := uint64(1)<<22 | 5<<12 | 7
+	if err := run([]string{"decompose", "-epoch", "2020-01-01T00:00:00Z", "-format", "hex", "405007"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"time: 2020-01-01T00:00:00.001Z", "machine id: 5", "sequence: 7"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("decompose of %d missing %q:\n%s", id, want, out.String())
+		}
+	}
+
+	if err := run([]string{"frobnicate"}, &out); err == nil {
+		t.Error("unknown command should fail")
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	const id = "998492757481750528"
+
+	for _, format := range []string{"dec", "hex", "base62", "base32"} {
+		var out bytes.Buffer
+		if err := run([]string{"convert", "-to", format, id}, &out); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		encoded := strings.TrimSpace(out.String())
+
+		out.Reset()
+		if err := run([]string{"convert", "-from", "auto", "-to", "dec", encoded}, &out); err != nil {
+			t.Fatalf("%s %q: %v", format, encoded, err)
+		}
+		if got := strings.TrimSpace(out.String()); got != id {
+			t.Errorf("%s %q read back as %s", format, encoded, got)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	if err := os.WriteFile(path, []byte("4198400\n4198401\n4198401\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := run([]string{"audit", "-epoch", "2020-01-01T00:00:00Z", "-allow", "1,2", path}, &out)
+	if err == nil {
+		t.Fatal("expected the duplicate to fail the audit")
+	}
+	if !strings.Contains(out.String(), "4198401 seen 2 times") {
+		t.Errorf("duplicate not reported:\n%s", out.String())
+	}
+
+	if err := os.WriteFile(path, []byte("4198400\n4198401\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run([]string{"audit", "-allow", "1", path}, &out); err != nil {
+		t.Errorf("clean stream failed the audit: %v\n%s", err, out.String())
+	}
+}
+
+func TestInvalidMachineID(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"gen", "-machine", "4096"}, &out); !errors.Is(err, snowflake.ErrInvalidMachineID) {
+		t.Errorf("expected ErrInvalidMachineID, got %v", err)
+	}
+}
+
+func TestVectors(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"vectors", "-n", "5", "-snowflake-epoch", "2020-01-01T00:00:00Z", "-snowflake-tag-bits", "1", "-snowflake-time-bits", "41"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"epoch": "2020-01-01T00:00:00Z"`) || !strings.Contains(out.String(), `"tag_bits": 1`) {
+		t.Errorf("unexpected vectors:\n%.400s", out.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "vectors.json")
+	if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run([]string{"vectors", "-verify", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "12 vectors ok" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestBench(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"bench", "-c", "4", "-d", "50ms", "-machine", "3"}, &out); err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	for _, want := range []string{"goroutines: 4", "throughput: ", "latency p99: ", "rollovers: ", "duplicates: 0\n", "unchecked: 0\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("bench output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	seen := newSeenSet(snowflake.DefaultLayout, 10, 2)
+	id := snowflake.DefaultLayout.Compose(11, 3, 4095)
+	if fresh, ok := seen.add(id); !fresh || !ok {
+		t.Errorf("first add = %v, %v", fresh, ok)
+	}
+	if fresh, ok := seen.add(id); fresh || !ok {
+		t.Errorf("duplicate add = %v, %v", fresh, ok)
+	}
+	if _, ok := seen.add(snowflake.DefaultLayout.Compose(12, 3, 0)); ok {
+		t.Error("id past the set was checked")
+	}
+
+	var h latencies
+	for d := time.Duration(1); d <= 100; d++ {
+		h.add(d * time.Microsecond)
+	}
+	if p99 := h.quantile(0.99); p99 < 99*time.Microsecond || p99 > 105*time.Microsecond {
+		t.Errorf("p99 = %s", p99)
+	}
+}
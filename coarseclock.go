@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CoarseClock is a Clock that reads the system clock once per resolution on
+// a background goroutine and serves Now from the cached value. It trades
+// precision for speed where time.Now is a system call rather than a vDSO
+// read, as on some ARM kernels.
+//
+// Now lags the system clock by up to one resolution, so IDs carry slightly
+// older timestamps. A resolution above the generator's time unit also caps
+// throughput, since every tick the cache skips is a tick of sequence
+// numbers the generator cannot use.
+type CoarseClock struct {
+	nanos atomic.Int64
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// NewCoarseClock starts a CoarseClock refreshed every resolution, which
+// defaults to one millisecond. Call Stop to release its goroutine.
+func NewCoarseClock(resolution time.Duration) *CoarseClock {
+	if resolution <= 0 {
+		resolution = time.Millisecond
+	}
+
+	c := &CoarseClock{stop: make(chan struct{})}
+	c.nanos.Store(time.Now().UnixNano())
+
+	ticker := time.NewTicker(resolution)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				c.nanos.Store(now.UnixNano())
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+
+	return c
+}
+
+// Now returns the system time as of the last refresh.
+func (c *CoarseClock) Now() time.Time {
+	return time.Unix(0, c.nanos.Load())
+}
+
+// Stop stops refreshing the clock; Now keeps returning the last value.
+func (c *CoarseClock) Stop() {
+	c.once.Do(func() { close(c.stop) })
+}
@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoarseClock(t *testing.T) {
+	c := NewCoarseClock(time.Millisecond)
+	defer c.Stop()
+
+	start := c.Now()
+	if d := time.Since(start); d < 0 || d > time.Second {
+		t.Fatalf("coarse clock is %s off the system clock", d)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !c.Now().After(start) {
+		if time.Now().After(deadline) {
+			t.Fatal("coarse clock did not advance")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Stop()
+	c.Stop()
+	time.Sleep(2 * time.Millisecond)
+	stopped := c.Now()
+	time.Sleep(5 * time.Millisecond)
+	if !c.Now().Equal(stopped) {
+		t.Error("coarse clock advanced after Stop")
+	}
+}
+
+func TestCoarseClockGenerator(t *testing.T) {
+	c := NewCoarseClock(5 * time.Millisecond)
+	defer c.Stop()
+
+	sf := NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1, WithClock(c))
+
+	// Exhaust several ticks' worth of sequence numbers while the cached time
+	// stands still for up to five of them.
+	var last uint64
+	for i := 0; i < 4<<SequenceBits; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+	}
+}
+
+func BenchmarkClockNow(b *testing.B) {
+	c := NewCoarseClock(time.Millisecond)
+	defer c.Stop()
+
+	b.Run("system", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			systemClock{}.Now()
+		}
+	})
+	b.Run("coarse", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			c.Now()
+		}
+	})
+}
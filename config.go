@@ -0,0 +1,164 @@
+package snowflake
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidConfig = errors.New("invalid generator configuration")
+
+// Environment variables read by Config.LoadEnv and NewFromEnv.
+const (
+	EnvMachineID    = "SNOWFLAKE_MACHINE_ID"
+	EnvEpoch        = "SNOWFLAKE_EPOCH" // RFC 3339 or a registered epoch name
+	EnvTimeBits     = "SNOWFLAKE_BITS_TIME"
+	EnvTagBits      = "SNOWFLAKE_BITS_TAG"
+	EnvMachineBits  = "SNOWFLAKE_BITS_MACHINE"
+	EnvSequenceBits = "SNOWFLAKE_BITS_SEQUENCE"
+	EnvTimeUnit     = "SNOWFLAKE_TIME_UNIT"
+)
+
+// Config is a generator's scheme and machine ID, as read from the
+// environment or command line flags. The zero Epoch selects the package
+// default.
+type Config struct {
+	LayoutDescriptor
+	MachineID int
+}
+
+// DefaultConfig returns the configuration of NewSnowflake without options.
+func DefaultConfig() Config {
+	return Config{LayoutDescriptor: LayoutDescriptor{Layout: DefaultLayout, TimeUnit: snowflakeTimeUnit}}
+}
+
+// NewFromEnv returns a generator configured by the SNOWFLAKE_* environment
+// variables on top of DefaultConfig. Options may override them.
+func NewFromEnv(opts ...Option) (*Snowflake, error) {
+	c := DefaultConfig()
+	if err := c.LoadEnv(); err != nil {
+		return nil, err
+	}
+
+	return c.New(opts...)
+}
+
+// LoadEnv overrides c with the SNOWFLAKE_* environment variables that are
+// set.
+func (c *Config) LoadEnv() error {
+	for _, v := range []struct {
+		name string
+		set  func(string) error
+	}{
+		{EnvMachineID, c.setMachineID},
+		{EnvEpoch, c.setEpoch},
+		{EnvTimeBits, bitsSetter(&c.TimeBits)},
+		{EnvTagBits, bitsSetter(&c.TagBits)},
+		{EnvMachineBits, bitsSetter(&c.MachineBits)},
+		{EnvSequenceBits, bitsSetter(&c.SequenceBits)},
+		{EnvTimeUnit, c.setTimeUnit},
+	} {
+		s, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		if err := v.set(s); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, v.name, err)
+		}
+	}
+
+	return nil
+}
+
+// RegisterFlags returns a DefaultConfig set by flags registered on fs:
+// -snowflake-machine-id, -snowflake-epoch, -snowflake-time-bits,
+// -snowflake-tag-bits, -snowflake-machine-bits, -snowflake-sequence-bits
+// and -snowflake-time-unit. Call LoadEnv before fs.Parse to let the flags
+// override the environment.
+func RegisterFlags(fs *flag.FlagSet) *Config {
+	c := DefaultConfig()
+	c.RegisterFlags(fs)
+
+	return &c
+}
+
+// RegisterFlags registers flags setting c on fs, see the package-level
+// RegisterFlags.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.Func("snowflake-machine-id", "machine id", c.setMachineID)
+	fs.Func("snowflake-epoch", "epoch, in RFC 3339 format or a registered name", c.setEpoch)
+	fs.UintVar(&c.TimeBits, "snowflake-time-bits", c.TimeBits, "bits of the timestamp field")
+	fs.UintVar(&c.TagBits, "snowflake-tag-bits", c.TagBits, "bits of the tag field")
+	fs.UintVar(&c.MachineBits, "snowflake-machine-bits", c.MachineBits, "bits of the machine id field")
+	fs.UintVar(&c.SequenceBits, "snowflake-sequence-bits", c.SequenceBits, "bits of the sequence field")
+	fs.Func("snowflake-time-unit", "length of one tick, e.g. 1ms", c.setTimeUnit)
+}
+
+// New returns a generator for c, with opts applied after it. A machine ID
+// that does not fit the layout is reported as ErrInvalidMachineID as well
+// as ErrInvalidConfig.
+func (c Config) New(opts ...Option) (*Snowflake, error) {
+	if err := c.LayoutDescriptor.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := c.Layout.ValidateMachineID(c.MachineID); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	sf := NewFromLayout(c.LayoutDescriptor, c.MachineID, opts...)
+	if sf == nil {
+		return nil, fmt.Errorf("%w: generator rejected the configuration", ErrInvalidConfig)
+	}
+
+	return sf, nil
+}
+
+func (c *Config) setMachineID(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	c.MachineID = n
+
+	return nil
+}
+
+func (c *Config) setEpoch(s string) error {
+	if e, ok := LookupEpoch(s); ok {
+		c.Epoch = e.Start
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return err
+	}
+	c.Epoch = t
+
+	return nil
+}
+
+func (c *Config) setTimeUnit(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	c.TimeUnit = d
+
+	return nil
+}
+
+func bitsSetter(p *uint) func(string) error {
+	return func(s string) error {
+		n, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return err
+		}
+		*p = uint(n)
+
+		return nil
+	}
+}
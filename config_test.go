@@ -0,0 +1,80 @@
+package snowflake
+
+import (
+	"errors"
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv(EnvMachineID, "300")
+	t.Setenv(EnvEpoch, "2020-01-01T00:00:00Z")
+	t.Setenv(EnvTimeBits, "41")
+	t.Setenv(EnvSequenceBits, "13")
+
+	sf, err := NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := LayoutDescriptor{
+		Epoch:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Layout:   Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 13},
+		TimeUnit: time.Millisecond,
+	}
+	if got := sf.Descriptor(); !got.Epoch.Equal(want.Epoch) || got.Layout != want.Layout || got.TimeUnit != want.TimeUnit {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if sf.MachineID() != 300 {
+		t.Errorf("expected machine id 300, got %d", sf.MachineID())
+	}
+
+	t.Setenv(EnvEpoch, "twitter")
+	if sf, err := NewFromEnv(); err != nil || !sf.Epoch().Equal(TwitterEpoch.Start) {
+		t.Errorf("expected the twitter epoch, got %v", err)
+	}
+
+	for _, tc := range []struct{ name, value string }{
+		{EnvMachineID, "1024"},
+		{EnvMachineID, "x"},
+		{EnvEpoch, "yesterday"},
+		{EnvTimeUnit, "7ms"},
+		{EnvMachineBits, "300"},
+	} {
+		t.Run(tc.name+"="+tc.value, func(t *testing.T) {
+			t.Setenv(EnvSequenceBits, "12")
+			t.Setenv(EnvTimeBits, "42")
+			t.Setenv(EnvMachineID, "1")
+			t.Setenv(tc.name, tc.value)
+			if _, err := NewFromEnv(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	t.Setenv(EnvMachineID, "7")
+	t.Setenv(EnvTimeUnit, "10ms")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := RegisterFlags(fs)
+	if err := c.LoadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	err := fs.Parse([]string{"-snowflake-machine-id", "9", "-snowflake-epoch", "2021-06-01T00:00:00Z", "-snowflake-machine-bits", "8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sf, err := c.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sf.MachineID() != 9 || sf.TimeUnit() != 10*time.Millisecond || sf.Layout().MachineBits != 8 {
+		t.Errorf("flags did not override the environment: %s", sf)
+	}
+	if !sf.Epoch().Equal(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected epoch %s", sf.Epoch())
+	}
+}
@@ -0,0 +1,66 @@
+package snowflake
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const cursorVersion = 1
+
+// MinIDAfter returns the smallest ID the generator can produce after the
+// tick containing t, i.e. the lower bound for "created after t" queries.
+// It returns 0 for times before the epoch and the largest ID if t is in or
+// after the last representable tick.
+func (sf *Snowflake) MinIDAfter(t time.Time) uint64 {
+	if sf.toUnit(t) < sf.startTime {
+		return 0
+	}
+
+	next, ok := NextCursor(sf.LastIDForTime(t))
+	if !ok || next > sf.layout.ComposeTagged(sf.layout.MaxTimestamp(), sf.layout.MaxTag(), sf.layout.MaxMachineID(), sf.layout.MaxSequence()) {
+		return sf.LastIDForTime(t)
+	}
+
+	return next
+}
+
+// NextCursor returns id+1, the exclusive lower bound for the page after
+// id. ok is false if id is the largest uint64 and there is no next page.
+func NextCursor(id uint64) (next uint64, ok bool) {
+	if id == 1<<64-1 {
+		return id, false
+	}
+
+	return id + 1, true
+}
+
+// EncodeCursor returns an opaque, URL-safe cursor for id. The cursor
+// records the generator's epoch, so DecodeCursor on a generator with a
+// different epoch rejects it instead of misreading it.
+func (sf *Snowflake) EncodeCursor(id uint64) string {
+	var b [17]byte
+	b[0] = cursorVersion
+	binary.BigEndian.PutUint64(b[1:], uint64(sf.Epoch().UnixMilli()))
+	binary.BigEndian.PutUint64(b[9:], id)
+
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// DecodeCursor returns the ID stored in a cursor from EncodeCursor.
+func (sf *Snowflake) DecodeCursor(s string) (uint64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != 17 || b[0] != cursorVersion {
+		return 0, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+
+	if epoch := int64(binary.BigEndian.Uint64(b[1:])); epoch != sf.Epoch().UnixMilli() {
+		return 0, fmt.Errorf("%w: issued for epoch %s", ErrInvalidCursor, time.UnixMilli(epoch).UTC())
+	}
+
+	return binary.BigEndian.Uint64(b[9:]), nil
+}
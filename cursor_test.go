@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMinIDAfter(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sf := NewSnowflake(epoch, 1)
+
+	at := epoch.Add(time.Hour)
+	min := sf.MinIDAfter(at)
+	if got := sf.IDToTime(min); !got.Equal(at.Add(time.Millisecond)) {
+		t.Errorf("MinIDAfter points to %s, want the next tick", got)
+	}
+	if min != sf.LastIDForTime(at)+1 {
+		t.Errorf("MinIDAfter(%s) = %d, want %d", at, min, sf.LastIDForTime(at)+1)
+	}
+
+	if got := sf.MinIDAfter(epoch.Add(-time.Hour)); got != 0 {
+		t.Errorf("expected 0 before the epoch, got %d", got)
+	}
+	if got, want := sf.MinIDAfter(epoch.Add(200*365*24*time.Hour)), sf.LastIDForTime(epoch.Add(200*365*24*time.Hour)); got != want {
+		t.Errorf("expected the largest id after the last tick, got %d want %d", got, want)
+	}
+
+	if next, ok := NextCursor(41); next != 42 || !ok {
+		t.Errorf("NextCursor(41) = %d, %t", next, ok)
+	}
+	if _, ok := NextCursor(1<<64 - 1); ok {
+		t.Error("expected NextCursor to report overflow")
+	}
+}
+
+func TestCursorEncoding(t *testing.T) {
+	sf := NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+	other := NewSnowflake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+
+	id, _ := sf.NextID()
+	c := sf.EncodeCursor(id)
+
+	if got, err := sf.DecodeCursor(c); err != nil || got != id {
+		t.Errorf("DecodeCursor(%q) = %d, %v, want %d", c, got, err, id)
+	}
+	if _, err := other.DecodeCursor(c); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected a cursor from another epoch to be rejected, got %v", err)
+	}
+	for _, s := range []string{"", "not a cursor", c[:len(c)-2]} {
+		if _, err := sf.DecodeCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q): expected ErrInvalidCursor, got %v", s, err)
+		}
+	}
+}
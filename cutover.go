@@ -0,0 +1,178 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCutoverEpoch = errors.New("new epoch must not be later than the old epoch")
+	ErrCutoverPhase = errors.New("cutover is not in the required phase")
+	ErrCutoverTime  = errors.New("cutover time must be in the future")
+)
+
+// CutoverPhase is the stage of a fleet-wide epoch migration.
+type CutoverPhase int
+
+const (
+	// CutoverPending: only the old epoch is issued and decoded.
+	CutoverPending CutoverPhase = iota
+	// CutoverScheduled: the cutover ID is fixed and both epochs are decoded.
+	// The old epoch is issued until CutoverAt, the new one afterwards.
+	CutoverScheduled
+	// CutoverComplete: only the new epoch is issued. IDs below the cutover
+	// ID still decode with the old epoch.
+	CutoverComplete
+)
+
+// CutoverState is the shared state of a migration, stored in a CutoverBackend
+// so all nodes of a fleet agree on it.
+type CutoverState struct {
+	Phase     CutoverPhase `json:"phase"`
+	CutoverAt time.Time    `json:"cutover_at"`
+	CutoverID uint64       `json:"cutover_id"`
+}
+
+// CutoverBackend stores the CutoverState shared by a fleet.
+type CutoverBackend interface {
+	LoadCutover(ctx context.Context) (CutoverState, error)
+	StoreCutover(ctx context.Context, st CutoverState) error
+}
+
+// Cutover coordinates the switch from generator from to generator to, which
+// must share the machine ID and differ only in epoch.
+//
+// IDs at or above the cutover ID belong to the new epoch. Because the new
+// epoch is not later than the old one, old-epoch IDs issued before CutoverAt
+// are always below the cutover ID. A node that misses the schedule keeps
+// issuing old-epoch IDs below the cutover ID for (old epoch - new epoch)
+// after CutoverAt; nodes must Refresh well within that window.
+type Cutover struct {
+	from, to *Snowflake
+	backend  CutoverBackend
+
+	mu    sync.RWMutex
+	state CutoverState
+}
+
+func NewCutover(from, to *Snowflake, backend CutoverBackend) *Cutover {
+	return &Cutover{from: from, to: to, backend: backend}
+}
+
+// Refresh reloads the shared state from the backend.
+func (c *Cutover) Refresh(ctx context.Context) error {
+	st, err := c.backend.LoadCutover(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.state = st
+	c.mu.Unlock()
+
+	return nil
+}
+
+// State returns the state last loaded or stored by this node.
+func (c *Cutover) State() CutoverState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.state
+}
+
+// Schedule fixes the cutover at time at and enables dual decoding.
+func (c *Cutover) Schedule(ctx context.Context, at time.Time) error {
+	if c.to.Epoch().After(c.from.Epoch()) {
+		return ErrCutoverEpoch
+	}
+	if !at.After(c.from.clock.Now()) {
+		return ErrCutoverTime
+	}
+	if c.State().Phase != CutoverPending {
+		return ErrCutoverPhase
+	}
+
+	return c.store(ctx, CutoverState{
+		Phase:     CutoverScheduled,
+		CutoverAt: at,
+		CutoverID: c.to.TimeToSnowflakeID(at),
+	})
+}
+
+// Complete marks the migration as finished once CutoverAt has passed.
+func (c *Cutover) Complete(ctx context.Context) error {
+	st := c.State()
+	if st.Phase != CutoverScheduled || c.from.clock.Now().Before(st.CutoverAt) {
+		return ErrCutoverPhase
+	}
+
+	st.Phase = CutoverComplete
+
+	return c.store(ctx, st)
+}
+
+func (c *Cutover) store(ctx context.Context, st CutoverState) error {
+	if err := c.backend.StoreCutover(ctx, st); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.state = st
+	c.mu.Unlock()
+
+	return nil
+}
+
+// NextID issues an ID from the generator that is active in the current phase.
+func (c *Cutover) NextID() (uint64, error) {
+	st := c.State()
+
+	switch {
+	case st.Phase == CutoverComplete:
+		return c.to.NextID()
+	case st.Phase == CutoverScheduled && !c.to.clock.Now().Before(st.CutoverAt):
+		return c.to.NextID()
+	default:
+		return c.from.NextID()
+	}
+}
+
+// Generator returns the generator whose epoch id was issued under.
+func (c *Cutover) Generator(id uint64) *Snowflake {
+	st := c.State()
+	if st.Phase != CutoverPending && id >= st.CutoverID {
+		return c.to
+	}
+
+	return c.from
+}
+
+// IDToTime converts id to time using the epoch it was issued under.
+func (c *Cutover) IDToTime(id uint64) time.Time {
+	return c.Generator(id).IDToTime(id)
+}
+
+// MemoryCutoverBackend is a CutoverBackend for a single process.
+type MemoryCutoverBackend struct {
+	mu    sync.Mutex
+	state CutoverState
+}
+
+func (b *MemoryCutoverBackend) LoadCutover(context.Context) (CutoverState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state, nil
+}
+
+func (b *MemoryCutoverBackend) StoreCutover(_ context.Context, st CutoverState) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = st
+
+	return nil
+}
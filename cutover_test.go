@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestCutover(t *testing.T) {
+	ctx := context.Background()
+	epochA := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	epochB := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epochA.Add(time.Hour))
+
+	from := NewSnowflake(epochA, 3, WithClock(clock))
+	to := NewSnowflake(epochB, 3, WithClock(clock))
+	backend := new(MemoryCutoverBackend)
+
+	c := NewCutover(from, to, backend)
+	if err := NewCutover(to, from, backend).Schedule(ctx, clock.Now().Add(time.Minute)); !errors.Is(err, ErrCutoverEpoch) {
+		t.Errorf("expected ErrCutoverEpoch, got %v", err)
+	}
+
+	beforeID, _ := c.NextID()
+
+	at := clock.Now().Add(time.Minute)
+	if err := c.Schedule(ctx, at); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another node picks up the schedule from the shared backend.
+	peer := NewCutover(from, to, backend)
+	if err := peer.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(30 * time.Second)
+	scheduledID, _ := peer.NextID()
+	if peer.Generator(scheduledID) != from {
+		t.Error("ids issued before the cutover time should decode with the old epoch")
+	}
+
+	if err := c.Complete(ctx); !errors.Is(err, ErrCutoverPhase) {
+		t.Errorf("completing before the cutover time should fail, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	afterID, _ := peer.NextID()
+	if peer.Generator(afterID) != to {
+		t.Error("ids issued after the cutover time should decode with the new epoch")
+	}
+	if afterID <= scheduledID {
+		t.Error("ids must keep increasing across the cutover")
+	}
+
+	if err := c.Complete(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []uint64{beforeID, scheduledID, afterID} {
+		got := c.IDToTime(id)
+		if got.Before(epochA) || got.After(clock.Now()) {
+			t.Errorf("id %d decoded to implausible time %s", id, got)
+		}
+	}
+}
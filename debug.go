@@ -0,0 +1,59 @@
+package snowflake
+
+import "time"
+
+// DebugInfo is a snapshot of a generator's internals for operators.
+type DebugInfo struct {
+	MachineID     uint64           `json:"machine_id"`
+	Epoch         time.Time        `json:"epoch"`
+	TimeUnit      string           `json:"time_unit"`
+	Layout        LayoutDescriptor `json:"layout"`
+	LastTimestamp int64            `json:"last_timestamp"`
+	LastTime      time.Time        `json:"last_time"`
+	Sequence      uint16           `json:"sequence"`
+	Closed        bool             `json:"closed"`
+
+	Remaining   uint64 `json:"remaining"`
+	Issued      uint64 `json:"issued"`
+	Rollovers   uint64 `json:"rollovers"`
+	PeakPerTick uint64 `json:"peak_per_tick"`
+
+	// ClockBorrowed is how far the last issued tick is ahead of the last
+	// clock reading, i.e. time borrowed after rollovers or a clock going
+	// backwards.
+	ClockBorrowed string `json:"clock_borrowed"`
+	// ClockSynced and ClockOffset report the drift monitor, if any.
+	ClockSynced bool   `json:"clock_synced"`
+	ClockOffset string `json:"clock_offset,omitempty"`
+}
+
+// Debug returns a snapshot of the generator's state.
+func (sf *Snowflake) Debug() DebugInfo {
+	st := sf.Stats()
+
+	sf.mutex.Lock()
+	info := DebugInfo{
+		MachineID:     sf.machineID,
+		Epoch:         sf.Epoch().UTC(),
+		TimeUnit:      sf.unit.String(),
+		Layout:        sf.Descriptor(),
+		LastTimestamp: sf.lastTimestamp,
+		LastTime:      sf.SnowflakeUnitToTime(sf.lastTimestamp).UTC(),
+		Sequence:      sf.sequence,
+		Closed:        sf.closed,
+		Remaining:     st.Remaining,
+		Issued:        st.Issued,
+		Rollovers:     st.Rollovers,
+		PeakPerTick:   st.PeakPerTick,
+		ClockBorrowed: (time.Duration(max(sf.lastTimestamp-sf.lastObserved, 0)) * sf.unit).String(),
+		ClockSynced:   true,
+	}
+	sf.mutex.Unlock()
+
+	if sf.drift != nil {
+		info.ClockSynced = sf.drift.Synced()
+		info.ClockOffset = sf.drift.Offset().String()
+	}
+
+	return info
+}
@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestDebug(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 9, WithClock(clock))
+
+	sf.NextID()
+	sf.NextID()
+	clock.Advance(-3 * time.Millisecond)
+	sf.NextID()
+
+	info := sf.Debug()
+	if info.MachineID != 9 || !info.Epoch.Equal(epoch) || info.Sequence != 2 || info.Issued != 3 {
+		t.Errorf("unexpected state: %+v", info)
+	}
+	if !info.LastTime.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("got last time %s", info.LastTime)
+	}
+	if info.ClockBorrowed != "3ms" || !info.ClockSynced || info.Layout.Layout != DefaultLayout {
+		t.Errorf("unexpected clock or layout: %+v", info)
+	}
+
+	l := Layout{TimeBits: 41, TagBits: 2, MachineBits: 9, SequenceBits: 12}
+	if got := NewSnowflake(epoch, 9, WithClock(clock), WithLayout(l)).Debug().Layout.Layout; got != l {
+		t.Errorf("got layout %v, want %v", got, l)
+	}
+}
@@ -0,0 +1,75 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// WithRandomFallback keeps NextID available when the clock is further
+// behind than WithMaxBackwardTolerance allows. Instead of returning
+// ErrClockMovedBackwards, it issues IDs with the last timestamp it issued
+// and random machine and sequence bits drawn from the WithEntropy source.
+//
+// The top machine bit flags these degraded IDs, see IsDegraded, so the
+// generator's own machine ID must leave it clear; NewSnowflake returns nil
+// otherwise, and also without the backward tolerance the fallback relies
+// on. The bit is reserved fleet-wide: every machine sharing the ID space,
+// whether or not it enables the fallback, must use a machine ID below half
+// the machine range (512 with the default layout). A regular ID from a
+// higher machine ID looks degraded and can collide with a random one.
+// Degraded IDs are not ordered and, with the default layout's 21
+// random bits, a thousand of them have about a one in five chance of
+// containing a collision; choose this only if availability matters more
+// than strict uniqueness.
+func WithRandomFallback() Option {
+	return func(sf *Snowflake) {
+		sf.randomFallback = true
+	}
+}
+
+// IsDegraded reports if id was issued by the WithRandomFallback fallback.
+// It is only meaningful for generators using it.
+func (sf *Snowflake) IsDegraded(id uint64) bool {
+	_, mid, _ := sf.layout.Decompose(id)
+
+	return sf.layout.MachineBits > 0 && mid>>(sf.layout.MachineBits-1) == 1
+}
+
+// validRandomFallback checks the configuration once the machine ID is known.
+func (sf *Snowflake) validRandomFallback() bool {
+	return sf.checkBackward && sf.layout.MachineBits > 0 && !sf.IsDegraded(sf.layout.Compose(0, sf.machineID, 0))
+}
+
+// degradedID issues a fallback ID for a clock error err, or returns err if
+// the fallback is off or the entropy source fails. It must be called with
+// sf.mutex held.
+func (sf *Snowflake) degradedID(req *idRequest, err error) (uint64, error) {
+	if !sf.randomFallback || !errors.Is(err, ErrClockMovedBackwards) {
+		return 0, err
+	}
+
+	entropy := sf.entropy
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	var b [8]byte
+	if _, rerr := io.ReadFull(entropy, b[:]); rerr != nil {
+		return 0, err
+	}
+	r := binary.BigEndian.Uint64(b[:])
+
+	flag := uint64(1) << (sf.layout.MachineBits - 1)
+	mid := flag | r>>sf.layout.SequenceBits&(flag-1)
+	seq := r & sf.layout.MaxSequence()
+
+	sf.degraded.Add(1)
+	sf.record(1)
+	id := sf.layout.ComposeTagged(uint64(sf.lastTimestamp), req.tag, mid, seq)
+	if sf.journal != nil {
+		sf.addJournal(id, 1, req.label)
+	}
+
+	return id, nil
+}
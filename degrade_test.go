@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestRandomFallback(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 5, WithClock(clock), WithMaxBackwardTolerance(10*time.Millisecond), WithRandomFallback())
+
+	last, _ := sf.NextID()
+	if sf.IsDegraded(last) {
+		t.Fatalf("regular id %d reported as degraded", last)
+	}
+	lastTime := sf.IDToTime(last)
+
+	clock.Advance(-time.Second)
+	seen := map[uint64]bool{}
+	for i := 0; i < 100; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatalf("expected a degraded id, got %v", err)
+		}
+		if !sf.IsDegraded(id) {
+			t.Errorf("id %d is not flagged as degraded", id)
+		}
+		if !sf.IDToTime(id).Equal(lastTime) {
+			t.Errorf("degraded id %d has time %s, want %s", id, sf.IDToTime(id), lastTime)
+		}
+		seen[id] = true
+	}
+	if len(seen) < 95 {
+		t.Errorf("expected random ids, got %d distinct of 100", len(seen))
+	}
+	if st := sf.Stats(); st.Degraded != 100 || st.Issued != 101 {
+		t.Errorf("unexpected stats %+v", st)
+	}
+
+	// Small lags are still absorbed by waiting, and the clock recovering
+	// ends the fallback.
+	clock.Advance(time.Second + time.Millisecond)
+	if id, err := sf.NextID(); err != nil || sf.IsDegraded(id) || id <= last {
+		t.Errorf("expected a regular id after %d, got %d, %v", last, id, err)
+	}
+
+	if err := sf.SetMachineID(context.Background(), 512); !errors.Is(err, ErrInvalidMachineID) {
+		t.Errorf("expected the flag bit to be rejected, got %v", err)
+	}
+	if NewSnowflake(epoch, 512, WithClock(clock), WithMaxBackwardTolerance(time.Millisecond), WithRandomFallback()) != nil {
+		t.Error("expected nil for a machine id with the flag bit set")
+	}
+	if NewSnowflake(epoch, 5, WithClock(clock), WithRandomFallback()) != nil {
+		t.Error("expected nil without a backward tolerance")
+	}
+}
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// LayoutDescriptor is everything needed to build or parse the IDs of a
+// generator: its epoch, bit layout and time unit. It marshals to JSON so
+// services can publish their ID scheme in configuration or service
+// discovery metadata:
+//
+//	{"epoch":"2020-01-01T00:00:00Z","time_bits":42,"machine_bits":10,"sequence_bits":12,"time_unit":"1ms"}
+type LayoutDescriptor struct {
+	Epoch time.Time
+	Layout
+	TimeUnit time.Duration
+}
+
+type layoutDescriptorJSON struct {
+	Epoch time.Time `json:"epoch"`
+	Layout
+	TimeUnit string `json:"time_unit"`
+}
+
+// Descriptor returns the generator's LayoutDescriptor.
+func (sf *Snowflake) Descriptor() LayoutDescriptor {
+	return LayoutDescriptor{Epoch: sf.Epoch().UTC(), Layout: sf.layout, TimeUnit: sf.unit}
+}
+
+// Validate checks the layout and time unit.
+func (d LayoutDescriptor) Validate() error {
+	if err := d.Layout.Validate(); err != nil {
+		return err
+	}
+	if !validUnit(d.TimeUnit) {
+		return fmt.Errorf("%w: unsupported time unit %s", ErrInvalidLayout, d.TimeUnit)
+	}
+
+	return nil
+}
+
+func (d LayoutDescriptor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(layoutDescriptorJSON{Epoch: d.Epoch.UTC(), Layout: d.Layout, TimeUnit: d.TimeUnit.String()})
+}
+
+// UnmarshalJSON decodes and validates a descriptor. A missing time unit
+// means milliseconds.
+func (d *LayoutDescriptor) UnmarshalJSON(data []byte) error {
+	var v layoutDescriptorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	unit := snowflakeTimeUnit
+	if v.TimeUnit != "" {
+		var err error
+		if unit, err = time.ParseDuration(v.TimeUnit); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidLayout, err)
+		}
+	}
+
+	desc := LayoutDescriptor{Epoch: v.Epoch, Layout: v.Layout, TimeUnit: unit}
+	if err := desc.Validate(); err != nil {
+		return err
+	}
+	*d = desc
+
+	return nil
+}
+
+// NewFromLayout returns a generator for the scheme described by d. It
+// returns nil under the same conditions as NewSnowflake, including an
+// invalid descriptor. Options given after d may override it.
+func NewFromLayout(d LayoutDescriptor, machineID int, opts ...Option) *Snowflake {
+	all := make([]Option, 0, len(opts)+2)
+	all = append(all, WithLayout(d.Layout), WithTimeUnit(d.TimeUnit))
+	all = append(all, opts...)
+
+	return NewSnowflake(d.Epoch, machineID, all...)
+}
@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLayoutDescriptor(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sf := NewSnowflake(epoch, 5, WithLayout(InstagramLayout), WithTimeUnit(TimeUnit10Milliseconds))
+
+	data, err := json.Marshal(sf.Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"epoch":"2020-01-01T00:00:00Z","time_bits":41,"machine_bits":13,"sequence_bits":10,"time_unit":"10ms"}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	var d LayoutDescriptor
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if want := sf.Descriptor(); !d.Epoch.Equal(want.Epoch) || d.Layout != want.Layout || d.TimeUnit != want.TimeUnit {
+		t.Errorf("round trip gave %+v, want %+v", d, sf.Descriptor())
+	}
+
+	// A parser built from the descriptor decodes the generator's IDs.
+	parser := NewFromLayout(d, 0)
+	id, _ := sf.NextID()
+	if got, want := parser.Decompose(id), sf.Decompose(id); got.Time != want.Time || got.MachineID != 5 || got.Sequence != want.Sequence {
+		t.Errorf("parser decoded %+v, want %+v", got, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"epoch":"2020-01-01T00:00:00Z","time_bits":42,"machine_bits":10,"sequence_bits":12}`), &d); err != nil || d.TimeUnit != time.Millisecond {
+		t.Errorf("expected a missing unit to mean milliseconds, got %s, %v", d.TimeUnit, err)
+	}
+
+	for _, bad := range []string{
+		`{"epoch":"2020-01-01T00:00:00Z","time_bits":50,"machine_bits":10,"sequence_bits":12}`,
+		`{"epoch":"2020-01-01T00:00:00Z","time_bits":42,"machine_bits":10,"sequence_bits":12,"time_unit":"7ms"}`,
+		`{"epoch":"2020-01-01T00:00:00Z","time_bits":42,"machine_bits":10,"sequence_bits":12,"time_unit":"soon"}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &d); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("Unmarshal(%s) = %v, want ErrInvalidLayout", bad, err)
+		}
+	}
+
+	if NewFromLayout(LayoutDescriptor{Epoch: epoch, Layout: DefaultLayout, TimeUnit: 7 * time.Millisecond}, 1) != nil {
+		t.Error("expected nil for an invalid descriptor")
+	}
+}
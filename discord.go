@@ -0,0 +1,40 @@
+package snowflake
+
+import "time"
+
+// DiscordEpoch is the epoch of Discord snowflakes, the first second of 2015.
+var DiscordEpoch = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const discordProcessBits = 5
+
+// DiscordParts are the fields of a Discord snowflake.
+type DiscordParts struct {
+	Time      time.Time
+	WorkerID  uint64
+	ProcessID uint64
+	Increment uint64
+}
+
+// NewDiscord returns a generator for Discord-style snowflakes, which split
+// the 10 machine bits into a 5-bit worker ID and a 5-bit process ID. It
+// returns nil if either ID is out of range.
+func NewDiscord(workerID, processID int, opts ...Option) *Snowflake {
+	const maxID = 1<<discordProcessBits - 1
+	if workerID < 0 || workerID > maxID || processID < 0 || processID > maxID {
+		return nil
+	}
+
+	return NewSnowflake(DiscordEpoch, workerID<<discordProcessBits|processID, opts...)
+}
+
+// ParseDiscord decomposes a Discord snowflake.
+func ParseDiscord(id uint64) DiscordParts {
+	t, mid, seq := DefaultLayout.Decompose(id)
+
+	return DiscordParts{
+		Time:      DiscordEpoch.Add(time.Duration(t) * time.Millisecond),
+		WorkerID:  mid >> discordProcessBits,
+		ProcessID: mid & (1<<discordProcessBits - 1),
+		Increment: seq,
+	}
+}
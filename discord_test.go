@@ -0,0 +1,39 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDiscord(t *testing.T) {
+	// Example from the Discord API reference.
+	p := ParseDiscord(175928847299117063)
+
+	want := time.Date(2016, 4, 30, 11, 18, 25, 796e6, time.UTC)
+	if !p.Time.Equal(want) {
+		t.Errorf("time %s, want %s", p.Time, want)
+	}
+	if p.WorkerID != 1 || p.ProcessID != 0 || p.Increment != 7 {
+		t.Errorf("unexpected parts %+v", p)
+	}
+}
+
+func TestNewDiscord(t *testing.T) {
+	if NewDiscord(32, 0) != nil || NewDiscord(0, -1) != nil {
+		t.Error("out of range worker or process ids should be rejected")
+	}
+
+	sf := NewDiscord(3, 17)
+	id, err := sf.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := ParseDiscord(id)
+	if p.WorkerID != 3 || p.ProcessID != 17 {
+		t.Errorf("unexpected parts %+v", p)
+	}
+	if d := time.Since(p.Time); d < 0 || d > time.Minute {
+		t.Errorf("implausible time %s", p.Time)
+	}
+}
@@ -0,0 +1,19 @@
+// Package snowflake generates 64-bit, roughly time ordered unique IDs.
+//
+// The package depends on the standard library only and builds for
+// js/wasm and wasip1, so it can be embedded in minimal environments; see
+// BrowserClock for generators running in a browser.
+//
+// Integrations with third-party systems live in subpackages, which callers
+// import only when needed:
+//
+//   - snowflakeprom and snowflakeotel report metrics and traces.
+//   - snowflakehttp, snowflakegrpc and snowflakepb serve and transport IDs.
+//   - snowflakeredis issues IDs timed and sequenced by a Redis server.
+//   - snowflakeconsul, snowflakedynamo and snowflakecloud assign machine IDs.
+//   - snowflakepg, snowflakegorm and snowflakeent fill in database keys.
+//   - snowflakeaudit and snowflaketest check issued IDs.
+//
+// Instrumentation in this package goes through interfaces such as Metrics
+// and Hooks, which are no-ops unless configured.
+package snowflake
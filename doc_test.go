@@ -0,0 +1,22 @@
+package snowflake
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+)
+
+// TestStandardLibraryOnly keeps third-party imports out of the core
+// package; they belong in subpackages.
+func TestStandardLibraryOnly(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, imp := range append(pkg.Imports, pkg.TestImports...) {
+		first, _, _ := strings.Cut(imp, "/")
+		if strings.Contains(first, ".") && !strings.HasPrefix(imp, "github.com/fethican/snowflake-go/") {
+			t.Errorf("core package imports %s", imp)
+		}
+	}
+}
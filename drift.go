@@ -0,0 +1,128 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrClockUnsynced = errors.New("system clock is not synchronized")
+
+// NTPClient reports the offset of the local clock from a reference clock.
+// It is typically a thin wrapper around an NTP library query.
+type NTPClient interface {
+	Offset(ctx context.Context) (time.Duration, error)
+}
+
+// DriftMonitor periodically compares the local clock with an NTPClient and
+// flags the clock as unsynchronized while the offset exceeds a threshold.
+// Failed queries leave the previous verdict in place; the clock is assumed
+// to be synchronized until the first successful check says otherwise.
+type DriftMonitor struct {
+	client    NTPClient
+	threshold time.Duration
+	interval  time.Duration
+
+	unsynced atomic.Bool
+	offset   atomic.Int64
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// DefaultDriftInterval is the interval between checks used by
+// NewDriftMonitor when none is given.
+const DefaultDriftInterval = time.Minute
+
+// NewDriftMonitor returns a DriftMonitor flagging offsets beyond threshold,
+// checking every interval once started, or every DefaultDriftInterval if
+// interval is not positive.
+func NewDriftMonitor(client NTPClient, threshold, interval time.Duration) *DriftMonitor {
+	if interval <= 0 {
+		interval = DefaultDriftInterval
+	}
+
+	return &DriftMonitor{client: client, threshold: threshold, interval: interval}
+}
+
+// Check queries the client once and updates the verdict.
+func (m *DriftMonitor) Check(ctx context.Context) error {
+	offset, err := m.client.Offset(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.offset.Store(int64(offset))
+	m.unsynced.Store(offset > m.threshold || offset < -m.threshold)
+
+	return nil
+}
+
+// Synced reports whether the last successful check was within the threshold.
+func (m *DriftMonitor) Synced() bool {
+	return !m.unsynced.Load()
+}
+
+// Offset returns the offset measured by the last successful check.
+func (m *DriftMonitor) Offset() time.Duration {
+	return time.Duration(m.offset.Load())
+}
+
+// Start checks the clock immediately and then once per interval in a
+// background goroutine until Stop is called.
+func (m *DriftMonitor) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.run(m.stop, m.done)
+}
+
+// Stop ends the background checks and waits for the goroutine to exit.
+func (m *DriftMonitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	<-m.done
+	m.stop, m.done = nil, nil
+}
+
+func (m *DriftMonitor) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), m.interval)
+		m.Check(ctx)
+		cancel()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithDriftMonitor makes NextID and ReserveBlock return ErrClockUnsynced
+// while m reports the clock as unsynchronized. The monitor must be started
+// separately.
+func WithDriftMonitor(m *DriftMonitor) Option {
+	return func(sf *Snowflake) {
+		sf.drift = m
+	}
+}
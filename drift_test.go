@@ -0,0 +1,90 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeNTP struct {
+	offset atomic.Int64
+	calls  atomic.Int32
+	err    error
+}
+
+func (f *fakeNTP) Offset(context.Context) (time.Duration, error) {
+	f.calls.Add(1)
+	return time.Duration(f.offset.Load()), f.err
+}
+
+func TestDriftMonitor(t *testing.T) {
+	ctx := context.Background()
+	ntp := new(fakeNTP)
+	m := NewDriftMonitor(ntp, 50*time.Millisecond, time.Hour)
+	sf := NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1, WithDriftMonitor(m))
+
+	if _, err := sf.NextID(); err != nil {
+		t.Fatalf("clock should be assumed synced before the first check: %v", err)
+	}
+
+	ntp.offset.Store(int64(-80 * time.Millisecond))
+	m.Check(ctx)
+	if _, err := sf.NextID(); !errors.Is(err, ErrClockUnsynced) {
+		t.Errorf("expected ErrClockUnsynced, got %v", err)
+	}
+	if _, err := sf.ReserveBlock(1); !errors.Is(err, ErrClockUnsynced) {
+		t.Errorf("expected ErrClockUnsynced from ReserveBlock, got %v", err)
+	}
+
+	// A failing query keeps the last verdict.
+	ntp.err = errors.New("timeout")
+	m.Check(ctx)
+	if m.Synced() {
+		t.Error("failed check should not mark the clock as synced")
+	}
+
+	ntp.err = nil
+	ntp.offset.Store(int64(10 * time.Millisecond))
+	m.Check(ctx)
+	if _, err := sf.NextID(); err != nil {
+		t.Errorf("clock back within threshold, got %v", err)
+	}
+	if m.Offset() != 10*time.Millisecond {
+		t.Errorf("unexpected offset %s", m.Offset())
+	}
+}
+
+func TestDriftMonitorStartStop(t *testing.T) {
+	ntp := new(fakeNTP)
+	m := NewDriftMonitor(ntp, time.Millisecond, time.Millisecond)
+
+	m.Start()
+	m.Start()
+	time.Sleep(20 * time.Millisecond)
+	m.Stop()
+	m.Stop()
+
+	calls := ntp.calls.Load()
+	if calls == 0 {
+		t.Fatal("monitor never checked the clock")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if ntp.calls.Load() != calls {
+		t.Error("monitor kept checking after Stop")
+	}
+}
+
+func TestDriftMonitorDefaultInterval(t *testing.T) {
+	ntp := new(fakeNTP)
+	m := NewDriftMonitor(ntp, time.Millisecond, 0)
+
+	// A zero interval would make the ticker panic.
+	m.Start()
+	m.Stop()
+
+	if ntp.calls.Load() != 1 {
+		t.Errorf("expected the immediate check only, got %d", ntp.calls.Load())
+	}
+}
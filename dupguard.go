@@ -0,0 +1,166 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+)
+
+var ErrDuplicateID = errors.New("id already issued")
+
+// DuplicatePolicy decides what NextID does with an ID the duplicate guard
+// has seen before.
+type DuplicatePolicy int
+
+const (
+	// DuplicateSkip makes NextID move on to the next sequence number. After
+	// maxDuplicateSkips suspected duplicates in a row, which false positives
+	// alone practically never cause, it returns ErrDuplicateID.
+	DuplicateSkip DuplicatePolicy = iota
+	// DuplicateReport makes NextID return the ID anyway; the duplicate is
+	// only counted and reported.
+	DuplicateReport
+)
+
+const maxDuplicateSkips = 16
+
+// Bits per ID and hash functions of each filter, for a false positive rate
+// of about 0.1% with a full filter.
+const (
+	guardBitsPerID = 15
+	guardHashes    = 10
+)
+
+// duplicateGuard is a pair of bloom filters; IDs are added to current and
+// looked up in both. When current holds window IDs it replaces previous,
+// so the guard remembers between window and 2*window of the latest IDs.
+// It is guarded by the generator lock.
+type duplicateGuard struct {
+	current, previous []uint64
+	count, window     int
+	policy            DuplicatePolicy
+	onDuplicate       func(id uint64)
+	hits              uint64
+}
+
+// WithDuplicateGuard makes NextID check every ID against the last window to
+// 2*window IDs it issued, kept in rotating bloom filters of about 4 bytes
+// per ID. This catches duplicates caused by the clock or by restoring stale
+// state at the source; it cannot see IDs issued by other processes. IDs
+// from ReserveBlock are not checked.
+//
+// onDuplicate, if not nil, is called with every suspected duplicate while
+// the generator lock is held. Bloom filters have false positives, about one
+// in a thousand lookups when the filter is full, so a report is a suspicion
+// rather than proof. NewSnowflake returns nil if window is not positive.
+func WithDuplicateGuard(window int, policy DuplicatePolicy, onDuplicate func(id uint64)) Option {
+	return func(sf *Snowflake) {
+		words := (window*guardBitsPerID + 63) / 64
+		sf.guard = &duplicateGuard{
+			window:      window,
+			policy:      policy,
+			onDuplicate: onDuplicate,
+		}
+		if window > 0 {
+			sf.guard.current = make([]uint64, words)
+			sf.guard.previous = make([]uint64, words)
+		}
+	}
+}
+
+// SuspectedDuplicates returns the number of IDs the duplicate guard has
+// reported, including false positives.
+func (sf *Snowflake) SuspectedDuplicates() uint64 {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	if sf.guard == nil {
+		return 0
+	}
+
+	return sf.guard.hits
+}
+
+// guardDuplicate returns id, or with DuplicateSkip the first following ID
+// the guard has not seen, and remembers it. It must be called with
+// sf.mutex held.
+func (sf *Snowflake) guardDuplicate(ctx context.Context, req *idRequest, id uint64) (uint64, error) {
+	g := sf.guard
+
+	for skips := 0; g.contains(id); skips++ {
+		g.hits++
+		if g.onDuplicate != nil {
+			g.onDuplicate(id)
+		}
+		if g.policy == DuplicateReport {
+			break
+		}
+		if skips == maxDuplicateSkips {
+			return 0, sf.generationError(ErrDuplicateID)
+		}
+
+		currentTimestamp := sf.observe()
+		if req.noWait && sf.mustWait(currentTimestamp) {
+			return 0, errWouldBlock
+		}
+		if sf.maxBorrow != 0 && sf.exhausted(currentTimestamp) {
+			if err := sf.checkBorrow(currentTimestamp); err != nil {
+				return 0, err
+			}
+		}
+		if err := sf.advance(ctx, req, currentTimestamp); err != nil {
+			return 0, err
+		}
+		if uint64(sf.lastTimestamp) > sf.layout.MaxTimestamp() {
+			return 0, sf.generationError(ErrEpochExhausted)
+		}
+		id = sf.layout.ComposeTagged(uint64(sf.lastTimestamp), req.tag, sf.machineID, uint64(sf.sequence))
+	}
+	g.add(id)
+
+	return id, nil
+}
+
+func (g *duplicateGuard) contains(id uint64) bool {
+	return bloomContains(g.current, id) || bloomContains(g.previous, id)
+}
+
+func (g *duplicateGuard) add(id uint64) {
+	if g.count == g.window {
+		g.current, g.previous = g.previous, g.current
+		clear(g.current)
+		g.count = 0
+	}
+	g.count++
+
+	h1, h2 := guardHash(id)
+	n := uint64(len(g.current)) * 64
+	for i := uint64(0); i < guardHashes; i++ {
+		b := (h1 + i*h2) % n
+		g.current[b/64] |= 1 << (b % 64)
+	}
+}
+
+func bloomContains(filter []uint64, id uint64) bool {
+	h1, h2 := guardHash(id)
+	n := uint64(len(filter)) * 64
+	for i := uint64(0); i < guardHashes; i++ {
+		b := (h1 + i*h2) % n
+		if filter[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// guardHash derives two hashes from id with the splitmix64 finalizer; IDs
+// themselves are far too regular to index the filter directly.
+func guardHash(id uint64) (uint64, uint64) {
+	z := id + 0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	z ^= z >> 31
+
+	return z, bits.RotateLeft64(z, 32) | 1
+}
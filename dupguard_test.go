@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestDuplicateGuard(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, policy := range []DuplicatePolicy{DuplicateSkip, DuplicateReport} {
+		clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+		var reported []uint64
+		sf := NewSnowflake(epoch, 1, WithClock(clock), WithDuplicateGuard(1000, policy, func(id uint64) {
+			reported = append(reported, id)
+		}))
+
+		issued := make(map[uint64]bool)
+		for i := 0; i < 10; i++ {
+			id, _ := sf.NextID()
+			issued[id] = true
+		}
+
+		// Corrupt the state as a faulty restore would: the sequence
+		// restarts within the same tick.
+		sf.sequence, sf.seqIndex = 0, 0
+
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch policy {
+		case DuplicateSkip:
+			if issued[id] || sf.SuspectedDuplicates() != 9 || len(reported) != 9 {
+				t.Errorf("skip: got id %d (reissued: %v) after %d reports", id, issued[id], sf.SuspectedDuplicates())
+			}
+		case DuplicateReport:
+			if !issued[id] || sf.SuspectedDuplicates() != 1 || len(reported) != 1 || reported[0] != id {
+				t.Errorf("report: got id %d (reissued: %v), reported %v", id, issued[id], reported)
+			}
+		}
+	}
+}
+
+func TestDuplicateGuardGivesUp(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithDuplicateGuard(1000, DuplicateSkip, nil))
+
+	for i := 0; i < 100; i++ {
+		sf.NextID()
+	}
+	sf.sequence, sf.seqIndex = 0, 0
+
+	if _, err := sf.NextID(); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("expected ErrDuplicateID after a long run of duplicates, got %v", err)
+	}
+}
+
+func TestDuplicateGuardFalsePositives(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithDuplicateGuard(20000, DuplicateReport, nil))
+
+	const n = 100000
+	for i := 0; i < n; i++ {
+		if _, err := sf.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if fp := sf.SuspectedDuplicates(); fp > n/200 {
+		t.Errorf("%d false positives in %d ids", fp, n)
+	}
+
+	if NewSnowflake(epoch, 1, WithDuplicateGuard(0, DuplicateSkip, nil)) != nil {
+		t.Error("expected nil for an empty window")
+	}
+}
+
+func BenchmarkNextIDDuplicateGuard(b *testing.B) {
+	sf := NewSnowflake(time.Now(), 34, WithDuplicateGuard(1<<20, DuplicateSkip, nil))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sf.NextID()
+	}
+}
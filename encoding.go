@@ -0,0 +1,214 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidEncoding = errors.New("invalid id encoding")
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Base62 encodes id with the digits 0-9, A-Z, a-z.
+func (id ID) Base62() string {
+	var buf [11]byte
+
+	return string(id.AppendBase62(buf[:0]))
+}
+
+// AppendBase62 appends the Base62 form of id to dst. It does not allocate
+// if dst has room for 11 more bytes.
+func (id ID) AppendBase62(dst []byte) []byte {
+	if id == 0 {
+		return append(dst, '0')
+	}
+
+	var buf [11]byte
+	i := len(buf)
+	for n := uint64(id); n > 0; n /= 62 {
+		i--
+		buf[i] = base62Alphabet[n%62]
+	}
+
+	return append(dst, buf[i:]...)
+}
+
+// ParseBase62 decodes an ID encoded by ID.Base62.
+func ParseBase62(s string) (ID, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty base62 string", ErrInvalidEncoding)
+	}
+
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := base62Digit(s[i])
+		if d < 0 {
+			return 0, fmt.Errorf("%w: invalid base62 digit %q", ErrInvalidEncoding, s[i])
+		}
+		if n > (1<<64-1-uint64(d))/62 {
+			return 0, fmt.Errorf("%w: base62 value out of range", ErrInvalidEncoding)
+		}
+		n = n*62 + uint64(d)
+	}
+
+	return ID(n), nil
+}
+
+func base62Digit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 36
+	}
+
+	return -1
+}
+
+// Bytes returns id in big-endian order, so that byte-wise comparison of the
+// results orders IDs like their numeric values.
+func (id ID) Bytes() [8]byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+
+	return b
+}
+
+// FromBytes decodes an ID from the 8 big-endian bytes returned by ID.Bytes.
+func FromBytes(b []byte) (ID, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("%w: need 8 bytes, got %d", ErrInvalidEncoding, len(b))
+	}
+
+	return ID(binary.BigEndian.Uint64(b)), nil
+}
+
+// Hex returns id as 16 lower-case hex digits. The fixed width keeps the
+// strings in the same order as the IDs.
+func (id ID) Hex() string {
+	var buf [16]byte
+
+	return string(id.AppendHex(buf[:0]))
+}
+
+// AppendHex appends the Hex form of id to dst.
+func (id ID) AppendHex(dst []byte) []byte {
+	b := id.Bytes()
+
+	return hex.AppendEncode(dst, b[:])
+}
+
+// ParseHex decodes an ID from up to 16 hex digits of either case, as
+// returned by ID.Hex or without leading zeros.
+func ParseHex(s string) (ID, error) {
+	if s == "" || len(s) > 16 {
+		return 0, fmt.Errorf("%w: hex id must have 1 to 16 digits", ErrInvalidEncoding)
+	}
+
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c -= 'a' - 10
+		case c >= 'A' && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, fmt.Errorf("%w: invalid hex digit %q", ErrInvalidEncoding, s[i])
+		}
+		n = n<<4 | uint64(c)
+	}
+
+	return ID(n), nil
+}
+
+// StringPadded returns id as a 20-digit zero-padded decimal, so that the
+// strings sort like the IDs, e.g. as object keys or string sort keys.
+func (id ID) StringPadded() string {
+	var buf [20]byte
+
+	return string(id.AppendPadded(buf[:0]))
+}
+
+// AppendPadded appends the StringPadded form of id to dst.
+func (id ID) AppendPadded(dst []byte) []byte {
+	var buf [20]byte
+	n := uint64(id)
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = byte('0' + n%10)
+		n /= 10
+	}
+
+	return append(dst, buf[:]...)
+}
+
+// ParsePadded decodes an ID from exactly 20 decimal digits as returned by
+// ID.StringPadded.
+func ParsePadded(s string) (ID, error) {
+	if len(s) != 20 {
+		return 0, fmt.Errorf("%w: padded id must have 20 digits", ErrInvalidEncoding)
+	}
+
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("%w: invalid decimal digit %q", ErrInvalidEncoding, s[i])
+		}
+		d := uint64(s[i] - '0')
+		if n > (1<<64-1-d)/10 {
+			return 0, fmt.Errorf("%w: decimal value out of range", ErrInvalidEncoding)
+		}
+		n = n*10 + d
+	}
+
+	return ID(n), nil
+}
+
+// Base32 returns id as 13 upper-case Crockford base32 digits. The fixed
+// width keeps the strings in the same order as the IDs.
+func (id ID) Base32() string {
+	var buf [13]byte
+
+	return string(id.AppendBase32(buf[:0]))
+}
+
+// AppendBase32 appends the Base32 form of id to dst.
+func (id ID) AppendBase32(dst []byte) []byte {
+	var buf [13]byte
+	n := uint64(id)
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[n&31]
+		n >>= 5
+	}
+
+	return append(dst, buf[:]...)
+}
+
+// ParseBase32 decodes an ID from 1 to 13 Crockford base32 digits of either
+// case, as returned by ID.Base32 or without leading zeros.
+func ParseBase32(s string) (ID, error) {
+	if s == "" || len(s) > 13 {
+		return 0, fmt.Errorf("%w: base32 id must have 1 to 13 digits", ErrInvalidEncoding)
+	}
+
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(crockfordAlphabet, upper(s[i]))
+		if d < 0 {
+			return 0, fmt.Errorf("%w: invalid base32 digit %q", ErrInvalidEncoding, s[i])
+		}
+		if n>>59 != 0 {
+			return 0, fmt.Errorf("%w: base32 value out of range", ErrInvalidEncoding)
+		}
+		n = n<<5 | uint64(d)
+	}
+
+	return ID(n), nil
+}
@@ -0,0 +1,181 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBase62(t *testing.T) {
+	for _, id := range []ID{0, 1, 61, 62, 123456789, 1<<63 + 12345, 1<<64 - 1} {
+		s := id.Base62()
+
+		got, err := ParseBase62(s)
+		if err != nil {
+			t.Fatalf("ParseBase62(%q): %v", s, err)
+		}
+		if got != id {
+			t.Errorf("round trip of %d through %q returned %d", id, s, got)
+		}
+	}
+
+	if ID(1<<64-1).Base62() != "LygHa16AHYF" {
+		t.Errorf("unexpected encoding of max uint64: %s", ID(1<<64-1).Base62())
+	}
+
+	for _, s := range []string{"", "abc-", "LygHa16AHYG"} {
+		if _, err := ParseBase62(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("ParseBase62(%q) should fail, got %v", s, err)
+		}
+	}
+}
+
+func TestBytesAndHex(t *testing.T) {
+	ids := []ID{0, 1, 255, 256, 1<<32 + 7, 1<<63 + 12345, 1<<64 - 1}
+
+	for i, id := range ids {
+		b := id.Bytes()
+		got, err := FromBytes(b[:])
+		if err != nil || got != id {
+			t.Errorf("FromBytes(%x) = %d, %v, want %d", b, got, err, id)
+		}
+
+		h := id.Hex()
+		if len(h) != 16 {
+			t.Errorf("expected 16 hex digits, got %q", h)
+		}
+		if got, err := ParseHex(h); err != nil || got != id {
+			t.Errorf("ParseHex(%q) = %d, %v, want %d", h, got, err, id)
+		}
+
+		if i > 0 {
+			prev := ids[i-1].Bytes()
+			if bytes.Compare(prev[:], b[:]) >= 0 || ids[i-1].Hex() >= h {
+				t.Errorf("encodings of %d and %d are out of order", ids[i-1], id)
+			}
+		}
+	}
+
+	if got, err := ParseHex("DeadBeef"); err != nil || got != 0xdeadbeef {
+		t.Errorf("ParseHex(DeadBeef) = %x, %v", got, err)
+	}
+	for _, s := range []string{"", "0x1", "12345678901234567"} {
+		if _, err := ParseHex(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("ParseHex(%q) should fail, got %v", s, err)
+		}
+	}
+	if _, err := FromBytes([]byte{1, 2, 3}); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("FromBytes of 3 bytes should fail, got %v", err)
+	}
+}
+
+func TestStringPadded(t *testing.T) {
+	ids := []ID{0, 9, 10, 123456789, 1<<63 + 12345, 1<<64 - 1}
+
+	for i, id := range ids {
+		s := id.StringPadded()
+		if len(s) != 20 {
+			t.Errorf("expected 20 digits, got %q", s)
+		}
+		if got, err := ParsePadded(s); err != nil || got != id {
+			t.Errorf("ParsePadded(%q) = %d, %v, want %d", s, got, err, id)
+		}
+		if i > 0 && ids[i-1].StringPadded() >= s {
+			t.Errorf("%q does not sort after %q", s, ids[i-1].StringPadded())
+		}
+	}
+
+	if got := ID(42).StringPadded(); got != "00000000000000000042" {
+		t.Errorf("got %q", got)
+	}
+	for _, s := range []string{"42", "+0000000000000000042", "18446744073709551616", "0000000000000000004x"} {
+		if _, err := ParsePadded(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("ParsePadded(%q) should fail, got %v", s, err)
+		}
+	}
+}
+
+func TestBase32(t *testing.T) {
+	ids := []ID{0, 1, 31, 32, 1<<32 + 7, 1<<63 + 12345, 1<<64 - 1}
+
+	for i, id := range ids {
+		s := id.Base32()
+		if len(s) != 13 {
+			t.Errorf("expected 13 base32 digits, got %q", s)
+		}
+		if got, err := ParseBase32(strings.ToLower(s)); err != nil || got != id {
+			t.Errorf("ParseBase32(%q) = %d, %v, want %d", s, got, err, id)
+		}
+		if i > 0 && ids[i-1].Base32() >= s {
+			t.Errorf("encodings of %d and %d are out of order", ids[i-1], id)
+		}
+	}
+
+	for _, s := range []string{"", "0U", "00000000000000", "G000000000000"} {
+		if _, err := ParseBase32(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("ParseBase32(%q) should fail, got %v", s, err)
+		}
+	}
+}
+
+var sinkString string
+
+func TestEncodingAllocs(t *testing.T) {
+	id := ID(1<<63 + 12345)
+	buf := make([]byte, 0, 32)
+
+	tests := []struct {
+		name   string
+		f      func()
+		allocs float64
+	}{
+		{"AppendBase62", func() { buf = id.AppendBase62(buf[:0]) }, 0},
+		{"AppendBase32", func() { buf = id.AppendBase32(buf[:0]) }, 0},
+		{"AppendHex", func() { buf = id.AppendHex(buf[:0]) }, 0},
+		{"AppendPadded", func() { buf = id.AppendPadded(buf[:0]) }, 0},
+		{"ParseBase62", func() { _, _ = ParseBase62("LygHa16AHYF") }, 0},
+		{"ParseHex", func() { _, _ = ParseHex("deadbeef") }, 0},
+		{"ParseAny", func() { _, _, _ = ParseAny("9223372036854788153") }, 0},
+		// The string forms allocate only the returned string.
+		{"Base62", func() { sinkString = id.Base62() }, 1},
+		{"Base32", func() { sinkString = id.Base32() }, 1},
+		{"Hex", func() { sinkString = id.Hex() }, 1},
+		{"StringPadded", func() { sinkString = id.StringPadded() }, 1},
+	}
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(100, tt.f); got != tt.allocs {
+			t.Errorf("%s: %v allocations, want %v", tt.name, got, tt.allocs)
+		}
+	}
+}
+
+func BenchmarkEncoding(b *testing.B) {
+	id := ID(1<<63 + 12345)
+	buf := make([]byte, 0, 32)
+
+	b.Run("AppendBase62", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			buf = id.AppendBase62(buf[:0])
+		}
+	})
+	b.Run("Base62", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			sinkString = id.Base62()
+		}
+	})
+	b.Run("Hex", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			sinkString = id.Hex()
+		}
+	})
+	b.Run("ParseAny", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _, _ = ParseAny("9223372036854788153")
+		}
+	})
+}
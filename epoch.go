@@ -0,0 +1,134 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrEpochExists   = errors.New("epoch already registered")
+	ErrEpochOverflow = errors.New("time cannot be represented under epoch")
+	ErrEpochHeadroom = errors.New("epoch leaves too little headroom")
+)
+
+// Epoch is a named reference time that ID timestamps count from.
+type Epoch struct {
+	Name  string
+	Start time.Time
+}
+
+var (
+	// TwitterEpoch is the epoch of Twitter snowflakes, whose layout matches
+	// DefaultLayout.
+	TwitterEpoch = Epoch{Name: "twitter", Start: time.UnixMilli(1288834974657).UTC()}
+
+	epochsMu sync.RWMutex
+	epochs   = map[string]Epoch{
+		"default":         {Name: "default", Start: epochStart},
+		TwitterEpoch.Name: TwitterEpoch,
+		"discord":         {Name: "discord", Start: DiscordEpoch},
+	}
+)
+
+// RegisterEpoch makes e available to LookupEpoch. Registering the same name
+// twice with a different start time returns ErrEpochExists.
+func RegisterEpoch(e Epoch) error {
+	epochsMu.Lock()
+	defer epochsMu.Unlock()
+
+	if old, ok := epochs[e.Name]; ok && !old.Start.Equal(e.Start) {
+		return fmt.Errorf("%w: %q", ErrEpochExists, e.Name)
+	}
+	epochs[e.Name] = e
+
+	return nil
+}
+
+// LookupEpoch returns the epoch registered under name. "default", "twitter"
+// and "discord" are always registered.
+func LookupEpoch(name string) (Epoch, bool) {
+	epochsMu.RLock()
+	defer epochsMu.RUnlock()
+
+	e, ok := epochs[name]
+
+	return e, ok
+}
+
+// Parse attaches e to id, which must be in DefaultLayout.
+func (e Epoch) Parse(id ID) ParsedID {
+	return ParsedID{ID: id, Epoch: e}
+}
+
+// ParsedID is an ID together with the epoch it was generated under.
+type ParsedID struct {
+	ID    ID
+	Epoch Epoch
+}
+
+// Time returns the time embedded in the ID under its epoch.
+func (p ParsedID) Time() time.Time {
+	t, _, _ := DefaultLayout.Decompose(uint64(p.ID))
+
+	return p.Epoch.Start.Add(time.Duration(t) * snowflakeTimeUnit)
+}
+
+// In re-encodes the ID under epoch to, keeping its time, machine ID and
+// sequence. It returns ErrEpochOverflow if the time is before to.Start or
+// too far after it for the timestamp field.
+func (p ParsedID) In(to Epoch) (ID, error) {
+	_, mid, seq := DefaultLayout.Decompose(uint64(p.ID))
+
+	ts := timeToSnowflakeUnit(p.Time()) - timeToSnowflakeUnit(to.Start)
+	if ts < 0 || uint64(ts) > DefaultLayout.MaxTimestamp() {
+		return 0, fmt.Errorf("%w: %s under %q", ErrEpochOverflow, p.Time().UTC(), to.Name)
+	}
+
+	return ID(DefaultLayout.Compose(uint64(ts), mid, seq)), nil
+}
+
+// RecommendedEpoch returns the start of the current year in UTC, the latest
+// round epoch, which leaves a new system nearly the full lifetime of its
+// layout. Passing a zero time to NewSnowflake instead selects the 2019
+// package default.
+//
+// An epoch must never change once IDs were issued under it: call
+// RecommendedEpoch when setting up a system and hard-code the result.
+func RecommendedEpoch() time.Time {
+	return recommendedEpoch(time.Now())
+}
+
+func recommendedEpoch(now time.Time) time.Time {
+	return time.Date(now.UTC().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// EpochUntil returns RecommendedEpoch if IDs in DefaultLayout issued under
+// it last until end, and ErrEpochHeadroom otherwise.
+func EpochUntil(end time.Time) (time.Time, error) {
+	return epochUntil(time.Now(), end)
+}
+
+func epochUntil(now, end time.Time) (time.Time, error) {
+	epoch := recommendedEpoch(now)
+	if err := DefaultLayout.checkHeadroom(epoch, snowflakeTimeUnit, end); err != nil {
+		return time.Time{}, err
+	}
+
+	return epoch, nil
+}
+
+// CheckHeadroom returns ErrEpochHeadroom unless IDs in l with ticks of unit
+// issued under epoch last for at least the given number of years from now.
+func (l Layout) CheckHeadroom(epoch time.Time, unit time.Duration, years int) error {
+	return l.checkHeadroom(epoch, unit, time.Now().AddDate(years, 0, 0))
+}
+
+func (l Layout) checkHeadroom(epoch time.Time, unit time.Duration, end time.Time) error {
+	if last := epoch.Add(l.Lifetime(unit)); last.Before(end) {
+		return fmt.Errorf("%w: IDs run out in %d, before %d", ErrEpochHeadroom, last.Year(), end.Year())
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEpochRegistry(t *testing.T) {
+	twitter, ok := LookupEpoch("twitter")
+	if !ok || twitter != TwitterEpoch {
+		t.Fatal("twitter epoch should be registered")
+	}
+
+	custom := Epoch{Name: "custom", Start: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := RegisterEpoch(custom); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterEpoch(custom); err != nil {
+		t.Errorf("registering the same epoch twice should be allowed, got %v", err)
+	}
+	if err := RegisterEpoch(Epoch{Name: "custom"}); !errors.Is(err, ErrEpochExists) {
+		t.Errorf("expected ErrEpochExists, got %v", err)
+	}
+	if got, _ := LookupEpoch("custom"); got != custom {
+		t.Errorf("LookupEpoch returned %+v", got)
+	}
+}
+
+func TestParsedIDIn(t *testing.T) {
+	// A tweet id; (id >> 22) + 1288834974657 = 1539202764211 ms.
+	p := TwitterEpoch.Parse(1050118621198921728)
+
+	want := time.Date(2018, 10, 10, 20, 19, 24, 211e6, time.UTC)
+	if !p.Time().Equal(want) {
+		t.Fatalf("time %s, want %s", p.Time(), want)
+	}
+
+	discord, _ := LookupEpoch("discord")
+	id, err := p.In(discord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back := discord.Parse(id)
+	if !back.Time().Equal(want) || id.Machine() != p.ID.Machine() || id.Sequence() != p.ID.Sequence() {
+		t.Errorf("re-encoded id %d lost information", id)
+	}
+
+	later := Epoch{Name: "later", Start: want.Add(time.Hour)}
+	if _, err := p.In(later); !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("expected ErrEpochOverflow, got %v", err)
+	}
+}
+
+func TestRecommendedEpoch(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.FixedZone("", -10*3600))
+
+	if got := recommendedEpoch(now); !got.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("recommendedEpoch = %s", got)
+	}
+	if got := RecommendedEpoch(); got.After(time.Now()) || time.Since(got) > 366*24*time.Hour {
+		t.Errorf("RecommendedEpoch = %s", got)
+	}
+
+	if _, err := epochUntil(now, time.Date(2150, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("42 time bits should last until 2150: %v", err)
+	}
+	if _, err := epochUntil(now, time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrEpochHeadroom) {
+		t.Errorf("expected ErrEpochHeadroom, got %v", err)
+	}
+
+	// The 2019 default leaves more than a century with DefaultLayout...
+	if err := DefaultLayout.CheckHeadroom(epochStart, time.Millisecond, 100); err != nil {
+		t.Error(err)
+	}
+	// ...but only about 34 years with 40 time bits.
+	small := Layout{TimeBits: 40, MachineBits: 10, SequenceBits: 12}
+	if err := small.CheckHeadroom(epochStart, time.Millisecond, 50); !errors.Is(err, ErrEpochHeadroom) {
+		t.Errorf("expected ErrEpochHeadroom, got %v", err)
+	}
+	if err := small.CheckHeadroom(epochStart, 10*time.Millisecond, 50); err != nil {
+		t.Errorf("10ms ticks should last 348 years: %v", err)
+	}
+}
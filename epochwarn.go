@@ -0,0 +1,39 @@
+package snowflake
+
+import "time"
+
+type epochThreshold struct {
+	fraction float64
+	tick     int64 // set by NewSnowflake once the layout is known
+	fn       func(remaining time.Duration)
+	fired    bool
+}
+
+// OnEpochThreshold calls fn once, on its own goroutine, when the generator
+// issues an ID after fraction (between 0 and 1) of its timestamp range has
+// been used. fn receives the time left until the range runs out. It may be
+// given several times for several thresholds. NewSnowflake returns nil if
+// fraction is outside [0, 1].
+func OnEpochThreshold(fraction float64, fn func(remaining time.Duration)) Option {
+	return func(sf *Snowflake) {
+		sf.thresholds = append(sf.thresholds, &epochThreshold{fraction: fraction, fn: fn})
+	}
+}
+
+// RemainingEpoch returns the time left until the generator's timestamp
+// range runs out, or 0 if it already has.
+func (sf *Snowflake) RemainingEpoch() time.Duration {
+	end := sf.Epoch().Add(sf.layout.Lifetime(sf.unit))
+
+	return max(end.Sub(sf.clock.Now()), 0)
+}
+
+// checkEpochThresholds must be called with sf.mutex held.
+func (sf *Snowflake) checkEpochThresholds() {
+	for _, th := range sf.thresholds {
+		if !th.fired && sf.lastTimestamp >= th.tick {
+			th.fired = true
+			go th.fn(sf.RemainingEpoch())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestEpochThreshold(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	layout := Layout{TimeBits: 20, MachineBits: 10, SequenceBits: 12} // about 17.5 minutes
+	lifetime := layout.Lifetime(time.Millisecond)
+	clock := clocktest.NewFakeClock(epoch.Add(lifetime / 2))
+
+	warned := make(chan time.Duration, 2)
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithLayout(layout),
+		OnEpochThreshold(0.9, func(remaining time.Duration) { warned <- remaining }))
+
+	if got, want := sf.RemainingEpoch(), lifetime/2; got != want {
+		t.Errorf("got remaining %s, want %s", got, want)
+	}
+
+	sf.NextID()
+	select {
+	case <-warned:
+		t.Fatal("warned before the threshold")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Set(epoch.Add(lifetime * 95 / 100))
+	sf.NextID()
+	sf.NextID()
+
+	select {
+	case remaining := <-warned:
+		if want := lifetime - lifetime*95/100; remaining != want {
+			t.Errorf("got remaining %s, want %s", remaining, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no warning after the threshold")
+	}
+	select {
+	case <-warned:
+		t.Error("warned twice")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Set(epoch.Add(lifetime + time.Hour))
+	if got := sf.RemainingEpoch(); got != 0 {
+		t.Errorf("expected no time left, got %s", got)
+	}
+
+	if NewSnowflake(epoch, 1, OnEpochThreshold(1.5, func(time.Duration) {})) != nil {
+		t.Error("expected a fraction above 1 to be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned when an ID cannot be generated. Together with
+// ErrClockMovedBackwards, ErrClockUnsynced, ErrRateLimited,
+// ErrInvariantViolation and ErrClosed they cover every failure of NextID and
+// ReserveBlock; the generator never panics. Failures tied to a point in time
+// are returned as a *GenerationError wrapping one of them.
+var (
+	ErrSequenceExhausted = errors.New("sequence exhausted")
+	ErrEpochExhausted    = errors.New("maximum timestamp has been reached")
+	ErrInvalidMachineID  = errors.New("invalid machine id")
+)
+
+// ErrPartRange is returned by Compose for parts that do not fit the layout.
+var ErrPartRange = errors.New("id part out of range")
+
+// GenerationError describes a failure to generate an ID. errors.Is matches
+// it against the error it wraps, e.g. ErrEpochExhausted.
+type GenerationError struct {
+	Err       error
+	MachineID uint64
+	// Timestamp is the tick, counted from the epoch, the generator was at.
+	Timestamp int64
+}
+
+func (e *GenerationError) Error() string {
+	return fmt.Sprintf("%v (machine id %d, timestamp %d)", e.Err, e.MachineID, e.Timestamp)
+}
+
+func (e *GenerationError) Unwrap() error {
+	return e.Err
+}
+
+// generationError must be called with sf.mutex held.
+func (sf *Snowflake) generationError(err error) error {
+	return &GenerationError{Err: err, MachineID: sf.machineID, Timestamp: sf.lastTimestamp}
+}
+
+// ValidateMachineID returns ErrInvalidMachineID if machineID does not fit
+// the layout. NewSnowflake returns nil for such machine IDs.
+func (l Layout) ValidateMachineID(machineID int) error {
+	if machineID < 0 || uint64(machineID) > l.MaxMachineID() {
+		return fmt.Errorf("%w: %d is not between 0 and %d", ErrInvalidMachineID, machineID, l.MaxMachineID())
+	}
+
+	return nil
+}
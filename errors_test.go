@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestGenerationError(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	// Start in the last tick, then move past it.
+	sf := NewSnowflake(epoch, 7, WithClock(clock), WithTimeOffset(DefaultLayout.Lifetime(time.Millisecond)-time.Hour-time.Millisecond))
+	clock.Advance(time.Millisecond)
+	_, err := sf.NextID()
+	if !errors.Is(err, ErrEpochExhausted) {
+		t.Fatalf("expected ErrEpochExhausted, got %v", err)
+	}
+	var ge *GenerationError
+	if !errors.As(err, &ge) || ge.MachineID != 7 || uint64(ge.Timestamp) != DefaultLayout.MaxTimestamp()+1 {
+		t.Errorf("unexpected error fields %+v", ge)
+	}
+	if _, err := sf.ReserveBlock(4); !errors.Is(err, ErrEpochExhausted) {
+		t.Errorf("expected ErrEpochExhausted from ReserveBlock, got %v", err)
+	}
+
+	sf = NewSnowflake(epoch, 3, WithClock(clock), WithMaxBackwardTolerance(time.Millisecond))
+	sf.NextID()
+	clock.Advance(-time.Second)
+	_, err = sf.NextID()
+	if !errors.Is(err, ErrClockMovedBackwards) || !errors.As(err, &ge) || ge.MachineID != 3 {
+		t.Errorf("expected a GenerationError wrapping ErrClockMovedBackwards, got %v", err)
+	}
+}
+
+func TestValidateMachineID(t *testing.T) {
+	for _, mid := range []int{0, 1, 1023} {
+		if err := DefaultLayout.ValidateMachineID(mid); err != nil {
+			t.Errorf("ValidateMachineID(%d) = %v", mid, err)
+		}
+	}
+	for _, mid := range []int{-1, 1024} {
+		if err := DefaultLayout.ValidateMachineID(mid); !errors.Is(err, ErrInvalidMachineID) {
+			t.Errorf("ValidateMachineID(%d) = %v, want ErrInvalidMachineID", mid, err)
+		}
+		if NewSnowflake(time.Time{}, mid) != nil {
+			t.Errorf("NewSnowflake accepted machine id %d", mid)
+		}
+		c := DefaultConfig()
+		c.MachineID = mid
+		if _, err := c.New(); !errors.Is(err, ErrInvalidMachineID) {
+			t.Errorf("Config.New with machine id %d = %v, want ErrInvalidMachineID", mid, err)
+		}
+	}
+}
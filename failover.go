@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrSameMachineID = errors.New("generators share a machine id")
+
+// FailoverEvent reports a switch between the generators of a Failover.
+type FailoverEvent struct {
+	From, To uint64 // machine IDs
+	Err      error  // the error that triggered the switch
+}
+
+// Failover issues IDs from one of two generators with different machine
+// IDs, switching to the other one after maxErrors consecutive errors, e.g.
+// a clock rollback, ErrClosed or ErrRateLimited. IDs from the two
+// generators never collide, but IDs issued around a switch are only
+// ordered by time, not by machine.
+type Failover struct {
+	gens      [2]*Snowflake
+	maxErrors int
+	onSwitch  func(FailoverEvent)
+
+	mu          sync.Mutex
+	active      int
+	consecutive int
+}
+
+// NewFailover returns a Failover starting on primary. maxErrors of zero
+// means 1. onSwitch, if not nil, is called synchronously on every switch.
+func NewFailover(primary, standby *Snowflake, maxErrors int, onSwitch func(FailoverEvent)) (*Failover, error) {
+	if primary == nil || standby == nil {
+		return nil, errors.New("failover needs two generators")
+	}
+	if primary.MachineID() == standby.MachineID() {
+		return nil, ErrSameMachineID
+	}
+	if maxErrors <= 0 {
+		maxErrors = 1
+	}
+
+	return &Failover{gens: [2]*Snowflake{primary, standby}, maxErrors: maxErrors, onSwitch: onSwitch}, nil
+}
+
+// NextID issues an ID from the active generator. When the error that
+// triggers a switch is returned, the call is retried once on the other
+// generator.
+func (f *Failover) NextID() (uint64, error) {
+	f.mu.Lock()
+	gen := f.gens[f.active]
+	f.mu.Unlock()
+
+	id, err := gen.NextID()
+	if err == nil {
+		f.mu.Lock()
+		f.consecutive = 0
+		f.mu.Unlock()
+		return id, nil
+	}
+
+	f.mu.Lock()
+	var ev *FailoverEvent
+	if f.gens[f.active] == gen {
+		f.consecutive++
+		if f.consecutive >= f.maxErrors {
+			f.active, f.consecutive = 1-f.active, 0
+			ev = &FailoverEvent{From: gen.MachineID(), To: f.gens[f.active].MachineID(), Err: err}
+		}
+	}
+	next := f.gens[f.active]
+	f.mu.Unlock()
+
+	if ev == nil {
+		return 0, err
+	}
+	if f.onSwitch != nil {
+		f.onSwitch(*ev)
+	}
+
+	return next.NextID()
+}
+
+// Active returns the generator currently issuing IDs.
+func (f *Failover) Active() *Snowflake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.gens[f.active]
+}
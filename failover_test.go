@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFailover(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	primary := NewSnowflake(epoch, 1)
+	standby := NewSnowflake(epoch, 2)
+
+	var events []FailoverEvent
+	f, err := NewFailover(primary, standby, 2, func(ev FailoverEvent) { events = append(events, ev) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, _ := f.NextID(); ID(id).Machine() != 1 {
+		t.Fatalf("expected the primary to issue first, got machine %d", ID(id).Machine())
+	}
+
+	primary.Close()
+
+	if _, err := f.NextID(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected the first error to be returned, got %v", err)
+	}
+	id, err := f.NextID()
+	if err != nil || ID(id).Machine() != 2 {
+		t.Fatalf("expected the standby to take over, got %d, %v", id, err)
+	}
+	if len(events) != 1 || events[0].From != 1 || events[0].To != 2 || !errors.Is(events[0].Err, ErrClosed) {
+		t.Errorf("unexpected events %+v", events)
+	}
+	if f.Active() != standby {
+		t.Error("expected the standby to be active")
+	}
+
+	if _, err := NewFailover(primary, NewSnowflake(epoch, 1), 1, nil); !errors.Is(err, ErrSameMachineID) {
+		t.Errorf("expected ErrSameMachineID, got %v", err)
+	}
+}
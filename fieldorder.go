@@ -0,0 +1,144 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Field names one of the fields of a Layout.
+type Field uint8
+
+const (
+	FieldTime Field = iota + 1
+	FieldTag
+	FieldMachine
+	FieldSequence
+)
+
+var fieldNames = [...]string{FieldTime: "time", FieldTag: "tag", FieldMachine: "machine", FieldSequence: "sequence"}
+
+func (f Field) String() string {
+	if f == 0 || int(f) >= len(fieldNames) {
+		return fmt.Sprintf("Field(%d)", uint8(f))
+	}
+
+	return fieldNames[f]
+}
+
+func (f Field) MarshalText() ([]byte, error) {
+	if f == 0 || int(f) >= len(fieldNames) {
+		return nil, fmt.Errorf("%w: unknown field %d", ErrInvalidLayout, uint8(f))
+	}
+
+	return []byte(fieldNames[f]), nil
+}
+
+func (f *Field) UnmarshalText(b []byte) error {
+	for i, name := range fieldNames {
+		if i > 0 && name == string(b) {
+			*f = Field(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown field %q", ErrInvalidLayout, b)
+}
+
+// FieldOrder lists the fields of a Layout from most to least significant.
+// The zero value is the default order of time, tag, machine ID and
+// sequence. Other orders describe IDs minted by foreign systems, e.g. a
+// vendor that puts the machine ID above the timestamp:
+//
+//	vendor := Layout{
+//		TimeBits: 41, MachineBits: 10, SequenceBits: 12,
+//		Order: FieldOrder{FieldMachine, FieldTime, FieldTag, FieldSequence},
+//	}
+//
+// Such layouts compose and decompose IDs, but IDs only sort by time with
+// the timestamp on top, so generators, ID sets and the other helpers that
+// rely on that reject them.
+type FieldOrder [4]Field
+
+var defaultOrder = FieldOrder{FieldTime, FieldTag, FieldMachine, FieldSequence}
+
+// validate checks that o names every field once.
+func (o FieldOrder) validate() error {
+	if o == (FieldOrder{}) {
+		return nil
+	}
+
+	var seen [len(fieldNames)]bool
+	for _, f := range o {
+		if f == 0 || int(f) >= len(fieldNames) || seen[f] {
+			return fmt.Errorf("%w: field order %v must name each field once", ErrInvalidLayout, o)
+		}
+		seen[f] = true
+	}
+
+	return nil
+}
+
+// TimeMajor reports if the timestamp is the most significant field of l,
+// which keeps IDs sorted by time.
+func (l Layout) TimeMajor() bool {
+	return l.Order == (FieldOrder{}) || l.Order[0] == FieldTime
+}
+
+// width returns the number of bits of f.
+func (l Layout) width(f Field) uint {
+	switch f {
+	case FieldTime:
+		return l.TimeBits
+	case FieldTag:
+		return l.TagBits
+	case FieldMachine:
+		return l.MachineBits
+	default:
+		return l.SequenceBits
+	}
+}
+
+// shift returns the position of f, the total width of the fields below it.
+func (l Layout) shift(f Field) uint {
+	if l.Order == (FieldOrder{}) {
+		switch f {
+		case FieldTime:
+			return l.timeShift()
+		case FieldTag:
+			return l.MachineBits + l.SequenceBits
+		case FieldMachine:
+			return l.SequenceBits
+		default:
+			return 0
+		}
+	}
+
+	var s uint
+	for i := len(l.Order) - 1; i >= 0 && l.Order[i] != f; i-- {
+		s += l.width(l.Order[i])
+	}
+
+	return s
+}
+
+// AppendID appends the 8 bytes of id to dst, little-endian if
+// l.LittleEndian is set and big-endian otherwise.
+func (l Layout) AppendID(dst []byte, id uint64) []byte {
+	if l.LittleEndian {
+		return binary.LittleEndian.AppendUint64(dst, id)
+	}
+
+	return binary.BigEndian.AppendUint64(dst, id)
+}
+
+// ReadID decodes an ID written by AppendID with the same layout.
+func (l Layout) ReadID(b []byte) (uint64, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("%w: %d bytes, want 8", ErrInvalidEncoding, len(b))
+	}
+	if l.LittleEndian {
+		return binary.LittleEndian.Uint64(b), nil
+	}
+
+	return binary.BigEndian.Uint64(b), nil
+}
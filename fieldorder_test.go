@@ -0,0 +1,124 @@
+package snowflake
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+var vendorLayout = Layout{
+	TimeBits:     41,
+	MachineBits:  10,
+	SequenceBits: 12,
+	Order:        FieldOrder{FieldMachine, FieldTime, FieldTag, FieldSequence},
+}
+
+func TestFieldOrderVendor(t *testing.T) {
+	if err := vendorLayout.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Machine 5 in bits 53-62, tick 1000 in bits 12-52, sequence 7 below.
+	want := uint64(5)<<53 | uint64(1000)<<12 | 7
+	if id := vendorLayout.Compose(1000, 5, 7); id != want {
+		t.Fatalf("Compose = %#x, want %#x", id, want)
+	}
+
+	ts, mid, seq := vendorLayout.Decompose(want)
+	if ts != 1000 || mid != 5 || seq != 7 {
+		t.Fatalf("Decompose = %d, %d, %d", ts, mid, seq)
+	}
+
+	if got := vendorLayout.String(); got != "41/10/12 machine,time,tag,sequence" {
+		t.Fatalf("String = %q", got)
+	}
+	if vendorLayout.TimeMajor() || !DefaultLayout.TimeMajor() {
+		t.Fatal("TimeMajor is wrong")
+	}
+	if NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1, WithLayout(vendorLayout)) != nil {
+		t.Fatal("generator accepted a layout without the timestamp on top")
+	}
+	if _, err := vendorLayout.AppendIDSet(nil, []uint64{want}); !errors.Is(err, ErrInvalidLayout) {
+		t.Fatalf("AppendIDSet error = %v", err)
+	}
+}
+
+func TestFieldOrderSequenceHigh(t *testing.T) {
+	l := Layout{
+		TimeBits:     41,
+		TagBits:      2,
+		MachineBits:  8,
+		SequenceBits: 12,
+		Order:        FieldOrder{FieldSequence, FieldTag, FieldMachine, FieldTime},
+	}
+	id := l.ComposeTagged(123456, 3, 200, 4095)
+	if id != 4095<<51|3<<49|200<<41|123456 {
+		t.Fatalf("ComposeTagged = %#x", id)
+	}
+	if tag := l.Tag(id); tag != 3 {
+		t.Fatalf("Tag = %d", tag)
+	}
+	ts, mid, seq := l.Decompose(id)
+	if ts != 123456 || mid != 200 || seq != 4095 {
+		t.Fatalf("Decompose = %d, %d, %d", ts, mid, seq)
+	}
+}
+
+func TestFieldOrderInvalid(t *testing.T) {
+	for _, o := range []FieldOrder{
+		{FieldTime, FieldTime, FieldMachine, FieldSequence},
+		{FieldTime, FieldMachine, FieldSequence},
+		{FieldTime, FieldTag, FieldMachine, 9},
+	} {
+		l := DefaultLayout
+		l.Order = o
+		if err := l.Validate(); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("Validate(%v) = %v", o, err)
+		}
+	}
+}
+
+func TestFieldOrderJSON(t *testing.T) {
+	b, err := json.Marshal(vendorLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"time_bits":41,"machine_bits":10,"sequence_bits":12,"order":["machine","time","tag","sequence"]}`
+	if string(b) != want {
+		t.Fatalf("Marshal = %s", b)
+	}
+
+	var l Layout
+	if err := json.Unmarshal(b, &l); err != nil {
+		t.Fatal(err)
+	}
+	if l != vendorLayout {
+		t.Fatalf("Unmarshal = %+v", l)
+	}
+
+	if b, _ := json.Marshal(DefaultLayout); bytes.Contains(b, []byte("order")) {
+		t.Fatalf("default layout marshals an order: %s", b)
+	}
+}
+
+func TestLittleEndianID(t *testing.T) {
+	l := DefaultLayout
+	l.LittleEndian = true
+
+	b := l.AppendID(nil, 0x0102030405060708)
+	if !bytes.Equal(b, []byte{8, 7, 6, 5, 4, 3, 2, 1}) {
+		t.Fatalf("AppendID = %x", b)
+	}
+	id, err := l.ReadID(b)
+	if err != nil || id != 0x0102030405060708 {
+		t.Fatalf("ReadID = %#x, %v", id, err)
+	}
+	if id, _ := DefaultLayout.ReadID(b); id != 0x0807060504030201 {
+		t.Fatalf("big-endian ReadID = %#x", id)
+	}
+	if _, err := l.ReadID(b[:7]); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("short ReadID error = %v", err)
+	}
+}
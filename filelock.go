@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrNoFreeMachineID = errors.New("no free machine id")
+
+// FileLockConfig configures a FileLockProvider.
+type FileLockConfig struct {
+	// Dir holds one lock file per machine ID. It must be on a local file
+	// system shared by all processes on the host; flock is unreliable over
+	// NFS.
+	Dir string
+	// MaxMachineID is the largest ID handed out. Defaults to the largest
+	// machine ID of DefaultLayout.
+	MaxMachineID uint64
+	// OnStale, if not nil, is called when a lock file still naming the
+	// process that held it is reclaimed, i.e. the process exited without
+	// releasing its ID.
+	OnStale func(machineID uint64, pid int)
+}
+
+// FileLockProvider is a MachineIDProvider that shares machine IDs between
+// the processes of one host through flock-ed files in a directory, e.g. to
+// run several workers per box without external coordination.
+//
+// The kernel drops the lock of a process that dies, so IDs of crashed
+// processes become free again without intervention. Lock files are never
+// removed, since deleting a file another process is about to lock would
+// let two processes hold the same ID.
+type FileLockProvider struct {
+	FileLockConfig
+
+	mu    sync.Mutex
+	files map[uint64]*os.File
+}
+
+// NewFileLockProvider creates cfg.Dir if needed and returns a provider
+// locking files in it.
+func NewFileLockProvider(cfg FileLockConfig) (*FileLockProvider, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("file lock provider needs a directory")
+	}
+	if cfg.MaxMachineID == 0 {
+		cfg.MaxMachineID = DefaultLayout.MaxMachineID()
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileLockProvider{FileLockConfig: cfg, files: make(map[uint64]*os.File)}, nil
+}
+
+// Acquire locks the lock file of the lowest free machine ID and records the
+// process ID in it.
+func (p *FileLockProvider) Acquire(ctx context.Context) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for id := uint64(0); id <= p.MaxMachineID; id++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if _, ok := p.files[id]; ok {
+			continue
+		}
+
+		f, err := os.OpenFile(p.path(id), os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return 0, err
+		}
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return 0, fmt.Errorf("locking %s: %w", f.Name(), err)
+		}
+		if !ok {
+			f.Close()
+			continue
+		}
+
+		if err := p.claim(id, f); err != nil {
+			unlock(f)
+			f.Close()
+			return 0, err
+		}
+		p.files[id] = f
+
+		return id, nil
+	}
+
+	return 0, fmt.Errorf("%w: all %d ids in %s are locked", ErrNoFreeMachineID, p.MaxMachineID+1, p.Dir)
+}
+
+// claim reports a stale previous owner of f and writes our process ID.
+func (p *FileLockProvider) claim(id uint64, f *os.File) error {
+	var buf [32]byte
+	n, _ := f.ReadAt(buf[:], 0)
+	if pid, err := strconv.Atoi(strings.TrimSpace(string(buf[:n]))); err == nil && p.OnStale != nil {
+		p.OnStale(id, pid)
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+
+	return err
+}
+
+// Release clears and unlocks the lock file of machineID.
+func (p *FileLockProvider) Release(ctx context.Context, machineID uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f, ok := p.files[machineID]
+	if !ok {
+		return fmt.Errorf("machine id %d not held", machineID)
+	}
+	delete(p.files, machineID)
+
+	return errors.Join(f.Truncate(0), unlock(f), f.Close())
+}
+
+func (p *FileLockProvider) path(id uint64) string {
+	return filepath.Join(p.Dir, fmt.Sprintf("machine-%d.lock", id))
+}
@@ -0,0 +1,83 @@
+//go:build unix
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLockProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "locks")
+
+	// Two providers stand in for two processes: flock locks belong to the
+	// open file, so they conflict within one process too.
+	a, err := NewFileLockProvider(FileLockConfig{Dir: dir, MaxMachineID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewFileLockProvider(FileLockConfig{Dir: dir, MaxMachineID: 2})
+
+	for _, tt := range []struct {
+		p    *FileLockProvider
+		want uint64
+	}{{a, 0}, {b, 1}, {a, 2}} {
+		if id, err := tt.p.Acquire(ctx); err != nil || id != tt.want {
+			t.Fatalf("Acquire = %d, %v, want %d", id, err, tt.want)
+		}
+	}
+	if _, err := b.Acquire(ctx); !errors.Is(err, ErrNoFreeMachineID) {
+		t.Fatalf("expected ErrNoFreeMachineID, got %v", err)
+	}
+
+	if err := a.Release(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Release(ctx, 0); err == nil {
+		t.Error("releasing an id twice should fail")
+	}
+	if id, err := b.Acquire(ctx); err != nil || id != 0 {
+		t.Errorf("expected the released id 0, got %d, %v", id, err)
+	}
+}
+
+func TestFileLockProviderStale(t *testing.T) {
+	dir := t.TempDir()
+
+	// A process that exited without releasing leaves its pid behind, but
+	// not its lock.
+	if err := os.WriteFile(filepath.Join(dir, "machine-0.lock"), []byte("4242\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stale []int
+	p, _ := NewFileLockProvider(FileLockConfig{Dir: dir, OnStale: func(id uint64, pid int) {
+		stale = append(stale, pid)
+	}})
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sf := NewSnowflake(epoch, 0, WithMachineIDProvider(p))
+	if sf == nil || sf.MachineID() != 0 {
+		t.Fatal("expected the stale id 0 to be reclaimed")
+	}
+	if len(stale) != 1 || stale[0] != 4242 {
+		t.Errorf("expected stale owner 4242 to be reported, got %v", stale)
+	}
+
+	if err := sf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, "machine-0.lock")); len(b) != 0 {
+		t.Errorf("expected a clean release to clear the lock file, got %q", b)
+	}
+
+	// A cleanly released ID is not reported as stale.
+	if _, err := p.Acquire(context.Background()); err != nil || len(stale) != 1 {
+		t.Errorf("unexpected stale report after clean release: %v, %v", stale, err)
+	}
+}
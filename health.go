@@ -0,0 +1,156 @@
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthState summarizes a generator's health, from best to worst.
+type HealthState int
+
+const (
+	// HealthOK: IDs are issued without delay.
+	HealthOK HealthState = iota
+	// HealthDegraded: IDs are issued, but the generator often waits for the
+	// next tick, borrows time from a clock behind its last ID, issues
+	// WithRandomFallback IDs or approaches the end of its epoch.
+	HealthDegraded
+	// HealthFailing: NextID fails or soon will, because the generator is
+	// closed, the clock is unsynchronized or too far behind, or the epoch
+	// is nearly exhausted.
+	HealthFailing
+)
+
+func (s HealthState) String() string {
+	switch s {
+	case HealthOK:
+		return "ok"
+	case HealthDegraded:
+		return "degraded"
+	case HealthFailing:
+		return "failing"
+	}
+
+	return fmt.Sprintf("HealthState(%d)", int(s))
+}
+
+func (s HealthState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// HealthStatus is the result of Health.
+type HealthStatus struct {
+	State HealthState `json:"state"`
+	// Reasons explains every problem found, worst first.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// HealthThresholds tune Health. Zero fields take the defaults of
+// DefaultHealthThresholds.
+type HealthThresholds struct {
+	// RolloversPerSecond is the sequence rollover rate, measured between
+	// two Health calls, above which the generator is degraded.
+	RolloversPerSecond float64
+	// EpochDegraded and EpochFailing are the remaining epoch below which
+	// the generator is degraded and failing.
+	EpochDegraded time.Duration
+	EpochFailing  time.Duration
+}
+
+// DefaultHealthThresholds are used unless WithHealthThresholds is given.
+var DefaultHealthThresholds = HealthThresholds{
+	RolloversPerSecond: 10,
+	EpochDegraded:      5 * 365 * 24 * time.Hour,
+	EpochFailing:       30 * 24 * time.Hour,
+}
+
+// WithHealthThresholds changes the thresholds used by Health.
+func WithHealthThresholds(t HealthThresholds) Option {
+	return func(sf *Snowflake) {
+		sf.healthThresholds = t
+	}
+}
+
+// healthWatch remembers the counters at the previous Health call, so rates
+// can be measured between calls, e.g. readiness probes.
+type healthWatch struct {
+	mu        sync.Mutex
+	at        time.Time
+	rollovers uint64
+	degraded  uint64
+}
+
+// Health checks the generator, e.g. for a readiness probe. Rates such as
+// sequence rollovers are measured since the previous call, so the first
+// call only reports conditions visible at once.
+func (sf *Snowflake) Health() HealthStatus {
+	th := sf.healthThresholds
+	def := DefaultHealthThresholds
+	if th.RolloversPerSecond == 0 {
+		th.RolloversPerSecond = def.RolloversPerSecond
+	}
+	if th.EpochDegraded == 0 {
+		th.EpochDegraded = def.EpochDegraded
+	}
+	if th.EpochFailing == 0 {
+		th.EpochFailing = def.EpochFailing
+	}
+
+	var failing, degraded []string
+
+	sf.mutex.Lock()
+	closed := sf.closed
+	lag := time.Duration(sf.lastTimestamp-sf.elapsedTime()) * sf.unit
+	sf.mutex.Unlock()
+
+	if closed {
+		failing = append(failing, "generator is closed")
+	}
+	if sf.drift != nil && !sf.drift.Synced() {
+		failing = append(failing, fmt.Sprintf("clock is unsynchronized, offset %s", sf.drift.Offset()))
+	}
+	if lag > 0 {
+		msg := fmt.Sprintf("clock is %s behind the last ID", lag)
+		if sf.checkBackward && lag > max(sf.maxBackward, sf.maxBorrow) && !sf.randomFallback {
+			failing = append(failing, msg)
+		} else {
+			degraded = append(degraded, msg)
+		}
+	}
+
+	remaining := sf.RemainingEpoch()
+	switch {
+	case remaining < th.EpochFailing:
+		failing = append(failing, fmt.Sprintf("epoch runs out in %s", remaining))
+	case remaining < th.EpochDegraded:
+		degraded = append(degraded, fmt.Sprintf("epoch runs out in %s", remaining))
+	}
+
+	now := sf.clock.Now()
+	st := sf.Stats()
+	w := &sf.health
+	w.mu.Lock()
+	if !w.at.IsZero() {
+		if elapsed := now.Sub(w.at).Seconds(); elapsed > 0 {
+			if rate := float64(st.Rollovers-w.rollovers) / elapsed; rate > th.RolloversPerSecond {
+				degraded = append(degraded, fmt.Sprintf("sequence rolls over %.1f times per second", rate))
+			}
+		}
+		if n := st.Degraded - w.degraded; n > 0 {
+			degraded = append(degraded, fmt.Sprintf("%d random fallback IDs issued", n))
+		}
+	}
+	w.at, w.rollovers, w.degraded = now, st.Rollovers, st.Degraded
+	w.mu.Unlock()
+
+	status := HealthStatus{Reasons: append(failing, degraded...)}
+	switch {
+	case failing != nil:
+		status.State = HealthFailing
+	case degraded != nil:
+		status.State = HealthDegraded
+	}
+
+	return status
+}
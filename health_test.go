@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestHealth(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithMaxBackwardTolerance(10*time.Millisecond))
+
+	if h := sf.Health(); h.State != HealthOK || h.Reasons != nil {
+		t.Fatalf("expected a healthy generator, got %+v", h)
+	}
+
+	// Ten rollovers in a second are fine, a hundred are not.
+	for i := 0; i < 100<<SequenceBits; i++ {
+		sf.NextID()
+	}
+	clock.Advance(time.Second)
+	if h := sf.Health(); h.State != HealthDegraded || !strings.Contains(h.Reasons[0], "rolls over") {
+		t.Errorf("expected sequence pressure, got %+v", h)
+	}
+	clock.Advance(time.Second)
+	if h := sf.Health(); h.State != HealthOK {
+		t.Errorf("expected the pressure to be over, got %+v", h)
+	}
+
+	sf.NextID()
+	clock.Advance(-5 * time.Millisecond)
+	if h := sf.Health(); h.State != HealthDegraded || !strings.Contains(h.Reasons[0], "behind the last ID") {
+		t.Errorf("expected a lagging clock to degrade, got %+v", h)
+	}
+	clock.Advance(-time.Second)
+	if h := sf.Health(); h.State != HealthFailing {
+		t.Errorf("expected a clock beyond the tolerance to fail, got %+v", h)
+	}
+	clock.Advance(2 * time.Second)
+
+	ntp := new(fakeNTP)
+	ntp.offset.Store(int64(time.Minute))
+	drift := NewDriftMonitor(ntp, time.Second, time.Hour)
+	drift.Check(context.Background())
+	unsynced := NewSnowflake(epoch, 1, WithClock(clock), WithDriftMonitor(drift))
+	if h := unsynced.Health(); h.State != HealthFailing || !strings.Contains(h.Reasons[0], "unsynchronized") {
+		t.Errorf("expected an unsynced clock to fail, got %+v", h)
+	}
+
+	// 37 time bits last about 4.4 years from 2020.
+	short := NewSnowflake(epoch, 1, WithClock(clock), WithLayout(Layout{TimeBits: 37, MachineBits: 10, SequenceBits: 12}))
+	if h := short.Health(); h.State != HealthDegraded || !strings.Contains(h.Reasons[0], "epoch") {
+		t.Errorf("expected a short epoch to degrade, got %+v", h)
+	}
+	custom := NewSnowflake(epoch, 1, WithClock(clock), WithLayout(Layout{TimeBits: 37, MachineBits: 10, SequenceBits: 12}),
+		WithHealthThresholds(HealthThresholds{EpochFailing: 10 * 365 * 24 * time.Hour}))
+	if h := custom.Health(); h.State != HealthFailing {
+		t.Errorf("expected a custom threshold to fail, got %+v", h)
+	}
+
+	sf.Close()
+	if h := sf.Health(); h.State != HealthFailing || h.State.String() != "failing" {
+		t.Errorf("expected a closed generator to fail, got %+v", h)
+	}
+}
@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrHighWater = errors.New("cannot persist high-water mark")
+
+// HighWaterStore persists a generator's high-water mark: a time no ID has
+// been issued at or after.
+type HighWaterStore interface {
+	// Get returns the persisted mark, or the zero time if there is none.
+	Get() (time.Time, error)
+	Set(mark time.Time) error
+}
+
+// WithHighWaterMark protects against duplicate IDs after a restart with a
+// clock set back. Before issuing an ID at or after the persisted mark, the
+// generator persists a new mark lead ahead, renewing it once half of the
+// lead is used up. On creation it never issues IDs below the stored mark,
+// waiting for the clock to reach it as after a sequence rollover.
+//
+// A larger lead means fewer writes but a longer wait after restarts with a
+// correct clock. NextID returns ErrHighWater if the mark cannot be renewed
+// before it is reached; NewSnowflake returns nil if the mark cannot be read
+// or lead is shorter than one tick.
+func WithHighWaterMark(store HighWaterStore, lead time.Duration) Option {
+	return func(sf *Snowflake) {
+		sf.highWater = store
+		sf.highWaterLead = lead
+	}
+}
+
+// loadHighWater fast-forwards past the stored mark.
+func (sf *Snowflake) loadHighWater() error {
+	mark, err := sf.highWater.Get()
+	if err != nil || mark.IsZero() {
+		return err
+	}
+
+	tick := sf.toUnit(mark) - sf.startTime
+	if tick-1 > sf.lastTimestamp {
+		// Exhaust the tick before the mark, so the next ID is at the mark.
+		sf.lastTimestamp = tick - 1
+		sf.sequence = sf.seqMask
+		sf.seqIndex = sf.seqMask
+		sf.current.Store(uint64(sf.lastTimestamp)<<16 | uint64(sf.seqIndex))
+	}
+	sf.highWaterMark = tick
+
+	return nil
+}
+
+// guardHighWater renews the mark before sf.lastTimestamp reaches it. It
+// must be called with sf.mutex held.
+func (sf *Snowflake) guardHighWater() error {
+	lead := int64(sf.highWaterLead / sf.unit)
+	if sf.lastTimestamp < sf.highWaterMark-lead/2 {
+		return nil
+	}
+
+	next := sf.lastTimestamp + lead
+	if err := sf.highWater.Set(sf.SnowflakeUnitToTime(next)); err != nil {
+		if sf.lastTimestamp >= sf.highWaterMark {
+			return sf.generationError(fmt.Errorf("%w: %v", ErrHighWater, err))
+		}
+		// Still below the old mark; retry on the next call.
+		return nil
+	}
+	sf.highWaterMark = next
+
+	return nil
+}
+
+// FileHighWaterStore is a HighWaterStore keeping the mark in a text file.
+type FileHighWaterStore struct {
+	path string
+}
+
+func NewFileHighWaterStore(path string) *FileHighWaterStore {
+	return &FileHighWaterStore{path: path}
+}
+
+func (s *FileHighWaterStore) Get() (time.Time, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+}
+
+// Set atomically replaces the mark file.
+func (s *FileHighWaterStore) Set(mark time.Time) error {
+	return writeFileAtomic(s.path, []byte(mark.UTC().Format(time.RFC3339Nano)+"\n"))
+}
@@ -0,0 +1,93 @@
+package snowflake
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+type memoryHighWater struct {
+	mark time.Time
+	sets int
+	err  error
+}
+
+func (s *memoryHighWater) Get() (time.Time, error) { return s.mark, nil }
+
+func (s *memoryHighWater) Set(mark time.Time) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.mark = mark
+	s.sets++
+
+	return nil
+}
+
+func TestHighWaterMark(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := clocktest.NewFakeClock(start)
+	store := new(memoryHighWater)
+
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithHighWaterMark(store, time.Second))
+	var last uint64
+	for i := 0; i < 20; i++ {
+		last, _ = sf.NextID()
+		clock.Advance(100 * time.Millisecond)
+	}
+	// The first ID sets the mark, which is renewed every 500ms.
+	if store.sets != 4 || !store.mark.After(sf.IDToTime(last)) {
+		t.Errorf("unexpected mark %s after %d writes, last ID at %s", store.mark, store.sets, sf.IDToTime(last))
+	}
+
+	// After a restart with the clock set back, IDs start at the mark.
+	mark := store.mark
+	clock.Advance(start.Sub(clock.Now()))
+	restarted := NewSnowflake(epoch, 1, WithClock(clock), WithHighWaterMark(store, time.Second))
+	id, err := restarted.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= last || !restarted.IDToTime(id).Equal(mark) {
+		t.Errorf("restarted at %s, want the mark %s", restarted.IDToTime(id), mark)
+	}
+
+	// Failed renewals are retried until the mark is reached.
+	store.err = errors.New("disk full")
+	for {
+		_, err = restarted.NextID()
+		if err != nil {
+			break
+		}
+		clock.Advance(100 * time.Millisecond)
+	}
+	if !errors.Is(err, ErrHighWater) {
+		t.Errorf("expected ErrHighWater, got %v", err)
+	}
+	if got := restarted.IDToTime(restarted.layout.Compose(uint64(restarted.Snapshot().LastTimestamp), 0, 0)); got.Before(store.mark) {
+		t.Errorf("failed at %s, before the mark %s", got, store.mark)
+	}
+
+	if NewSnowflake(epoch, 1, WithClock(clock), WithHighWaterMark(store, time.Microsecond)) != nil {
+		t.Error("expected nil for a lead shorter than a tick")
+	}
+}
+
+func TestFileHighWaterStore(t *testing.T) {
+	s := NewFileHighWaterStore(filepath.Join(t.TempDir(), "mark"))
+	if mark, err := s.Get(); err != nil || !mark.IsZero() {
+		t.Fatalf("expected no mark, got %s, %v", mark, err)
+	}
+
+	want := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	if err := s.Set(want); err != nil {
+		t.Fatal(err)
+	}
+	if mark, err := s.Get(); err != nil || !mark.Equal(want) {
+		t.Errorf("got %s, %v, want %s", mark, err, want)
+	}
+}
@@ -0,0 +1,100 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BlockStore hands out disjoint ranges of a shared counter, e.g. a SQL
+// sequence advanced by the block size or a Redis INCRBY.
+type BlockStore interface {
+	// Reserve reserves size consecutive counter values and returns the first.
+	Reserve(ctx context.Context, size uint64) (uint64, error)
+}
+
+// HiLoConfig configures a HiLo allocator.
+type HiLoConfig struct {
+	Store     BlockStore
+	BlockSize uint64
+	// MaxBlockAge bounds how long a block is used before the rest of it is
+	// abandoned. Defaults to one second.
+	MaxBlockAge time.Duration
+	// Fallback issues IDs while Store is unreachable. It also defines the
+	// epoch, layout and clock of HiLo IDs. Its machine ID must have the top
+	// machine bit set.
+	Fallback *Snowflake
+}
+
+// HiLo issues IDs without a coordinated machine ID. The machine and sequence
+// bits of each ID are taken from a counter value reserved in blocks from a
+// central store, restricted to machine IDs with the top bit clear; the
+// timestamp comes from the local clock.
+//
+// Two nodes can only collide if 2^(machine+sequence bits-1) counter values
+// are reserved while one of them still uses an older block, which
+// MaxBlockAge rules out for realistic reservation rates. IDs are unique but
+// only roughly time ordered.
+type HiLo struct {
+	HiLoConfig
+
+	mu         sync.Mutex
+	next, end  uint64
+	reservedAt time.Time
+
+	fallbacks atomic.Uint64
+}
+
+func NewHiLo(cfg HiLoConfig) (*HiLo, error) {
+	if cfg.Store == nil || cfg.Fallback == nil || cfg.BlockSize == 0 {
+		return nil, errors.New("hilo needs a store, a fallback generator and a block size")
+	}
+
+	l := cfg.Fallback.layout
+	if l.MachineBits == 0 || cfg.Fallback.MachineID()>>(l.MachineBits-1) == 0 {
+		return nil, errors.New("hilo fallback machine id must have the top machine bit set")
+	}
+	if cfg.MaxBlockAge <= 0 {
+		cfg.MaxBlockAge = time.Second
+	}
+
+	return &HiLo{HiLoConfig: cfg}, nil
+}
+
+// NextID issues an ID from the current block, reserving a new one when it
+// is used up or too old. If the reservation fails, the ID is issued by the
+// fallback generator instead.
+func (h *HiLo) NextID(ctx context.Context) (uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sf := h.Fallback
+	now := sf.clock.Now()
+
+	if h.next == h.end || now.Sub(h.reservedAt) > h.MaxBlockAge {
+		start, err := h.Store.Reserve(ctx, h.BlockSize)
+		if err != nil {
+			h.fallbacks.Add(1)
+			return sf.NextID()
+		}
+		h.next, h.end, h.reservedAt = start, start+h.BlockSize, now
+	}
+
+	l := sf.layout
+	low := h.next & (1<<(l.MachineBits+l.SequenceBits-1) - 1)
+	h.next++
+
+	ts := sf.toUnit(now) - sf.startTime
+	if ts < 0 || uint64(ts) > l.MaxTimestamp() {
+		return 0, &GenerationError{Err: ErrEpochExhausted, Timestamp: ts}
+	}
+
+	return l.Compose(uint64(ts), 0, 0) | low, nil
+}
+
+// Fallbacks returns how many IDs were issued by the fallback generator.
+func (h *HiLo) Fallbacks() uint64 {
+	return h.fallbacks.Load()
+}
@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+type counterStore struct {
+	counter uint64
+	err     error
+}
+
+func (s *counterStore) Reserve(_ context.Context, size uint64) (uint64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	start := s.counter
+	s.counter += size
+
+	return start, nil
+}
+
+func TestHiLo(t *testing.T) {
+	ctx := context.Background()
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	store := new(counterStore)
+
+	if _, err := NewHiLo(HiLoConfig{Store: store, BlockSize: 10, Fallback: NewSnowflake(epoch, 5, WithClock(clock))}); err == nil {
+		t.Error("fallback machine ids in the hilo half should be rejected")
+	}
+
+	fallback := NewSnowflake(epoch, 1<<(MachineIDBits-1)|5, WithClock(clock))
+	a, err := NewHiLo(HiLoConfig{Store: store, BlockSize: 10, Fallback: fallback})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewHiLo(HiLoConfig{Store: store, BlockSize: 10, Fallback: fallback})
+
+	seen := make(map[uint64]bool)
+	issue := func(h *HiLo) uint64 {
+		t.Helper()
+		id, err := h.NextID(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+
+		return id
+	}
+
+	for i := 0; i < 25; i++ {
+		issue(a)
+		issue(b)
+	}
+	if store.counter != 60 {
+		t.Errorf("expected 6 blocks to be reserved, counter at %d", store.counter)
+	}
+
+	// Blocks already reserved keep working while the store is down; new
+	// reservations fall back to the time-based generator.
+	store.err = errors.New("connection refused")
+	id := issue(a)
+	if !fallback.IDToTime(id).Equal(clock.Now()) {
+		t.Errorf("hilo id time %s, want %s", fallback.IDToTime(id), clock.Now())
+	}
+
+	c, _ := NewHiLo(HiLoConfig{Store: store, BlockSize: 10, Fallback: fallback})
+	if _, mid, _ := DecomposeParts(issue(c)); mid != fallback.MachineID() || c.Fallbacks() != 1 {
+		t.Errorf("expected fallback id, got machine %d", mid)
+	}
+
+	// Old blocks are abandoned.
+	store.err = nil
+	clock.Advance(2 * time.Second)
+	before := store.counter
+	issue(a)
+	if store.counter != before+10 {
+		t.Error("expired block should have been replaced")
+	}
+}
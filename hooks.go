@@ -0,0 +1,66 @@
+package snowflake
+
+import "time"
+
+// Hooks are callbacks invoked by NextID and ReserveBlock, e.g. for logging
+// or custom metrics. Unlike Metrics they run after the generator lock is
+// released, so they may block or call back into the generator. Nil fields
+// are skipped.
+type Hooks struct {
+	// OnGenerate is called with every ID issued, including each ID of a
+	// block reserved with ReserveBlock.
+	OnGenerate func(id uint64)
+	// OnRollover is called when a tick's sequence space was exhausted.
+	OnRollover func()
+	// OnWait is called with the time spent sleeping for the next tick.
+	OnWait func(d time.Duration)
+	// OnError is called with every error returned.
+	OnError func(err error)
+}
+
+// WithHooks invokes h on the events of NextID and ReserveBlock.
+func WithHooks(h Hooks) Option {
+	return func(sf *Snowflake) {
+		sf.hooks = &h
+	}
+}
+
+// hookEvents collects the events of one NextID call while the generator
+// lock is held.
+type hookEvents struct {
+	rollover bool
+	waited   time.Duration
+}
+
+func (h *Hooks) run(id uint64, ev hookEvents, err error) {
+	if h.report(ev, err) && h.OnGenerate != nil {
+		h.OnGenerate(id)
+	}
+}
+
+// runBlock is run for ReserveBlock, calling OnGenerate with every ID of b.
+func (h *Hooks) runBlock(b Block, ev hookEvents, err error) {
+	if h.report(ev, err) && h.OnGenerate != nil {
+		for i := range b.Len() {
+			h.OnGenerate(b.ID(i))
+		}
+	}
+}
+
+// report runs the event and error hooks and reports if the call succeeded.
+func (h *Hooks) report(ev hookEvents, err error) bool {
+	if ev.rollover && h.OnRollover != nil {
+		h.OnRollover()
+	}
+	if ev.waited > 0 && h.OnWait != nil {
+		h.OnWait(ev.waited)
+	}
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(err)
+		}
+		return false
+	}
+
+	return true
+}
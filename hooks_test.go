@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestHooks(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	var (
+		sf        *Snowflake
+		generated int
+		last      uint64
+		rollovers int
+		waited    time.Duration
+		errs      []error
+	)
+	sf = NewSnowflake(epoch, 1, WithClock(clock), WithHooks(Hooks{
+		OnGenerate: func(id uint64) {
+			generated++
+			last = id
+			// The lock is released, so hooks may inspect the generator.
+			if sf.LastTime().IsZero() {
+				t.Error("unexpected zero time")
+			}
+		},
+		OnRollover: func() { rollovers++ },
+		OnWait:     func(d time.Duration) { waited += d },
+		OnError:    func(err error) { errs = append(errs, err) },
+	}))
+
+	var id uint64
+	for i := 0; i < 1<<SequenceBits+1; i++ {
+		id, _ = sf.NextID()
+	}
+
+	if generated != 1<<SequenceBits+1 || last != id {
+		t.Errorf("expected %d ids ending in %d, got %d ending in %d", 1<<SequenceBits+1, id, generated, last)
+	}
+	if rollovers != 1 {
+		t.Errorf("expected 1 rollover, got %d", rollovers)
+	}
+	if waited != time.Millisecond {
+		t.Errorf("expected to wait 1ms, waited %s", waited)
+	}
+
+	sf.Close()
+	if _, err := sf.NextID(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrClosed) {
+		t.Errorf("expected ErrClosed to be reported, got %v", errs)
+	}
+	if generated != 1<<SequenceBits+1 {
+		t.Error("OnGenerate called for a failed call")
+	}
+}
+
+func TestHooksReserveBlock(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	var (
+		generated []uint64
+		errs      []error
+	)
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithHooks(Hooks{
+		OnGenerate: func(id uint64) { generated = append(generated, id) },
+		OnError:    func(err error) { errs = append(errs, err) },
+	}))
+
+	b, err := sf.ReserveBlock(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generated) != b.Len() || generated[0] != b.ID(0) || generated[2] != b.ID(2) {
+		t.Errorf("expected the ids of the block, got %v", generated)
+	}
+
+	if _, err := sf.ReserveBlock(0); !errors.Is(err, ErrInvalidBlockSize) {
+		t.Fatalf("expected ErrInvalidBlockSize, got %v", err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidBlockSize) {
+		t.Errorf("expected ErrInvalidBlockSize to be reported, got %v", errs)
+	}
+}
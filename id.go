@@ -0,0 +1,64 @@
+package snowflake
+
+import "time"
+
+// ID is a snowflake identifier as returned by NextID. Its methods assume
+// DefaultLayout.
+type ID uint64
+
+// Time returns the time embedded in id, interpreted against the package
+// epoch. IDs from a generator with a custom epoch should be converted with
+// the generator's IDToTime instead.
+func (id ID) Time() time.Time {
+	t, _, _ := DefaultLayout.Decompose(uint64(id))
+
+	return time.Unix(0, epochStart.UnixNano()+int64(t)*int64(snowflakeTimeUnit))
+}
+
+// Machine returns the machine ID embedded in id.
+func (id ID) Machine() uint64 {
+	_, mid, _ := DefaultLayout.Decompose(uint64(id))
+
+	return mid
+}
+
+// Sequence returns the sequence number embedded in id.
+func (id ID) Sequence() uint64 {
+	_, _, seq := DefaultLayout.Decompose(uint64(id))
+
+	return seq
+}
+
+// Before reports whether id sorts before other. IDs from the same generator
+// sort in the order they were issued.
+func (id ID) Before(other ID) bool {
+	return id < other
+}
+
+// Sub returns the time elapsed between the ticks embedded in other and id.
+func (id ID) Sub(other ID) time.Duration {
+	t, _, _ := DefaultLayout.Decompose(uint64(id))
+	o, _, _ := DefaultLayout.Decompose(uint64(other))
+
+	return time.Duration(int64(t)-int64(o)) * snowflakeTimeUnit
+}
+
+// ApproxBefore reports whether a was generated before b, allowing for clock
+// skew between machines. IDs from the same machine are strictly ordered.
+// IDs from different machines whose timestamps are within tolerance of each
+// other are treated as concurrent, and ApproxBefore reports false for them
+// in both directions.
+func ApproxBefore(a, b ID, tolerance time.Duration) bool {
+	ta, ma, _ := DefaultLayout.Decompose(uint64(a))
+	tb, mb, _ := DefaultLayout.Decompose(uint64(b))
+
+	if ma == mb {
+		return a < b
+	}
+
+	if tb <= ta {
+		return false
+	}
+
+	return time.Duration(tb-ta)*snowflakeTimeUnit > tolerance
+}
@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApproxBefore(t *testing.T) {
+	compose := func(ts, mid, seq uint64) ID {
+		return ID(ts<<(MachineIDBits+SequenceBits) | mid<<SequenceBits | seq)
+	}
+
+	tests := []struct {
+		a, b      ID
+		tolerance time.Duration
+		want      bool
+	}{
+		{compose(100, 1, 0), compose(100, 1, 1), time.Second, true},
+		{compose(100, 1, 1), compose(100, 1, 0), time.Second, false},
+		{compose(100, 1, 0), compose(105, 2, 0), 10 * time.Millisecond, false},
+		{compose(105, 2, 0), compose(100, 1, 0), 10 * time.Millisecond, false},
+		{compose(100, 1, 0), compose(111, 2, 0), 10 * time.Millisecond, true},
+		{compose(100, 1, 0), compose(110, 2, 0), 10 * time.Millisecond, false},
+		{compose(100, 1, 0), compose(101, 2, 0), 0, true},
+	}
+
+	for _, tt := range tests {
+		if got := ApproxBefore(tt.a, tt.b, tt.tolerance); got != tt.want {
+			t.Errorf("ApproxBefore(%d, %d, %s) = %v, want %v", tt.a, tt.b, tt.tolerance, got, tt.want)
+		}
+	}
+}
+
+func TestIDMethods(t *testing.T) {
+	sf := NewSnowflake(time.Time{}, 17)
+
+	a, _ := sf.NextID()
+	time.Sleep(2 * time.Millisecond)
+	b, _ := sf.NextID()
+
+	idA, idB := ID(a), ID(b)
+
+	if idA.Machine() != 17 || idA.Sequence() != 0 {
+		t.Errorf("unexpected parts: machine %d sequence %d", idA.Machine(), idA.Sequence())
+	}
+	if !idA.Time().Equal(sf.IDToTime(a)) {
+		t.Errorf("Time() = %s, want %s", idA.Time(), sf.IDToTime(a))
+	}
+	if !idA.Before(idB) || idB.Before(idA) {
+		t.Error("ids should be ordered by issuance")
+	}
+	if d := idB.Sub(idA); d < 2*time.Millisecond || d != -idA.Sub(idB) {
+		t.Errorf("unexpected Sub result %s", d)
+	}
+}
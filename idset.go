@@ -0,0 +1,165 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var ErrUnsortedIDs = errors.New("ids are not strictly increasing")
+
+const idSetVersion = 1
+
+// EncodeIDSet compactly encodes strictly increasing IDs in DefaultLayout,
+// see Layout.AppendIDSet.
+func EncodeIDSet(ids []ID) ([]byte, error) {
+	raw := make([]uint64, len(ids))
+	for i, id := range ids {
+		raw[i] = uint64(id)
+	}
+
+	return DefaultLayout.AppendIDSet(nil, raw)
+}
+
+// DecodeIDSet decodes IDs encoded by EncodeIDSet.
+func DecodeIDSet(b []byte) ([]ID, error) {
+	raw, err := DefaultLayout.DecodeIDSet(b)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]ID, len(raw))
+	for i, id := range raw {
+		ids[i] = ID(id)
+	}
+
+	return ids, nil
+}
+
+// AppendIDSet appends a compact encoding of ids, which must be strictly
+// increasing, to dst. Each ID after the first is stored as the varint
+// difference of its timestamp to the previous one, followed by the
+// difference of its machine and sequence bits: unsigned within one tick,
+// zig-zag signed across ticks. Runs of IDs from a few machines take one to
+// three bytes per ID instead of eight.
+func (l Layout) AppendIDSet(dst []byte, ids []uint64) ([]byte, error) {
+	if !l.TimeMajor() {
+		return nil, fmt.Errorf("%w: id sets need the timestamp on top", ErrInvalidLayout)
+	}
+	dst = append(dst, idSetVersion)
+	dst = binary.AppendUvarint(dst, uint64(len(ids)))
+	if len(ids) == 0 {
+		return dst, nil
+	}
+
+	shift := l.timeShift()
+	low := uint64(1)<<shift - 1
+
+	dst = binary.AppendUvarint(dst, ids[0])
+	for i := 1; i < len(ids); i++ {
+		prev, id := ids[i-1], ids[i]
+		if id <= prev {
+			return nil, fmt.Errorf("%w: %d follows %d", ErrUnsortedIDs, id, prev)
+		}
+
+		dt := id>>shift - prev>>shift
+		dst = binary.AppendUvarint(dst, dt)
+		if dt == 0 {
+			dst = binary.AppendUvarint(dst, id&low-prev&low-1)
+		} else {
+			dst = binary.AppendVarint(dst, int64(id&low)-int64(prev&low))
+		}
+	}
+
+	return dst, nil
+}
+
+// DecodeIDSet decodes IDs encoded by AppendIDSet with the same layout.
+func (l Layout) DecodeIDSet(b []byte) ([]uint64, error) {
+	if !l.TimeMajor() {
+		return nil, fmt.Errorf("%w: id sets need the timestamp on top", ErrInvalidLayout)
+	}
+	if len(b) == 0 || b[0] != idSetVersion {
+		return nil, fmt.Errorf("%w: unknown id set version", ErrInvalidEncoding)
+	}
+	b = b[1:]
+
+	n, err := readUvarint(&b)
+	if err != nil {
+		return nil, err
+	}
+	// Every ID takes at least one byte, which bounds the allocation.
+	if n > uint64(len(b)) {
+		return nil, fmt.Errorf("%w: %d ids in %d bytes", ErrInvalidEncoding, n, len(b))
+	}
+	if n == 0 && len(b) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidEncoding, len(b))
+	}
+	if n == 0 {
+		return []uint64{}, nil
+	}
+
+	shift := l.timeShift()
+	low := uint64(1)<<shift - 1
+
+	ids := make([]uint64, 0, n)
+	first, err := readUvarint(&b)
+	if err != nil {
+		return nil, err
+	}
+	if first>>shift > l.MaxTimestamp() {
+		return nil, fmt.Errorf("%w: id out of range", ErrInvalidEncoding)
+	}
+	ids = append(ids, first)
+
+	for uint64(len(ids)) < n {
+		prev := ids[len(ids)-1]
+		dt, err := readUvarint(&b)
+		if err != nil {
+			return nil, err
+		}
+
+		var lo uint64
+		if dt == 0 {
+			d, err := readUvarint(&b)
+			if err != nil {
+				return nil, err
+			}
+			if d >= low {
+				return nil, fmt.Errorf("%w: id out of range", ErrInvalidEncoding)
+			}
+			lo = prev&low + d + 1
+		} else {
+			d, k := binary.Varint(b)
+			if k <= 0 {
+				return nil, fmt.Errorf("%w: truncated id set", ErrInvalidEncoding)
+			}
+			b = b[k:]
+			if d > int64(low) || d < -int64(low) {
+				return nil, fmt.Errorf("%w: id out of range", ErrInvalidEncoding)
+			}
+			lo = uint64(int64(prev&low) + d)
+		}
+
+		ts := prev>>shift + dt
+		if lo > low || ts < prev>>shift || ts > l.MaxTimestamp() {
+			return nil, fmt.Errorf("%w: id out of range", ErrInvalidEncoding)
+		}
+		ids = append(ids, ts<<shift|lo)
+	}
+	if len(b) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidEncoding, len(b))
+	}
+
+	return ids, nil
+}
+
+func readUvarint(b *[]byte) (uint64, error) {
+	v, k := binary.Uvarint(*b)
+	if k <= 0 {
+		return 0, fmt.Errorf("%w: truncated id set", ErrInvalidEncoding)
+	}
+	*b = (*b)[k:]
+
+	return v, nil
+}
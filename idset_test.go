@@ -0,0 +1,89 @@
+package snowflake
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestIDSet(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	// IDs from 8 machines issued over a few seconds, in bursts.
+	var gens []*Snowflake
+	for mid := 0; mid < 8; mid++ {
+		gens = append(gens, NewSnowflake(epoch, 100+mid, WithClock(clock)))
+	}
+	var ids []ID
+	for i := 0; i < 20000; i++ {
+		id, _ := gens[rng.IntN(len(gens))].NextID()
+		ids = append(ids, ID(id))
+		if rng.IntN(20) == 0 {
+			clock.Advance(time.Duration(rng.IntN(5)) * time.Millisecond)
+		}
+	}
+	slices.Sort(ids)
+
+	b, err := EncodeIDSet(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeIDSet(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, ids) {
+		t.Fatal("round trip changed the ids")
+	}
+	if ratio := float64(len(b)) / float64(8*len(ids)); ratio > 0.4 {
+		t.Errorf("encoded %d ids in %d bytes, %.0f%% of the raw size", len(ids), len(b), 100*ratio)
+	}
+
+	for _, set := range [][]ID{nil, {0}, {1<<63 - 1}, {0, 1, 1 << 22, 1<<22 + 5000, 1<<41 - 1}} {
+		b, err := EncodeIDSet(set)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := DecodeIDSet(b); err != nil || len(got) != len(set) || !slices.Equal(got, set) {
+			t.Errorf("round trip of %v gave %v, %v", set, got, err)
+		}
+	}
+
+	if _, err := EncodeIDSet([]ID{5, 5}); !errors.Is(err, ErrUnsortedIDs) {
+		t.Errorf("expected ErrUnsortedIDs for a duplicate, got %v", err)
+	}
+	if _, err := EncodeIDSet([]ID{5, 4}); !errors.Is(err, ErrUnsortedIDs) {
+		t.Errorf("expected ErrUnsortedIDs, got %v", err)
+	}
+
+	for _, bad := range [][]byte{nil, {2, 0}, {1, 200}, {1, 2, 5}, b[:len(b)-1], append(slices.Clone(b), 0)} {
+		if _, err := DecodeIDSet(bad); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("DecodeIDSet(%x...) = %v, want ErrInvalidEncoding", bad[:min(len(bad), 8)], err)
+		}
+	}
+}
+
+func FuzzDecodeIDSet(f *testing.F) {
+	b, _ := EncodeIDSet([]ID{1, 2, 1 << 22, 1<<22 | 1<<12})
+	f.Add(b)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		ids, err := DecodeIDSet(b)
+		if err != nil {
+			return
+		}
+		// Whatever decodes must be a valid set that survives a round trip.
+		again, err := EncodeIDSet(ids)
+		if err != nil {
+			t.Fatalf("decoded an invalid set %v: %v", ids, err)
+		}
+		if got, err := DecodeIDSet(again); err != nil || !slices.Equal(got, ids) {
+			t.Fatalf("round trip of %v gave %v, %v", ids, got, err)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package snowflake
+
+import "time"
+
+// InstagramLayout is the layout popularised by Instagram: a 41-bit
+// millisecond timestamp, a 13-bit logical shard ID in place of the machine
+// ID, and a 10-bit sequence.
+var InstagramLayout = Layout{TimeBits: 41, MachineBits: 13, SequenceBits: 10}
+
+// NewInstagram returns a generator using InstagramLayout that embeds shardID
+// in every ID, so the shard an entity lives on can be read from its ID. It
+// returns nil if shardID does not fit in 13 bits.
+func NewInstagram(epoch time.Time, shardID int, opts ...Option) *Snowflake {
+	if shardID < 0 || uint64(shardID) > InstagramLayout.MaxMachineID() {
+		return nil
+	}
+
+	return NewSnowflake(epoch, shardID, append(opts[:len(opts):len(opts)], WithLayout(InstagramLayout))...)
+}
+
+// ShardFor maps a user ID to one of shardCount logical shards, so IDs for a
+// user's entities can be generated on, and routed to, the user's shard
+// without a lookup. shardCount must not be zero.
+func ShardFor(userID, shardCount uint64) uint64 {
+	return userID % shardCount
+}
@@ -0,0 +1,41 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrInt64Overflow = errors.New("id does not fit in a positive int64")
+
+// NextID64 is like NextID but returns the ID as an int64 for databases and
+// schemas without unsigned integers. It never returns a negative ID: once the
+// timestamp needs the top bit, i.e. about 69 years after the epoch, it
+// returns ErrInt64Overflow instead.
+func (sf *Snowflake) NextID64() (int64, error) {
+	id, err := sf.NextID()
+	if err != nil {
+		return 0, err
+	}
+
+	return ID(id).Int64()
+}
+
+// Int64 returns id as an int64, or ErrInt64Overflow if its top bit is set.
+func (id ID) Int64() (int64, error) {
+	if id > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %d", ErrInt64Overflow, uint64(id))
+	}
+
+	return int64(id), nil
+}
+
+// FromInt64 converts an ID stored as int64 back into an ID. Negative values
+// cannot be snowflake IDs and return ErrInt64Overflow.
+func FromInt64(v int64) (ID, error) {
+	if v < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrInt64Overflow, v)
+	}
+
+	return ID(v), nil
+}
@@ -0,0 +1,41 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestNextID64(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+
+	v, err := sf.NextID64()
+	if err != nil || v <= 0 {
+		t.Fatalf("NextID64() = %d, %v", v, err)
+	}
+	if id, err := FromInt64(v); err != nil || id.Machine() != 1 {
+		t.Errorf("FromInt64(%d) = %d, %v", v, id, err)
+	}
+
+	// One tick before and at the point where the timestamp needs the top bit.
+	clock.Set(epoch.Add((1<<(EpochBits-1) - 1) * time.Millisecond))
+	if _, err := sf.NextID64(); err != nil {
+		t.Errorf("last positive tick should work, got %v", err)
+	}
+	clock.Advance(time.Millisecond)
+	if _, err := sf.NextID64(); !errors.Is(err, ErrInt64Overflow) {
+		t.Errorf("expected ErrInt64Overflow, got %v", err)
+	}
+
+	if _, err := ID(math.MaxInt64 + 1).Int64(); !errors.Is(err, ErrInt64Overflow) {
+		t.Errorf("expected ErrInt64Overflow, got %v", err)
+	}
+	if _, err := FromInt64(-1); !errors.Is(err, ErrInt64Overflow) {
+		t.Errorf("expected ErrInt64Overflow, got %v", err)
+	}
+}
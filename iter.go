@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// All returns an iterator over newly generated IDs. Iteration ends when the
+// consumer stops ranging, when ctx is done (yielding ctx.Err()) or after
+// yielding the first generation error.
+func (sf *Snowflake) All(ctx context.Context) iter.Seq2[ID, error] {
+	return func(yield func(ID, error) bool) {
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(0, err)
+				return
+			}
+
+			id, err := sf.NextIDContext(ctx)
+			if !yield(ID(id), err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// IDs returns an iterator over newly generated IDs for streaming producers:
+//
+//	for id, err := range sf.IDs(ctx) {
+//		if err != nil {
+//			// log, back off or break
+//			continue
+//		}
+//		...
+//	}
+//
+// Unlike All, a generation error is yielded for that iteration only and
+// iteration continues if the consumer keeps ranging, since errors such as
+// ErrRateLimited or a clock behind the last tick are transient. Iteration
+// ends when the consumer stops ranging, when ctx is done (yielding
+// ctx.Err()) or after yielding ErrClosed.
+func (sf *Snowflake) IDs(ctx context.Context) iter.Seq2[ID, error] {
+	return func(yield func(ID, error) bool) {
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(0, err)
+				return
+			}
+
+			id, err := sf.NextIDContext(ctx)
+			if !yield(ID(id), err) || errors.Is(err, ErrClosed) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestAll(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var n int
+	var last ID
+	var lastErr error
+	for id, err := range sf.All(ctx) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+		if n++; n == 100 {
+			cancel()
+		}
+	}
+
+	if n != 100 || !errors.Is(lastErr, context.Canceled) {
+		t.Errorf("expected 100 ids followed by context.Canceled, got %d ids and %v", n, lastErr)
+	}
+
+	// Breaking out of the loop must stop generation.
+	for range sf.All(context.Background()) {
+		break
+	}
+}
+
+func TestIDs(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithMaxIDsPerSecond(1, 3, RateLimitReject))
+
+	// Rate limit errors are yielded and iteration continues past them.
+	var ids, limited int
+	for _, err := range sf.IDs(context.Background()) {
+		switch {
+		case err == nil:
+			ids++
+		case errors.Is(err, ErrRateLimited):
+			limited++
+			clock.Advance(time.Second)
+		default:
+			t.Fatal(err)
+		}
+		if ids == 5 {
+			break
+		}
+	}
+	if limited == 0 {
+		t.Error("expected the rate limit error to be yielded")
+	}
+
+	// Closing the generator ends iteration.
+	var last error
+	n := 0
+	for _, err := range sf.IDs(context.Background()) {
+		last = err
+		if n++; n == 2 {
+			sf.Close()
+		}
+		if n > 10 {
+			t.Fatal("iteration did not stop after Close")
+		}
+	}
+	if !errors.Is(last, ErrClosed) {
+		t.Errorf("expected iteration to end with ErrClosed, got %v", last)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range sf.IDs(ctx) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	}
+}
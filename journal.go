@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JournalEntry records an issued ID, or the first ID of a reserved block.
+type JournalEntry struct {
+	ID    uint64    `json:"id,string"`
+	Count int       `json:"count"` // IDs issued, more than 1 for blocks
+	Time  time.Time `json:"time"`  // clock reading when the ID was issued
+	Label string    `json:"label,omitempty"`
+}
+
+// journal is a ring buffer of the latest entries. It is guarded by the
+// generator lock.
+type journal struct {
+	entries []JournalEntry
+	next    int
+	full    bool
+}
+
+// WithJournal keeps the last n issued IDs in memory, with the time they
+// were issued and the label given to NextIDLabeled, for investigating
+// where an ID came from. Journal and DumpJournal return them. Recording
+// costs a clock reading per ID. NewSnowflake returns nil if n is not
+// positive.
+func WithJournal(n int) Option {
+	return func(sf *Snowflake) {
+		sf.journal = &journal{entries: make([]JournalEntry, max(n, 0))}
+	}
+}
+
+// NextIDLabeled is NextID recording label, e.g. the calling component or
+// a request ID, with the ID in the journal.
+func (sf *Snowflake) NextIDLabeled(label string) (uint64, error) {
+	return sf.issue(context.Background(), idRequest{label: label})
+}
+
+// Journal returns the journal, oldest entry first, or nil without
+// WithJournal.
+func (sf *Snowflake) Journal() []JournalEntry {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	j := sf.journal
+	if j == nil {
+		return nil
+	}
+	if !j.full {
+		return append([]JournalEntry(nil), j.entries[:j.next]...)
+	}
+
+	return append(append([]JournalEntry(nil), j.entries[j.next:]...), j.entries[:j.next]...)
+}
+
+// DumpJournal writes the journal to w, oldest entry first, one tab
+// separated line per entry: time, ID, count and label.
+func (sf *Snowflake) DumpJournal(w io.Writer) error {
+	for _, e := range sf.Journal() {
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", e.Time.UTC().Format(time.RFC3339Nano), e.ID, e.Count, e.Label); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addJournal records n IDs starting at id. It must be called with
+// sf.mutex held.
+func (sf *Snowflake) addJournal(id uint64, n int, label string) {
+	j := sf.journal
+	j.entries[j.next] = JournalEntry{ID: id, Count: n, Time: sf.clock.Now(), Label: label}
+	j.next++
+	if j.next == len(j.entries) {
+		j.next, j.full = 0, true
+	}
+}
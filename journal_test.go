@@ -0,0 +1,54 @@
+package snowflake
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestJournal(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithJournal(3))
+
+	sf.NextID()
+	a, _ := sf.NextIDLabeled("a")
+	clock.Advance(time.Millisecond)
+	b, _ := sf.ReserveBlock(5)
+	c, _ := sf.NextIDLabeled("c")
+
+	got := sf.Journal()
+	want := []JournalEntry{
+		{ID: a, Count: 1, Time: epoch.Add(time.Hour), Label: "a"},
+		{ID: b.ID(0), Count: 5, Time: epoch.Add(time.Hour + time.Millisecond)},
+		{ID: c, Count: 1, Time: epoch.Add(time.Hour + time.Millisecond), Label: "c"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := sf.DumpJournal(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != "2020-01-01T01:00:00Z\t"+strconv.FormatUint(a, 10)+"\t1\ta" {
+		t.Errorf("unexpected dump %q", buf.String())
+	}
+
+	if NewSnowflake(epoch, 1, WithClock(clock)).Journal() != nil {
+		t.Error("expected no journal by default")
+	}
+	if NewSnowflake(epoch, 1, WithClock(clock), WithJournal(0)) != nil {
+		t.Error("expected nil for an empty journal")
+	}
+}
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+// LagBetween estimates how far a replica is behind its source from the
+// newest ID seen on each: the time between the timestamps of source and
+// replica, or 0 if the replica is not behind. Like the ID methods it
+// assumes DefaultLayout; Snowflake.LagBetween decodes other layouts. IDs
+// from different machines carry the skew of their clocks.
+func LagBetween(source, replica ID) time.Duration {
+	return max(source.Sub(replica), 0)
+}
+
+// LagBetween is the package-level LagBetween for IDs in the generator's
+// layout and time unit.
+func (sf *Snowflake) LagBetween(source, replica uint64) time.Duration {
+	s, _, _ := sf.layout.Decompose(source)
+	r, _, _ := sf.layout.Decompose(replica)
+	if r >= s {
+		return 0
+	}
+
+	return time.Duration(s-r) * sf.unit
+}
+
+// LagMonitor tracks the newest IDs seen on a source and a replica, e.g.
+// the primary and a follower database or the producer and consumer side
+// of a queue, to report the replication lag or backlog between them. It is
+// safe for concurrent use.
+type LagMonitor struct {
+	decoder *Snowflake
+
+	mu              sync.Mutex
+	source, replica uint64
+}
+
+// NewLagMonitor returns a LagMonitor decoding IDs with decoder.
+func NewLagMonitor(decoder *Snowflake) *LagMonitor {
+	return &LagMonitor{decoder: decoder}
+}
+
+// ObserveSource records an ID seen on the source. Older IDs than the
+// newest one seen are ignored.
+func (m *LagMonitor) ObserveSource(id uint64) {
+	m.mu.Lock()
+	m.source = max(m.source, id)
+	m.mu.Unlock()
+}
+
+// ObserveReplica records an ID seen on the replica.
+func (m *LagMonitor) ObserveReplica(id uint64) {
+	m.mu.Lock()
+	m.replica = max(m.replica, id)
+	m.mu.Unlock()
+}
+
+// Lag returns how far the newest replica ID is behind the newest source
+// ID. It is 0 until both sides have been observed.
+func (m *LagMonitor) Lag() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.source == 0 || m.replica == 0 {
+		return 0
+	}
+
+	return m.decoder.LagBetween(m.source, m.replica)
+}
+
+// ReplicaAge returns the time since the newest replica ID was issued, by
+// the decoder's clock. Unlike Lag it keeps growing while the source is
+// idle, so it bounds the lag from above.
+func (m *LagMonitor) ReplicaAge() time.Duration {
+	m.mu.Lock()
+	replica := m.replica
+	m.mu.Unlock()
+
+	return max(m.decoder.clock.Now().Sub(m.decoder.IDToTime(replica)), 0)
+}
@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestLagBetween(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithTimeUnit(TimeUnit10Milliseconds))
+
+	replica, _ := sf.NextID()
+	clock.Advance(1500 * time.Millisecond)
+	source, _ := sf.NextID()
+
+	if got := sf.LagBetween(source, replica); got != 1500*time.Millisecond {
+		t.Errorf("LagBetween = %s, want 1.5s", got)
+	}
+	if got := sf.LagBetween(replica, source); got != 0 {
+		t.Errorf("LagBetween of a replica ahead = %s, want 0", got)
+	}
+
+	a := ID(DefaultLayout.Compose(5000, 1, 0))
+	b := ID(DefaultLayout.Compose(3000, 2, 9))
+	if got := LagBetween(a, b); got != 2*time.Second {
+		t.Errorf("LagBetween = %s, want 2s", got)
+	}
+}
+
+func TestLagMonitor(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+	m := NewLagMonitor(sf)
+
+	first, _ := sf.NextID()
+	m.ObserveSource(first)
+	if m.Lag() != 0 {
+		t.Error("expected no lag before the replica was observed")
+	}
+	m.ObserveReplica(first)
+
+	clock.Advance(time.Second)
+	second, _ := sf.NextID()
+	m.ObserveSource(second)
+	m.ObserveSource(first) // late delivery
+	if got := m.Lag(); got != time.Second {
+		t.Errorf("Lag = %s, want 1s", got)
+	}
+
+	clock.Advance(time.Second)
+	if got := m.ReplicaAge(); got != 2*time.Second {
+		t.Errorf("ReplicaAge = %s, want 2s", got)
+	}
+
+	m.ObserveReplica(second)
+	if got := m.Lag(); got != 0 {
+		t.Errorf("Lag = %s after catching up", got)
+	}
+}
@@ -0,0 +1,125 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidLayout = errors.New("invalid layout")
+
+// Layout describes how the bits of an ID are split between the timestamp,
+// tag, machine ID and sequence fields, from most to least significant.
+// Unused high bits are always zero.
+//
+// The optional tag field holds an application value chosen per ID with
+// NextIDWithTag, e.g. to tell test from production IDs or entity classes
+// apart. It sits below the timestamp, so tagged IDs still sort by time.
+//
+// Order and LittleEndian only matter for IDs from legacy systems with a
+// different bit order or byte layout, see FieldOrder and AppendID.
+type Layout struct {
+	TimeBits     uint       `json:"time_bits"`
+	TagBits      uint       `json:"tag_bits,omitempty"`
+	MachineBits  uint       `json:"machine_bits"`
+	SequenceBits uint       `json:"sequence_bits"`
+	Order        FieldOrder `json:"order,omitzero"`
+	LittleEndian bool       `json:"little_endian,omitempty"`
+}
+
+// DefaultLayout is the layout used unless WithLayout is given.
+var DefaultLayout = Layout{TimeBits: EpochBits, MachineBits: MachineIDBits, SequenceBits: SequenceBits}
+
+// Validate checks that the fields fit in an ID and that the sequence fits
+// the generator's 16-bit counter.
+func (l Layout) Validate() error {
+	switch {
+	case l.TimeBits == 0:
+		return fmt.Errorf("%w: no time bits", ErrInvalidLayout)
+	case l.SequenceBits == 0 || l.SequenceBits > 16:
+		return fmt.Errorf("%w: sequence bits must be between 1 and 16", ErrInvalidLayout)
+	case l.TagBits > 8:
+		return fmt.Errorf("%w: tag bits must be at most 8", ErrInvalidLayout)
+	case l.TimeBits+l.timeShift() > TotalBits:
+		return fmt.Errorf("%w: %d bits do not fit in %d", ErrInvalidLayout, l.TimeBits+l.timeShift(), TotalBits)
+	}
+
+	return l.Order.validate()
+}
+
+func (l Layout) MaxTimestamp() uint64 { return 1<<l.TimeBits - 1 }
+func (l Layout) MaxMachineID() uint64 { return 1<<l.MachineBits - 1 }
+func (l Layout) MaxSequence() uint64  { return 1<<l.SequenceBits - 1 }
+func (l Layout) MaxTag() uint64       { return 1<<l.TagBits - 1 }
+
+// timeShift is the position of the timestamp field.
+func (l Layout) timeShift() uint { return l.TagBits + l.MachineBits + l.SequenceBits }
+
+// String returns the field widths as time/machine/sequence bits, e.g.
+// "42/10/12", or time/tag/machine/sequence bits with a tag field. A
+// custom field order is appended, e.g. "41/10/12 machine,time,tag,sequence".
+func (l Layout) String() string {
+	s := fmt.Sprintf("%d/%d/%d", l.TimeBits, l.MachineBits, l.SequenceBits)
+	if l.TagBits > 0 {
+		s = fmt.Sprintf("%d/%d/%d/%d", l.TimeBits, l.TagBits, l.MachineBits, l.SequenceBits)
+	}
+	if l.Order != (FieldOrder{}) && l.Order != defaultOrder {
+		s += fmt.Sprintf(" %v,%v,%v,%v", l.Order[0], l.Order[1], l.Order[2], l.Order[3])
+	}
+	if l.LittleEndian {
+		s += " little-endian"
+	}
+
+	return s
+}
+
+// Compose builds an untagged ID from its fields, which must be within
+// range.
+func (l Layout) Compose(ts, machineID, seq uint64) uint64 {
+	return ts<<l.shift(FieldTime) | machineID<<l.shift(FieldMachine) | seq<<l.shift(FieldSequence)
+}
+
+// ComposeTagged is Compose with a value for the tag field.
+func (l Layout) ComposeTagged(ts, tag, machineID, seq uint64) uint64 {
+	return l.Compose(ts, machineID, seq) | tag<<l.shift(FieldTag)
+}
+
+// Tag returns the tag field of id.
+func (l Layout) Tag(id uint64) uint64 {
+	return id >> l.shift(FieldTag) & l.MaxTag()
+}
+
+// untagged returns id with the tag field cleared.
+func (l Layout) untagged(id uint64) uint64 {
+	return id &^ (l.MaxTag() << l.shift(FieldTag))
+}
+
+// Decompose splits id into timestamp, machine ID and sequence.
+func (l Layout) Decompose(id uint64) (uint64, uint64, uint64) {
+	t := id >> l.shift(FieldTime) & l.MaxTimestamp()
+	mid := id >> l.shift(FieldMachine) & l.MaxMachineID()
+	seq := id >> l.shift(FieldSequence) & l.MaxSequence()
+
+	return t, mid, seq
+}
+
+// orDefault maps the zero Layout to DefaultLayout.
+func (l Layout) orDefault() Layout {
+	if l == (Layout{}) {
+		return DefaultLayout
+	}
+
+	return l
+}
+
+// WithLayout makes the generator use l instead of DefaultLayout. NewSnowflake
+// returns nil if l is invalid or does not put the timestamp on top.
+func WithLayout(l Layout) Option {
+	return func(sf *Snowflake) {
+		sf.layout = l
+	}
+}
+
+// Layout returns the generator's bit layout.
+func (sf *Snowflake) Layout() Layout {
+	return sf.layout
+}
@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestLayoutValidate(t *testing.T) {
+	for _, l := range []Layout{DefaultLayout, InstagramLayout, {TimeBits: 41, SequenceBits: 16}} {
+		if err := l.Validate(); err != nil {
+			t.Errorf("%+v should be valid: %v", l, err)
+		}
+	}
+
+	for _, l := range []Layout{{}, {TimeBits: 42, MachineBits: 10}, {TimeBits: 42, MachineBits: 11, SequenceBits: 12}, {TimeBits: 40, SequenceBits: 17}} {
+		if err := l.Validate(); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("%+v should be invalid, got %v", l, err)
+		}
+	}
+
+	if NewSnowflake(time.Time{}, 1, WithLayout(Layout{TimeBits: 64, SequenceBits: 1})) != nil {
+		t.Error("NewSnowflake should reject an invalid layout")
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	for _, l := range []Layout{DefaultLayout, InstagramLayout} {
+		for _, f := range [][3]uint64{{0, 0, 0}, {1, 2, 3}, {l.MaxTimestamp(), l.MaxMachineID(), l.MaxSequence()}} {
+			id := l.Compose(f[0], f[1], f[2])
+			if ts, mid, seq := l.Decompose(id); ts != f[0] || mid != f[1] || seq != f[2] {
+				t.Errorf("%+v: %v decomposed to %d %d %d", l, f, ts, mid, seq)
+			}
+		}
+	}
+}
+
+func TestInstagram(t *testing.T) {
+	epoch := time.Date(2011, 8, 24, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	if NewInstagram(epoch, 1<<13) != nil {
+		t.Error("shard ids above 13 bits should be rejected")
+	}
+
+	shard := ShardFor(31341, 2000)
+	sf := NewInstagram(epoch, int(shard), WithClock(clock))
+
+	var last uint64
+	for i := 0; i < 1<<10+1; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+	}
+
+	ts, mid, seq := InstagramLayout.Decompose(last)
+	if mid != 1341 || seq != 0 || ts != uint64(time.Hour/time.Millisecond)+1 {
+		t.Errorf("unexpected parts ts %d shard %d seq %d", ts, mid, seq)
+	}
+	if !sf.IDToTime(last).Equal(clock.Now()) {
+		t.Errorf("IDToTime = %s, want %s", sf.IDToTime(last), clock.Now())
+	}
+}
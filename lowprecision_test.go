@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestCoarsen(t *testing.T) {
+	tests := []struct {
+		layout Layout
+		factor int
+		want   Layout
+	}{
+		{DefaultLayout, 10, Layout{TimeBits: 39, MachineBits: 10, SequenceBits: 15}},
+		{DefaultLayout, 16, Layout{TimeBits: 38, MachineBits: 10, SequenceBits: 16}},
+		{Layout{TimeBits: 41, TagBits: 2, MachineBits: 10, SequenceBits: 10}, 100, Layout{TimeBits: 35, TagBits: 2, MachineBits: 10, SequenceBits: 16}},
+	}
+	for _, tt := range tests {
+		got, err := tt.layout.Coarsen(tt.factor)
+		if err != nil || got != tt.want {
+			t.Errorf("%s.Coarsen(%d) = %s, %v, want %s", tt.layout, tt.factor, got, err, tt.want)
+		}
+	}
+
+	for _, factor := range []int{0, 1, 100} {
+		if _, err := DefaultLayout.Coarsen(factor); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("Coarsen(%d): expected ErrInvalidLayout, got %v", factor, err)
+		}
+	}
+}
+
+func TestLowPrecision(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := clocktest.NewFakeClock(start)
+
+	sf := NewSnowflake(epoch, 1, WithLowPrecision(TimeUnit10Milliseconds), WithClock(clock))
+	if sf.TimeUnit() != TimeUnit10Milliseconds || sf.Layout().SequenceBits != 15 {
+		t.Fatalf("unexpected unit %s and layout %s", sf.TimeUnit(), sf.Layout())
+	}
+	if sf.Layout().Lifetime(sf.TimeUnit()) < DefaultLayout.Lifetime(time.Millisecond) {
+		t.Error("coarse layout runs out earlier")
+	}
+	for i := 0; i < 1<<15; i++ {
+		if _, err := sf.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !clock.Now().Equal(start) || sf.Stats().Rollovers != 0 {
+		t.Errorf("rolled over %d times", sf.Stats().Rollovers)
+	}
+
+	coarse := NewSnowflake(epoch, 1, WithClock(clock), WithLowPrecision(TimeUnit100Milliseconds),
+		WithLayout(Layout{TimeBits: 41, MachineBits: 12, SequenceBits: 10}))
+	if coarse == nil || coarse.Layout() != (Layout{TimeBits: 35, MachineBits: 12, SequenceBits: 16}) {
+		t.Errorf("unexpected layout %v", coarse)
+	}
+
+	if NewSnowflake(epoch, 1, WithClock(clock), WithLowPrecision(TimeUnit100Milliseconds)) != nil {
+		t.Error("expected nil for a sequence wider than 16 bits")
+	}
+	if NewSnowflake(epoch, 1, WithClock(clock), WithLowPrecision(1500*time.Microsecond)) != nil {
+		t.Error("expected nil for a unit that is not a multiple of the time unit")
+	}
+}
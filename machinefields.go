@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrMachineFieldRange = errors.New("machine field out of range")
+
+// MachineField is a named group of machine ID bits.
+type MachineField struct {
+	Name string
+	Bits uint
+}
+
+// MachineFields splits the machine ID into named sub-fields, from most to
+// least significant.
+type MachineFields []MachineField
+
+// RegionWorkerFields splits the default 10 machine bits into a 3-bit region
+// and a 7-bit worker.
+var RegionWorkerFields = MachineFields{{Name: "region", Bits: 3}, {Name: "worker", Bits: 7}}
+
+// Bits returns the total width of the fields.
+func (f MachineFields) Bits() uint {
+	var n uint
+	for _, field := range f {
+		n += field.Bits
+	}
+
+	return n
+}
+
+// Compose packs one value per field into a machine ID. It returns
+// ErrMachineFieldRange if the number of values is wrong or a value does not
+// fit its field.
+func (f MachineFields) Compose(values ...uint64) (uint64, error) {
+	if len(values) != len(f) {
+		return 0, fmt.Errorf("%w: got %d values for %d fields", ErrMachineFieldRange, len(values), len(f))
+	}
+
+	var mid uint64
+	for i, field := range f {
+		if values[i] > 1<<field.Bits-1 {
+			return 0, fmt.Errorf("%w: %s %d does not fit in %d bits", ErrMachineFieldRange, field.Name, values[i], field.Bits)
+		}
+		mid = mid<<field.Bits | values[i]
+	}
+
+	return mid, nil
+}
+
+// Decompose splits a machine ID into its fields, keyed by name.
+func (f MachineFields) Decompose(mid uint64) map[string]uint64 {
+	values := make(map[string]uint64, len(f))
+	for i := len(f) - 1; i >= 0; i-- {
+		values[f[i].Name] = mid & (1<<f[i].Bits - 1)
+		mid >>= f[i].Bits
+	}
+
+	return values
+}
+
+// WithMachineFields declares the structure of the machine ID, which
+// Decompose then reports per field. NewSnowflake returns nil if the fields
+// do not cover exactly the layout's machine bits.
+func WithMachineFields(f MachineFields) Option {
+	return func(sf *Snowflake) {
+		sf.machineFields = f
+	}
+}
+
+// NewPartitioned returns a generator whose machine ID is composed from one
+// value per field. It returns nil if a value is out of range or the fields
+// do not match the layout.
+func NewPartitioned(starttime time.Time, fields MachineFields, values []uint64, opts ...Option) *Snowflake {
+	mid, err := fields.Compose(values...)
+	if err != nil {
+		return nil
+	}
+
+	return NewSnowflake(starttime, int(mid), append([]Option{WithMachineFields(fields)}, opts...)...)
+}
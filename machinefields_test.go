@@ -0,0 +1,42 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMachineFields(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sf := NewPartitioned(epoch, RegionWorkerFields, []uint64{5, 100})
+	if sf == nil {
+		t.Fatal("expected a generator")
+	}
+	if want := uint64(5<<7 | 100); sf.MachineID() != want {
+		t.Errorf("got machine id %d, want %d", sf.MachineID(), want)
+	}
+
+	id, _ := sf.NextID()
+	p := sf.Decompose(id)
+	if p.Machine["region"] != 5 || p.Machine["worker"] != 100 {
+		t.Errorf("got fields %v", p.Machine)
+	}
+
+	if NewPartitioned(epoch, RegionWorkerFields, []uint64{8, 0}) != nil {
+		t.Error("expected region 8 to be out of range")
+	}
+	if NewPartitioned(epoch, RegionWorkerFields, []uint64{1}) != nil {
+		t.Error("expected a missing value to be rejected")
+	}
+	if NewSnowflake(epoch, 1, WithMachineFields(MachineFields{{Name: "worker", Bits: 9}})) != nil {
+		t.Error("expected fields not covering the machine bits to be rejected")
+	}
+
+	if _, err := RegionWorkerFields.Compose(0, 128); !errors.Is(err, ErrMachineFieldRange) {
+		t.Errorf("expected ErrMachineFieldRange, got %v", err)
+	}
+	if p := NewSnowflake(epoch, 1).Decompose(id); p.Machine != nil {
+		t.Errorf("expected no fields without WithMachineFields, got %v", p.Machine)
+	}
+}
@@ -0,0 +1,42 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetMachineID makes every ID issued after it returns carry machine ID id,
+// e.g. after a leased ID was lost and a new one acquired. Calls in flight
+// finish with the previous ID first.
+//
+// It returns ErrInvalidMachineID if id does not fit the layout, is not
+// among WithAllowedMachineIDs or sets the WithRandomFallback flag, and
+// ErrClosed after Shutdown. With a
+// MachineIDProvider, id must have been acquired from it; the previous ID is
+// released to it with ctx, and Shutdown releases id instead.
+func (sf *Snowflake) SetMachineID(ctx context.Context, id uint64) error {
+	if id > sf.layout.MaxMachineID() {
+		return fmt.Errorf("%w: %d is not between 0 and %d", ErrInvalidMachineID, id, sf.layout.MaxMachineID())
+	}
+	if !sf.machineAllowed(id) {
+		return fmt.Errorf("%w: %d is not allowed", ErrInvalidMachineID, id)
+	}
+	if sf.randomFallback && sf.IsDegraded(sf.layout.Compose(0, id, 0)) {
+		return fmt.Errorf("%w: %d has the degraded ID flag set", ErrInvalidMachineID, id)
+	}
+
+	sf.mutex.Lock()
+	if sf.closed {
+		sf.mutex.Unlock()
+		return ErrClosed
+	}
+	old := sf.machineID
+	sf.machineID = id
+	sf.mutex.Unlock()
+
+	if sf.provider != nil && old != id {
+		return sf.provider.Release(ctx, old)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestSetMachineID(t *testing.T) {
+	ctx := context.Background()
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	pool := &poolProvider{free: []uint64{1, 2, 3}}
+
+	sf := NewSnowflake(epoch, 0, WithClock(clock), WithMachineIDProvider(pool))
+	if sf.MachineID() != 1 {
+		t.Fatalf("expected machine id 1, got %d", sf.MachineID())
+	}
+
+	// Generators keep issuing IDs while the machine ID is swapped.
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					sf.NextID()
+				}
+			}
+		}()
+	}
+
+	next, _ := pool.Acquire(ctx)
+	if err := sf.SetMachineID(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		id, err := sf.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, mid, _ := DecomposeParts(id); mid != next {
+			t.Fatalf("id %d issued with machine id %d after switching to %d", id, mid, next)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if !slices.Contains(pool.free, 1) {
+		t.Errorf("machine id 1 was not released: %v", pool.free)
+	}
+
+	if err := sf.SetMachineID(ctx, 1024); !errors.Is(err, ErrInvalidMachineID) {
+		t.Errorf("expected ErrInvalidMachineID, got %v", err)
+	}
+	allowed := NewSnowflake(epoch, 1, WithClock(clock), WithAllowedMachineIDs(1, 2))
+	if err := allowed.SetMachineID(ctx, 3); !errors.Is(err, ErrInvalidMachineID) {
+		t.Errorf("expected ErrInvalidMachineID for a machine id not allowed, got %v", err)
+	}
+
+	if err := sf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(pool.free, next) {
+		t.Errorf("Shutdown did not release machine id %d: %v", next, pool.free)
+	}
+	if err := sf.SetMachineID(ctx, 3); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package snowflake
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Manager lazily creates one generator per tenant and keeps at most a fixed
+// number of them, shutting down the least recently used one when the bound
+// is reached.
+//
+// An evicted tenant gets a new generator from the factory on its next call.
+// The new generator is fast-forwarded past the last tick issued by any
+// evicted generator, so it cannot repeat an ID even if it reuses the same
+// epoch and machine ID.
+type Manager struct {
+	factory func(tenant string) *Snowflake
+	max     int
+
+	mu      sync.Mutex
+	lru     *list.List // of *tenantGenerator, most recently used first
+	tenants map[string]*list.Element
+	horizon time.Time
+	closed  bool
+}
+
+type tenantGenerator struct {
+	tenant string
+	gen    *Snowflake
+}
+
+// NewManager returns a Manager creating generators with factory, which
+// returns nil if a tenant's generator cannot be created, and keeping at
+// most maxTenants of them. Zero means no bound.
+func NewManager(factory func(tenant string) *Snowflake, maxTenants int) *Manager {
+	return &Manager{
+		factory: factory,
+		max:     maxTenants,
+		lru:     list.New(),
+		tenants: make(map[string]*list.Element),
+	}
+}
+
+// Generator returns the tenant's generator, creating it if needed. The
+// generator is closed if it is later evicted, so callers should not hold on
+// to it; NextID looks it up on every call.
+func (m *Manager) Generator(tenant string) (*Snowflake, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	if e, ok := m.tenants[tenant]; ok {
+		m.lru.MoveToFront(e)
+		return e.Value.(*tenantGenerator).gen, nil
+	}
+
+	gen := m.factory(tenant)
+	if gen == nil {
+		return nil, fmt.Errorf("generator for tenant %q could not be created", tenant)
+	}
+	gen.fastForward(m.horizon)
+
+	m.tenants[tenant] = m.lru.PushFront(&tenantGenerator{tenant: tenant, gen: gen})
+	for m.max > 0 && m.lru.Len() > m.max {
+		m.evict(m.lru.Back())
+	}
+
+	return gen, nil
+}
+
+// NextID issues an ID from the tenant's generator.
+func (m *Manager) NextID(tenant string) (uint64, error) {
+	gen, err := m.Generator(tenant)
+	if err != nil {
+		return 0, err
+	}
+
+	return gen.NextID()
+}
+
+// Len returns the number of live generators.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lru.Len()
+}
+
+// Shutdown shuts down all generators, see Snowflake.Shutdown. Afterwards
+// Generator and NextID return ErrClosed.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+	m.closed = true
+
+	var errs []error
+	for e := m.lru.Front(); e != nil; e = e.Next() {
+		tg := e.Value.(*tenantGenerator)
+		if err := tg.gen.Shutdown(ctx); err != nil && !errors.Is(err, ErrClosed) {
+			errs = append(errs, fmt.Errorf("tenant %q: %w", tg.tenant, err))
+		}
+	}
+	m.lru.Init()
+	clear(m.tenants)
+
+	return errors.Join(errs...)
+}
+
+// Close is Shutdown without a deadline.
+func (m *Manager) Close() error {
+	return m.Shutdown(context.Background())
+}
+
+// evict must be called with m.mu held.
+func (m *Manager) evict(e *list.Element) {
+	tg := m.lru.Remove(e).(*tenantGenerator)
+	delete(m.tenants, tg.tenant)
+
+	tg.gen.Close()
+	if t := tg.gen.LastTime(); t.After(m.horizon) {
+		m.horizon = t
+	}
+}
+
+// fastForward makes sure the generator issues no ID in a tick up to and
+// including the one containing t.
+func (sf *Snowflake) fastForward(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	if ts := sf.toUnit(t) - sf.startTime; ts >= sf.lastTimestamp {
+		sf.lastTimestamp = ts
+		sf.seqIndex = sf.seqMask
+	}
+}
@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestManager(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	tenantIDs := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	created := 0
+	m := NewManager(func(tenant string) *Snowflake {
+		mid, ok := tenantIDs[tenant]
+		if !ok {
+			return nil
+		}
+		created++
+		return NewSnowflake(epoch, mid, WithClock(clock))
+	}, 2)
+
+	seen := make(map[uint64]bool)
+	issue := func(tenant string) uint64 {
+		t.Helper()
+		id, err := m.NextID(tenant)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d for tenant %s", id, tenant)
+		}
+		seen[id] = true
+		return id
+	}
+
+	a := issue("a")
+	issue("b")
+	issue("a")
+	issue("c") // evicts b, the least recently used
+
+	if m.Len() != 2 || created != 3 {
+		t.Fatalf("expected 2 live of 3 created generators, got %d of %d", m.Len(), created)
+	}
+
+	// b comes back in the same tick and must not repeat its first ID.
+	if id := issue("b"); id <= a || created != 4 {
+		t.Errorf("expected a fresh generator for b, got id %d after %d created", id, created)
+	}
+
+	if _, err := m.NextID("unknown"); err == nil {
+		t.Error("expected an error for a tenant the factory rejects")
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.NextID("a"); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed after Close, got %v", err)
+	}
+}
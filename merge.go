@@ -0,0 +1,144 @@
+package snowflake
+
+import (
+	"container/heap"
+	"context"
+	"iter"
+	"reflect"
+	"time"
+)
+
+// MergeSorted merges streams of ascending IDs, such as the output of one
+// generator each, into a single ascending stream. IDs in the same layout
+// sort by time first, so the result is in time order.
+func MergeSorted(streams ...iter.Seq[uint64]) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		nexts := make([]func() (uint64, bool), len(streams))
+		var h mergeHeap
+		for i, s := range streams {
+			next, stop := iter.Pull(s)
+			defer stop()
+			nexts[i] = next
+			if id, ok := next(); ok {
+				h = append(h, mergeHead{id: id, stream: i})
+			}
+		}
+		heap.Init(&h)
+
+		for len(h) > 0 {
+			head := h[0]
+			if !yield(head.id) {
+				return
+			}
+			if id, ok := nexts[head.stream](); ok {
+				h[0].id = id
+				heap.Fix(&h, 0)
+			} else {
+				heap.Pop(&h)
+			}
+		}
+	}
+}
+
+// MergeChannels merges live per-machine streams of IDs into a single
+// stream in time order, as decoded by decoder. IDs arriving out of order
+// are put back in order as long as they are at most window late: an ID is
+// held back until the latest time seen in any stream, or the decoder's
+// clock, is window past its own time. IDs arriving later than that are
+// passed on immediately, out of order.
+//
+// The returned channel is closed once all streams are closed and the IDs
+// still held back are flushed, or when ctx is done, dropping them.
+// Consumers must drain it or cancel ctx.
+func MergeChannels(ctx context.Context, decoder *Snowflake, window time.Duration, streams ...<-chan uint64) <-chan uint64 {
+	out := make(chan uint64)
+	go func() {
+		defer close(out)
+
+		tick := time.NewTicker(max(window/2, time.Millisecond))
+		defer tick.Stop()
+
+		var (
+			pending mergeHeap
+			latest  time.Time
+		)
+		// flush sends the IDs older than the watermark, or all of them.
+		flush := func(all bool) bool {
+			watermark := decoder.clock.Now()
+			if latest.After(watermark) {
+				watermark = latest
+			}
+			watermark = watermark.Add(-window)
+			for len(pending) > 0 {
+				id := pending[0].id
+				if !all && decoder.IDToTime(id).After(watermark) {
+					return true
+				}
+				select {
+				case out <- id:
+				case <-ctx.Done():
+					return false
+				}
+				heap.Pop(&pending)
+			}
+
+			return true
+		}
+
+		// Receive directly from the streams so that an ID is in pending as
+		// soon as its send completes.
+		cases := make([]reflect.SelectCase, 0, len(streams)+2)
+		cases = append(cases,
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(tick.C)})
+		for _, s := range streams {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s)})
+		}
+
+		for open := len(streams); open > 0; {
+			i, v, ok := reflect.Select(cases)
+			switch {
+			case i == 0:
+				return
+			case i == 1:
+			case !ok:
+				// A nil channel blocks forever, disabling the case.
+				cases[i].Chan = reflect.ValueOf((<-chan uint64)(nil))
+				open--
+			default:
+				id := v.Uint()
+				if t := decoder.IDToTime(id); t.After(latest) {
+					latest = t
+				}
+				heap.Push(&pending, mergeHead{id: id})
+			}
+
+			if !flush(false) {
+				return
+			}
+		}
+		flush(true)
+	}()
+
+	return out
+}
+
+type mergeHead struct {
+	id     uint64
+	stream int
+}
+
+type mergeHeap []mergeHead
+
+func (h mergeHeap) Len() int           { return len(h) }
+func (h mergeHeap) Less(i, j int) bool { return h[i].id < h[j].id }
+func (h mergeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x any)        { *h = append(*h, x.(mergeHead)) }
+
+func (h *mergeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return x
+}
@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestMergeSorted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	var streams [][]uint64
+	for mid := 1; mid <= 3; mid++ {
+		sf := NewSnowflake(epoch, mid, WithClock(clock))
+		var ids []uint64
+		for i := 0; i < 50; i++ {
+			id, _ := sf.NextID()
+			ids = append(ids, id)
+			clock.Advance(time.Duration(mid) * time.Millisecond)
+		}
+		streams = append(streams, ids)
+	}
+
+	var want []uint64
+	for _, s := range streams {
+		want = append(want, s...)
+	}
+	slices.Sort(want)
+
+	got := slices.Collect(MergeSorted(slices.Values(streams[0]), slices.Values(streams[1]), slices.Values(streams[2])))
+	if !slices.Equal(got, want) {
+		t.Errorf("merged stream out of order:\n%v\nwant\n%v", got, want)
+	}
+
+	// Stopping early must stop the pulled streams.
+	for range MergeSorted(slices.Values(streams[0]), slices.Values(streams[1])) {
+		break
+	}
+}
+
+func TestMergeChannels(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	decoder := NewSnowflake(epoch, 0, WithClock(clock))
+	at := func(ms, mid int) uint64 {
+		return decoder.TimeToSnowflakeID(epoch.Add(time.Hour+time.Duration(ms)*time.Millisecond)) | uint64(mid)<<SequenceBits
+	}
+
+	a, b := make(chan uint64), make(chan uint64)
+	out := MergeChannels(context.Background(), decoder, 10*time.Millisecond, a, b)
+
+	// b's ID at 3ms arrives after a's at 5ms, within the window.
+	a <- at(5, 1)
+	b <- at(3, 2)
+	// An ID 20ms later pushes the watermark past both.
+	a <- at(25, 1)
+	for _, want := range []uint64{at(3, 2), at(5, 1)} {
+		if got := <-out; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	}
+
+	b <- at(20, 2)
+	close(a)
+	close(b)
+	var rest []uint64
+	for id := range out {
+		rest = append(rest, id)
+	}
+	if !slices.Equal(rest, []uint64{at(20, 2), at(25, 1)}) {
+		t.Errorf("unexpected flush %v", rest)
+	}
+}
+
+func TestMergeChannelsCancel(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	decoder := NewSnowflake(epoch, 0, WithClock(clocktest.NewFakeClock(epoch.Add(time.Hour))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a := make(chan uint64)
+	out := MergeChannels(ctx, decoder, time.Minute, a)
+	a <- decoder.TimeToSnowflakeID(epoch.Add(time.Hour))
+	cancel()
+
+	for range out {
+	}
+}
@@ -0,0 +1,32 @@
+package snowflake
+
+import "time"
+
+// Metrics receives instrumentation events from a generator. Methods are
+// called while the generator lock is held and must be cheap, e.g. atomic
+// counter updates.
+type Metrics interface {
+	// IDsGenerated is called with the number of IDs issued by NextID or
+	// reserved by ReserveBlock.
+	IDsGenerated(n int)
+	// SequenceRollover is called when a tick's sequence space is exhausted.
+	SequenceRollover()
+	// Waited is called with the time spent sleeping for the next tick.
+	Waited(d time.Duration)
+	// ClockBackwards is called when the clock is read earlier than before.
+	ClockBackwards(d time.Duration)
+}
+
+// WithMetrics reports generator events to m.
+func WithMetrics(m Metrics) Option {
+	return func(sf *Snowflake) {
+		sf.metrics = m
+	}
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) IDsGenerated(int)             {}
+func (nopMetrics) SequenceRollover()            {}
+func (nopMetrics) Waited(time.Duration)         {}
+func (nopMetrics) ClockBackwards(time.Duration) {}
@@ -0,0 +1,47 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+type countingMetrics struct {
+	ids, rollovers, backwards int
+	waited                    time.Duration
+}
+
+func (m *countingMetrics) IDsGenerated(n int)             { m.ids += n }
+func (m *countingMetrics) SequenceRollover()              { m.rollovers++ }
+func (m *countingMetrics) Waited(d time.Duration)         { m.waited += d }
+func (m *countingMetrics) ClockBackwards(d time.Duration) { m.backwards++ }
+
+func TestMetrics(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	m := new(countingMetrics)
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithMetrics(m))
+
+	for i := 0; i < 1<<SequenceBits+1; i++ {
+		sf.NextID()
+	}
+	sf.ReserveBlock(10)
+
+	clock.Advance(-5 * time.Millisecond)
+	sf.NextID()
+	sf.NextID()
+
+	if m.ids != 1<<SequenceBits+13 {
+		t.Errorf("expected %d ids, got %d", 1<<SequenceBits+13, m.ids)
+	}
+	if m.rollovers != 1 {
+		t.Errorf("expected 1 rollover, got %d", m.rollovers)
+	}
+	if m.waited != time.Millisecond {
+		t.Errorf("expected to wait 1ms, waited %s", m.waited)
+	}
+	if m.backwards != 1 {
+		t.Errorf("expected 1 clock backwards event, got %d", m.backwards)
+	}
+}
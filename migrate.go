@@ -0,0 +1,147 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMigrationOverlap = errors.New("new IDs may collide with legacy IDs")
+	ErrNoTranslation    = errors.New("no translation for ID")
+)
+
+// TranslationTable records which new ID was issued alongside which legacy
+// ID, so references can be rewritten in either direction.
+type TranslationTable interface {
+	StoreTranslation(ctx context.Context, oldID, newID uint64) error
+	// NewID and OldID return ErrNoTranslation for unknown IDs.
+	NewID(ctx context.Context, oldID uint64) (uint64, error)
+	OldID(ctx context.Context, newID uint64) (uint64, error)
+}
+
+// Migrator dual-writes IDs from a legacy generator and its replacement
+// during a cutover window, e.g. when moving from a 41/5/5/13 scheme,
+// declared as Layout{41, 10, 13} with two 5-bit MachineFields, to
+// DefaultLayout.
+//
+// The two generators may differ in layout, epoch and time unit. NewMigrator
+// checks that every legacy ID issued until the end of the window is below
+// every new ID, so the two ID spaces can share a column and IsLegacy can
+// tell them apart. The new generator usually needs an earlier epoch than
+// the legacy one for that.
+type Migrator struct {
+	legacy, next *Snowflake
+	table        TranslationTable
+	until        time.Time
+	boundary     uint64 // smallest ID the new generator can still issue
+}
+
+// NewMigrator returns a Migrator issuing pairs from legacy and next until the
+// time until, and recording them in table, which may be nil. It returns
+// ErrMigrationOverlap if legacy IDs issued before until could reach the new
+// ID space.
+func NewMigrator(legacy, next *Snowflake, table TranslationTable, until time.Time) (*Migrator, error) {
+	boundary := next.FirstIDForTime(next.clock.Now())
+	if legacy.LastIDForTime(until) >= boundary {
+		return nil, ErrMigrationOverlap
+	}
+
+	return &Migrator{legacy: legacy, next: next, table: table, until: until, boundary: boundary}, nil
+}
+
+// NextPair issues a legacy and a new ID for the same record, and records
+// them in the translation table. It returns ErrMigrationOverlap once the
+// cutover window has ended.
+func (m *Migrator) NextPair(ctx context.Context) (oldID, newID uint64, err error) {
+	if m.legacy.clock.Now().After(m.until) {
+		return 0, 0, ErrMigrationOverlap
+	}
+	if oldID, err = m.legacy.NextID(); err != nil {
+		return 0, 0, err
+	}
+	if newID, err = m.next.NextID(); err != nil {
+		return 0, 0, err
+	}
+
+	if m.table != nil {
+		if err := m.table.StoreTranslation(ctx, oldID, newID); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return oldID, newID, nil
+}
+
+// IsLegacy reports if id was issued by the legacy generator.
+func (m *Migrator) IsLegacy(id uint64) bool {
+	return id < m.boundary
+}
+
+// Generator returns the generator that issued id.
+func (m *Migrator) Generator(id uint64) *Snowflake {
+	if m.IsLegacy(id) {
+		return m.legacy
+	}
+
+	return m.next
+}
+
+// Translate returns the new ID issued alongside the legacy ID id, or id
+// itself if it is not a legacy ID.
+func (m *Migrator) Translate(ctx context.Context, id uint64) (uint64, error) {
+	if !m.IsLegacy(id) {
+		return id, nil
+	}
+	if m.table == nil {
+		return 0, ErrNoTranslation
+	}
+
+	return m.table.NewID(ctx, id)
+}
+
+// MemoryTranslationTable is a TranslationTable for a single process.
+type MemoryTranslationTable struct {
+	mu       sync.Mutex
+	oldToNew map[uint64]uint64
+	newToOld map[uint64]uint64
+}
+
+func (t *MemoryTranslationTable) StoreTranslation(_ context.Context, oldID, newID uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.oldToNew == nil {
+		t.oldToNew = make(map[uint64]uint64)
+		t.newToOld = make(map[uint64]uint64)
+	}
+	t.oldToNew[oldID] = newID
+	t.newToOld[newID] = oldID
+
+	return nil
+}
+
+func (t *MemoryTranslationTable) NewID(_ context.Context, oldID uint64) (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id, ok := t.oldToNew[oldID]
+	if !ok {
+		return 0, ErrNoTranslation
+	}
+
+	return id, nil
+}
+
+func (t *MemoryTranslationTable) OldID(_ context.Context, newID uint64) (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id, ok := t.newToOld[newID]
+	if !ok {
+		return 0, ErrNoTranslation
+	}
+
+	return id, nil
+}
@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	clock := clocktest.NewFakeClock(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	until := clock.Now().Add(30 * 24 * time.Hour)
+
+	// A 41/5/5/13 scheme with the Twitter epoch.
+	legacyEpoch := time.Date(2010, 11, 4, 1, 42, 54, 657e6, time.UTC)
+	dcWorker := MachineFields{{Name: "datacenter", Bits: 5}, {Name: "worker", Bits: 5}}
+	mid, _ := dcWorker.Compose(3, 7)
+	legacy := NewSnowflake(legacyEpoch, int(mid), WithClock(clock),
+		WithLayout(Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 13}), WithMachineFields(dcWorker))
+
+	// The default layout with the same epoch would start below the legacy
+	// IDs.
+	sameEpoch := NewSnowflake(legacyEpoch, 1, WithClock(clock))
+	if _, err := NewMigrator(legacy, sameEpoch, nil, until); !errors.Is(err, ErrMigrationOverlap) {
+		t.Fatalf("expected ErrMigrationOverlap, got %v", err)
+	}
+
+	next := NewSnowflake(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), 1, WithClock(clock))
+	table := new(MemoryTranslationTable)
+	m, err := NewMigrator(legacy, next, table, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pairs [][2]uint64
+	for i := 0; i < 100; i++ {
+		o, n, err := m.NextPair(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pairs = append(pairs, [2]uint64{o, n})
+		clock.Advance(7 * time.Hour)
+	}
+
+	for _, p := range pairs {
+		o, n := p[0], p[1]
+		if !m.IsLegacy(o) || m.IsLegacy(n) {
+			t.Errorf("misclassified pair %d, %d", o, n)
+		}
+		if m.Generator(o) != legacy || m.Generator(n) != next {
+			t.Errorf("wrong generators for pair %d, %d", o, n)
+		}
+		if got, err := m.Translate(ctx, o); err != nil || got != n {
+			t.Errorf("Translate(%d) = %d, %v, want %d", o, got, err, n)
+		}
+		if got, err := m.Translate(ctx, n); err != nil || got != n {
+			t.Errorf("Translate(%d) = %d, %v, want it unchanged", n, got, err)
+		}
+		if got, err := table.OldID(ctx, n); err != nil || got != o {
+			t.Errorf("OldID(%d) = %d, %v, want %d", n, got, err, o)
+		}
+		if !m.Generator(o).IDToTime(o).Equal(m.Generator(n).IDToTime(n)) {
+			t.Errorf("pair %d, %d decodes to different times", o, n)
+		}
+	}
+	if p := legacy.Decompose(pairs[0][0]); p.Machine["datacenter"] != 3 || p.Machine["worker"] != 7 {
+		t.Errorf("unexpected legacy machine fields %v", p.Machine)
+	}
+
+	if _, err := m.Translate(ctx, pairs[0][0]-1); !errors.Is(err, ErrNoTranslation) {
+		t.Errorf("expected ErrNoTranslation, got %v", err)
+	}
+
+	clock.Advance(until.Sub(clock.Now()) + time.Millisecond)
+	if _, _, err := m.NextPair(ctx); !errors.Is(err, ErrMigrationOverlap) {
+		t.Errorf("expected ErrMigrationOverlap after the window, got %v", err)
+	}
+}
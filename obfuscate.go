@@ -0,0 +1,56 @@
+package snowflake
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const obfuscatorRounds = 4
+
+// Obfuscator maps IDs to and from opaque 64-bit values with a keyed
+// permutation, a 4-round Feistel network whose round function is AES. The
+// result hides the creation time and per-tick volume embedded in public
+// IDs, but is not ordered and must be deobfuscated before decomposing.
+type Obfuscator struct {
+	block cipher.Block
+}
+
+// NewObfuscator derives the permutation from key. The same key must be used
+// to deobfuscate.
+func NewObfuscator(key []byte) *Obfuscator {
+	k := sha256.Sum256(key)
+	block, _ := aes.NewCipher(k[:]) // cannot fail for a 32-byte key
+
+	return &Obfuscator{block: block}
+}
+
+func (o *Obfuscator) round(i int, half uint32) uint32 {
+	var buf [aes.BlockSize]byte
+	buf[0] = byte(i)
+	binary.BigEndian.PutUint32(buf[1:], half)
+	o.block.Encrypt(buf[:], buf[:])
+
+	return binary.BigEndian.Uint32(buf[:])
+}
+
+// Obfuscate returns the opaque form of id.
+func (o *Obfuscator) Obfuscate(id uint64) uint64 {
+	l, r := uint32(id>>32), uint32(id)
+	for i := 0; i < obfuscatorRounds; i++ {
+		l, r = r, l^o.round(i, r)
+	}
+
+	return uint64(l)<<32 | uint64(r)
+}
+
+// Deobfuscate reverses Obfuscate.
+func (o *Obfuscator) Deobfuscate(v uint64) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for i := obfuscatorRounds - 1; i >= 0; i-- {
+		l, r = r^o.round(i, l), l
+	}
+
+	return uint64(l)<<32 | uint64(r)
+}
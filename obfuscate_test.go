@@ -0,0 +1,39 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestObfuscator(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sf := NewSnowflake(epoch, 1, WithClock(clocktest.NewFakeClock(epoch.Add(time.Hour))))
+	o := NewObfuscator([]byte("secret"))
+
+	seen := make(map[uint64]bool)
+	var prev uint64
+	for i := 0; i < 1000; i++ {
+		id, _ := sf.NextID()
+		v := o.Obfuscate(id)
+
+		if got := o.Deobfuscate(v); got != id {
+			t.Fatalf("round trip of %d returned %d", id, got)
+		}
+		if seen[v] {
+			t.Fatalf("obfuscated value %d repeated", v)
+		}
+		seen[v] = true
+
+		// Consecutive ids should not share their timestamp prefix any more.
+		if i > 0 && v>>(MachineIDBits+SequenceBits) == prev>>(MachineIDBits+SequenceBits) {
+			t.Errorf("obfuscated ids %d and %d share a prefix", prev, v)
+		}
+		prev = v
+	}
+
+	if other := NewObfuscator([]byte("other")); other.Obfuscate(1) == o.Obfuscate(1) {
+		t.Error("different keys should produce different permutations")
+	}
+}
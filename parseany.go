@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Encoding names a textual form of an ID.
+type Encoding int
+
+const (
+	EncodingDecimal Encoding = iota
+	EncodingHex
+	EncodingBase62
+	EncodingBase32
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingDecimal:
+		return "decimal"
+	case EncodingHex:
+		return "hex"
+	case EncodingBase62:
+		return "base62"
+	case EncodingBase32:
+		return "base32"
+	}
+
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// ParseAny parses an ID pasted from a log, URL or database dump, detecting
+// its encoding. Surrounding white space and quotes are ignored. Since many
+// strings are valid in several encodings, the first match wins:
+//
+//   - decimal, if s has only decimal digits and fits in 64 bits
+//   - hex, if s has a 0x prefix or is 16 hex digits as returned by ID.Hex
+//   - base32, if s is 13 Crockford digits as returned by ID.Base32
+//   - base62 otherwise
+//
+// Short hex or base32 strings without these markers are read as base62;
+// use the specific parser when the encoding is known.
+func ParseAny(s string) (ID, Encoding, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+	}
+
+	if isDecimal(s) {
+		if id, err := parseDecimal(s); err == nil {
+			return id, EncodingDecimal, nil
+		}
+	}
+
+	if h, ok := strings.CutPrefix(strings.ToLower(s), "0x"); ok {
+		id, err := ParseHex(h)
+		return id, EncodingHex, err
+	}
+	if len(s) == 16 {
+		if id, err := ParseHex(s); err == nil {
+			return id, EncodingHex, nil
+		}
+	}
+	if len(s) == 13 {
+		if id, err := ParseBase32(s); err == nil {
+			return id, EncodingBase32, nil
+		}
+	}
+
+	id, err := ParseBase62(s)
+	if err != nil {
+		return 0, EncodingBase62, fmt.Errorf("%w: %q matches no known encoding", ErrInvalidEncoding, s)
+	}
+
+	return id, EncodingBase62, nil
+}
+
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseDecimal(s string) (ID, error) {
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := uint64(s[i] - '0')
+		if n > (1<<64-1-d)/10 {
+			return 0, fmt.Errorf("%w: decimal value out of range", ErrInvalidEncoding)
+		}
+		n = n*10 + d
+	}
+
+	return ID(n), nil
+}
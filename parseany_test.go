@@ -0,0 +1,50 @@
+package snowflake
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestParseAny(t *testing.T) {
+	id := ID(0x1f2e3d4c5b6a7988)
+	dec := strconv.FormatUint(uint64(id), 10)
+
+	tests := []struct {
+		in  string
+		enc Encoding
+	}{
+		{dec, EncodingDecimal},
+		{" \"" + dec + "\"\n", EncodingDecimal},
+		{id.StringPadded(), EncodingDecimal},
+		{id.Hex(), EncodingHex},
+		{"0x1f2e3d4c5b6a7988", EncodingHex},
+		{"0X1F2E3D4C5B6A7988", EncodingHex},
+		{id.Base32(), EncodingBase32},
+		{"'" + id.Base32() + "'", EncodingBase32},
+		{id.Base62(), EncodingBase62},
+	}
+	for _, tt := range tests {
+		got, enc, err := ParseAny(tt.in)
+		if err != nil || enc != tt.enc || got != id {
+			t.Errorf("ParseAny(%q) = %d, %v, %v, want %d, %v", tt.in, got, enc, err, id, tt.enc)
+		}
+	}
+
+	// All-digit strings are decimal even when they could be hex.
+	if got, enc, err := ParseAny("4000000000003039"); err != nil || got != 4000000000003039 || enc != EncodingDecimal {
+		t.Errorf("ParseAny(4000000000003039) = %d, %v, %v", got, enc, err)
+	}
+
+	for _, s := range []string{"", "\"\"", "0x", "0xg", "abc-def", "99999999999999999999999"} {
+		if _, _, err := ParseAny(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("ParseAny(%q) should fail, got %v", s, err)
+		}
+	}
+}
+
+func TestEncodingString(t *testing.T) {
+	if EncodingBase32.String() != "base32" || Encoding(9).String() != "Encoding(9)" {
+		t.Errorf("unexpected names %q, %q", EncodingBase32, Encoding(9))
+	}
+}
@@ -0,0 +1,119 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// Parser decodes the IDs of one LayoutDescriptor, so IDs with a custom
+// layout, epoch or time unit are read the same way everywhere. The zero
+// Parser reads the IDs the package defaults describe, like the ID methods.
+type Parser struct {
+	desc LayoutDescriptor
+}
+
+// NewParser returns a Parser for d. A zero epoch, layout or time unit
+// selects the package default, as for NewSnowflake. It returns the error
+// of d.Validate otherwise.
+func NewParser(d LayoutDescriptor) (Parser, error) {
+	if d.Epoch.IsZero() {
+		d.Epoch = epochStart
+	}
+	d.Layout = d.Layout.orDefault()
+	if d.TimeUnit == 0 {
+		d.TimeUnit = snowflakeTimeUnit
+	}
+	if err := d.Validate(); err != nil {
+		return Parser{}, err
+	}
+
+	return Parser{desc: d}, nil
+}
+
+// Parser returns a Parser for the generator's IDs. Unlike Decompose, its
+// Parts leave out the generator's machine fields and allowlist.
+func (sf *Snowflake) Parser() Parser {
+	return Parser{desc: sf.Descriptor()}
+}
+
+// Descriptor returns the scheme p decodes, with defaults filled in.
+func (p Parser) Descriptor() LayoutDescriptor {
+	if p.desc.TimeUnit == 0 {
+		p, _ = NewParser(p.desc)
+	}
+
+	return p.desc
+}
+
+// Parse splits id into its fields.
+func (p Parser) Parse(id uint64) Parts {
+	d := p.Descriptor()
+	ts, mid, seq := d.Layout.Decompose(id)
+
+	return Parts{
+		Time:      addTicks(d.Epoch, d.TimeUnit, ts),
+		Timestamp: ts,
+		MachineID: mid,
+		Sequence:  seq,
+		Tag:       d.Layout.Tag(id),
+	}
+}
+
+// ParseString parses s in any encoding ParseAny accepts and splits it into
+// its fields.
+func (p Parser) ParseString(s string) (Parts, error) {
+	id, err := p.ParseID(s)
+	if err != nil {
+		return Parts{}, err
+	}
+
+	return p.Parse(uint64(id)), nil
+}
+
+// ParseID parses s like ParseAny and checks the result with Check.
+func (p Parser) ParseID(s string) (ID, error) {
+	id, _, err := ParseAny(s)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.Check(uint64(id)); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Check reports ErrInvalidID if id is zero or sets bits above the fields
+// of the layout.
+func (p Parser) Check(id uint64) error {
+	l := p.Descriptor().Layout
+	bits := l.TimeBits + l.timeShift()
+	switch {
+	case id == 0:
+		return fmt.Errorf("%w: zero", ErrInvalidID)
+	case bits < TotalBits && id>>bits != 0:
+		return fmt.Errorf("%w: %d sets bits above the %d of layout %s", ErrInvalidID, id, bits, l)
+	}
+
+	return nil
+}
+
+// Time returns the time embedded in id.
+func (p Parser) Time(id uint64) time.Time {
+	d := p.Descriptor()
+	ts, _, _ := d.Layout.Decompose(id)
+
+	return addTicks(d.Epoch, d.TimeUnit, ts)
+}
+
+// addTicks returns the time ticks units of unit after epoch. Like fromUnit
+// it works on seconds so that coarse units do not overflow.
+func addTicks(epoch time.Time, unit time.Duration, ticks uint64) time.Time {
+	if unit > time.Second {
+		return time.Unix(epoch.Unix()+int64(ticks)*int64(unit/time.Second), int64(epoch.Nanosecond()))
+	}
+
+	perSecond := uint64(time.Second / unit)
+
+	return time.Unix(epoch.Unix()+int64(ticks/perSecond), int64(epoch.Nanosecond())+int64(ticks%perSecond)*int64(unit))
+}
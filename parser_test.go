@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParser(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := Layout{TimeBits: 39, TagBits: 2, MachineBits: 10, SequenceBits: 12}
+	p, err := NewParser(LayoutDescriptor{Epoch: epoch, Layout: l, TimeUnit: TimeUnit10Milliseconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := l.ComposeTagged(150, 2, 9, 33)
+	got := p.Parse(id)
+	if !got.Time.Equal(epoch.Add(1500*time.Millisecond)) || got.Timestamp != 150 || got.Tag != 2 || got.MachineID != 9 || got.Sequence != 33 {
+		t.Fatalf("Parse = %+v", got)
+	}
+	if s, err := p.ParseString(ID(id).Base62()); err != nil || s.Timestamp != got.Timestamp || s.Sequence != 33 {
+		t.Fatalf("ParseString = %+v, %v", s, err)
+	}
+	if err := p.Check(1 << 63); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Check of a bit above the layout = %v", err)
+	}
+	if _, err := p.ParseID("0"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("ParseID(0) = %v", err)
+	}
+
+	sf := NewSnowflake(epoch, 5, WithLayout(l), WithTimeUnit(TimeUnit10Milliseconds))
+	if d := sf.Decompose(id); !d.Time.Equal(got.Time) || d.Timestamp != 150 {
+		t.Errorf("Decompose = %+v, Parse = %+v", d, got)
+	}
+
+	var zero Parser
+	def := ID(DefaultLayout.Compose(1000, 3, 4))
+	if pt := zero.Parse(uint64(def)); !pt.Time.Equal(def.Time()) || pt.MachineID != 3 || pt.Sequence != 4 {
+		t.Errorf("zero Parser = %+v", pt)
+	}
+
+	if _, err := NewParser(LayoutDescriptor{Layout: Layout{TimeBits: 60, SequenceBits: 12}}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("NewParser of an invalid layout = %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// PartitionBy selects the part of an ID that picks its partition.
+type PartitionBy int
+
+const (
+	// PartitionByMachine keeps the IDs of one generator in one partition,
+	// preserving their order.
+	PartitionByMachine PartitionBy = iota
+	// PartitionByBucket keeps the IDs of one time bucket in one partition.
+	PartitionByBucket
+	// PartitionByID spreads IDs evenly over all partitions.
+	PartitionByID
+)
+
+// Partitioner maps IDs to message broker partitions, e.g. Kafka partitions
+// or NATS subject tokens, the same way in every process and language.
+// Partition hashes the 8-byte big-endian key returned by PartitionKey with
+// Kafka's murmur2, so producers can either set the partition explicitly or
+// send PartitionKey as the message key and leave the choice to Kafka's
+// default partitioner.
+type Partitioner struct {
+	gen        *Snowflake
+	partitions int
+	by         PartitionBy
+	bucket     time.Duration
+}
+
+// NewPartitioner returns a Partitioner over partitions partitions for IDs
+// issued by gen. bucket is the bucket width for PartitionByBucket. It
+// returns nil if partitions is not positive, or bucket is not positive for
+// PartitionByBucket.
+func NewPartitioner(gen *Snowflake, partitions int, by PartitionBy, bucket time.Duration) *Partitioner {
+	if partitions <= 0 || by == PartitionByBucket && bucket <= 0 {
+		return nil
+	}
+
+	return &Partitioner{gen: gen, partitions: partitions, by: by, bucket: bucket}
+}
+
+// Key returns id as a fixed-width big-endian message key.
+func (p *Partitioner) Key(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+// PartitionKey returns the 8-byte big-endian key of the part of id that
+// picks its partition: its machine ID, its bucket number, or id itself.
+func (p *Partitioner) PartitionKey(id uint64) []byte {
+	switch p.by {
+	case PartitionByMachine:
+		_, mid, _ := p.gen.layout.Decompose(id)
+		id = mid
+	case PartitionByBucket:
+		id = uint64(p.gen.Bucket(id, p.bucket))
+	}
+
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+// Partition returns the partition of id, as Kafka's default partitioner
+// would choose it for PartitionKey(id).
+func (p *Partitioner) Partition(id uint64) int {
+	return KafkaPartition(p.PartitionKey(id), p.partitions)
+}
+
+// KafkaPartition returns the partition Kafka's default partitioner picks
+// for a message key among partitions partitions.
+func KafkaPartition(key []byte, partitions int) int {
+	return int(Murmur2(key)&0x7fffffff) % partitions
+}
+
+// Murmur2 returns the 32-bit murmur2 hash of data as computed by Kafka's
+// clients, with seed 0x9747b28c.
+func Murmur2(data []byte) uint32 {
+	const (
+		m = 0x5bd1e995
+		r = 24
+	)
+	n := len(data)
+	h := uint32(0x9747b28c) ^ uint32(n)
+
+	for i := 0; i+4 <= n; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[n&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+
+	return h
+}
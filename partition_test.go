@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestMurmur2(t *testing.T) {
+	// Vectors from Kafka's UtilsTest.
+	tests := []struct {
+		in   string
+		want int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	}
+	for _, tt := range tests {
+		if got := int32(Murmur2([]byte(tt.in))); got != tt.want {
+			t.Errorf("Murmur2(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPartitioner(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	a := NewSnowflake(epoch, 1, WithClock(clock))
+	b := NewSnowflake(epoch, 2, WithClock(clock))
+
+	byMachine := NewPartitioner(a, 12, PartitionByMachine, 0)
+	byBucket := NewPartitioner(a, 12, PartitionByBucket, time.Minute)
+	first, _ := a.NextID()
+	other, _ := b.NextID()
+	clock.Advance(time.Hour)
+	later, _ := a.NextID()
+
+	if key := ID(first).Bytes(); !bytes.Equal(byMachine.Key(first), key[:]) {
+		t.Errorf("unexpected key %x", byMachine.Key(first))
+	}
+	if byMachine.Partition(first) != byMachine.Partition(later) {
+		t.Error("IDs of one machine in different partitions")
+	}
+	if got := byMachine.Partition(other); got != KafkaPartition([]byte{0, 0, 0, 0, 0, 0, 0, 2}, 12) {
+		t.Errorf("unexpected partition %d for machine 2", got)
+	}
+	if byBucket.Partition(first) != byBucket.Partition(other) {
+		t.Error("IDs of one bucket in different partitions")
+	}
+
+	seen := make(map[int]bool)
+	byID := NewPartitioner(a, 4, PartitionByID, 0)
+	for range 100 {
+		id, _ := a.NextID()
+		seen[byID.Partition(id)] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("IDs spread over %d of 4 partitions", len(seen))
+	}
+
+	if NewPartitioner(a, 0, PartitionByID, 0) != nil || NewPartitioner(a, 4, PartitionByBucket, 0) != nil {
+		t.Error("expected nil for invalid partitioners")
+	}
+}
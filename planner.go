@@ -0,0 +1,206 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"sort"
+	"time"
+)
+
+var (
+	ErrInvalidPlanner = errors.New("invalid planner input")
+	ErrNoLayout       = errors.New("no layout satisfies the requirements")
+)
+
+// Planner recommends a layout and time unit from expected load, before a
+// system has production data. Request rates are given as percentiles of
+// the per-machine rate over time, e.g. {0.5: 2000, 0.99: 40000, 1: 100000}
+// IDs per second, and arrivals within a tick are modeled as Poisson.
+type Planner struct {
+	// RatePercentiles maps quantiles in (0, 1] to IDs per second issued by
+	// one machine. Rates above the highest quantile are assumed equal to it.
+	RatePercentiles map[float64]float64
+	// Machines is the number of generators that must run at once, including
+	// room for growth.
+	Machines int
+	// Lifetime is how long the IDs must last after the epoch.
+	Lifetime time.Duration
+	// TotalBits is the width of the IDs, 64 by default; 63 keeps them
+	// positive as int64.
+	TotalBits uint
+	// Units are the candidate time units, by default 1ms, 10ms, 100ms and
+	// 1s.
+	Units []time.Duration
+}
+
+// Plan is a candidate layout and time unit.
+type Plan struct {
+	Layout   Layout
+	TimeUnit time.Duration
+	// Lifetime is how long the time field lasts, at least the requested
+	// lifetime.
+	Lifetime time.Duration
+	// Exhaustion is the probability that a tick runs out of sequence
+	// numbers, making NextID wait for the next one.
+	Exhaustion float64
+}
+
+// Plans returns the layouts meeting the machine count and lifetime, one
+// per time unit, least likely to exhaust a tick first. Ties go to the
+// finer unit. Sequence bits are capped at 16; leftover bits go to the time
+// field.
+func (p Planner) Plans() ([]Plan, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	total := p.TotalBits
+	if total == 0 {
+		total = TotalBits
+	}
+	units := p.Units
+	if units == nil {
+		units = []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+	}
+	machineBits := uint(bits.Len(uint(p.Machines - 1)))
+
+	var plans []Plan
+	for _, unit := range units {
+		if !validUnit(unit) {
+			return nil, fmt.Errorf("%w: unsupported time unit %s", ErrInvalidPlanner, unit)
+		}
+
+		ticks := uint64(p.Lifetime / unit)
+		if p.Lifetime%unit != 0 {
+			ticks++
+		}
+		timeBits := uint(bits.Len64(ticks - 1))
+		if timeBits+machineBits >= total {
+			continue
+		}
+		seqBits := min(total-timeBits-machineBits, 16)
+		l := Layout{TimeBits: total - machineBits - seqBits, MachineBits: machineBits, SequenceBits: seqBits}
+
+		plans = append(plans, Plan{
+			Layout:     l,
+			TimeUnit:   unit,
+			Lifetime:   l.Lifetime(unit),
+			Exhaustion: p.exhaustion(l, unit),
+		})
+	}
+	if plans == nil {
+		return nil, fmt.Errorf("%w: %d machines for %s in %d bits", ErrNoLayout, p.Machines, p.Lifetime, total)
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].Exhaustion != plans[j].Exhaustion {
+			return plans[i].Exhaustion < plans[j].Exhaustion
+		}
+		return plans[i].TimeUnit < plans[j].TimeUnit
+	})
+
+	return plans, nil
+}
+
+// Recommend returns the first of Plans.
+func (p Planner) Recommend() (Plan, error) {
+	plans, err := p.Plans()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return plans[0], nil
+}
+
+// Exhaustion returns the probability that a tick of l with the given unit
+// runs out of sequence numbers under the planner's rates, e.g. to check an
+// existing layout.
+func (p Planner) Exhaustion(l Layout, unit time.Duration) (float64, error) {
+	if err := p.validate(); err != nil {
+		return 0, err
+	}
+
+	return p.exhaustion(l, unit), nil
+}
+
+func (p Planner) validate() error {
+	if len(p.RatePercentiles) == 0 {
+		return fmt.Errorf("%w: no rate percentiles", ErrInvalidPlanner)
+	}
+	for q, rate := range p.RatePercentiles {
+		if q <= 0 || q > 1 || rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+			return fmt.Errorf("%w: rate %v at quantile %v", ErrInvalidPlanner, rate, q)
+		}
+	}
+	if p.Machines <= 0 || p.Lifetime <= 0 {
+		return fmt.Errorf("%w: machines and lifetime must be positive", ErrInvalidPlanner)
+	}
+
+	return nil
+}
+
+// exhaustion integrates the Poisson tail over the rate percentiles,
+// interpolating linearly between them. Below the lowest and above the
+// highest quantile the rate is taken to be constant.
+func (p Planner) exhaustion(l Layout, unit time.Duration) float64 {
+	quantiles := make([]float64, 0, len(p.RatePercentiles))
+	for q := range p.RatePercentiles {
+		quantiles = append(quantiles, q)
+	}
+	slices.Sort(quantiles)
+
+	capacity := l.MaxSequence() + 1
+	tail := func(rate float64) float64 {
+		return poissonTail(rate*unit.Seconds(), capacity)
+	}
+
+	first, last := quantiles[0], quantiles[len(quantiles)-1]
+	prob := first*tail(p.RatePercentiles[first]) + (1-last)*tail(p.RatePercentiles[last])
+	for i := 1; i < len(quantiles); i++ {
+		q0, q1 := quantiles[i-1], quantiles[i]
+		r0, r1 := p.RatePercentiles[q0], p.RatePercentiles[q1]
+		for s := 0; s < plannerSteps; s++ {
+			f := (float64(s) + 0.5) / plannerSteps
+			prob += (q1 - q0) / plannerSteps * tail(r0+f*(r1-r0))
+		}
+	}
+
+	return prob
+}
+
+// plannerSteps is the number of points evaluated between two percentiles.
+const plannerSteps = 16
+
+// poissonTail returns P(X > k) for X ~ Poisson(lambda).
+func poissonTail(lambda float64, k uint64) float64 {
+	if lambda == 0 {
+		return 0
+	}
+
+	term := func(j uint64) float64 {
+		lg, _ := math.Lgamma(float64(j) + 1)
+		return math.Exp(float64(j)*math.Log(lambda) - lambda - lg)
+	}
+
+	if lambda > float64(k) {
+		// The tail is large; 1 minus the head is accurate enough.
+		var head float64
+		for j := uint64(0); j <= k; j++ {
+			head += term(j)
+		}
+		return max(1-head, 0)
+	}
+
+	// Terms decrease past k, so sum them until they no longer matter.
+	var tail float64
+	for j := k + 1; ; j++ {
+		t := term(j)
+		tail += t
+		if t == 0 || t < tail*1e-16 {
+			return tail
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPlanner(t *testing.T) {
+	p := Planner{
+		RatePercentiles: map[float64]float64{0.5: 100_000, 0.99: 2_000_000, 1: 8_000_000},
+		Machines:        1000,
+		Lifetime:        50 * 365 * 24 * time.Hour,
+	}
+
+	plans, err := p.Plans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 4 {
+		t.Fatalf("expected a plan per default unit, got %+v", plans)
+	}
+	for _, pl := range plans {
+		if err := pl.Layout.Validate(); err != nil {
+			t.Errorf("%+v: %v", pl, err)
+		}
+		if pl.Layout.MachineBits != 10 || pl.Layout.TimeBits+pl.Layout.MachineBits+pl.Layout.SequenceBits != 64 {
+			t.Errorf("unexpected layout %+v", pl.Layout)
+		}
+		if pl.Lifetime < p.Lifetime {
+			t.Errorf("%+v does not last %s", pl, p.Lifetime)
+		}
+	}
+
+	// 50 years of ms leave 13 sequence bits, enough for 8000 IDs per ms;
+	// coarser units pile more IDs into a tick of at most 16 bits.
+	if ms := plans[0]; ms.TimeUnit != time.Millisecond || ms.Layout != (Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 13}) || ms.Exhaustion > 1e-6 {
+		t.Errorf("expected 1ms with 13 sequence bits first, got %+v", ms)
+	}
+	if s := plans[3]; s.TimeUnit != time.Second || s.Exhaustion < 0.5 {
+		t.Errorf("expected 1s last, got %+v", s)
+	}
+	if rec, _ := p.Recommend(); rec != plans[0] {
+		t.Errorf("recommended %+v over %+v", rec, plans[0])
+	}
+
+	// DefaultLayout only has 4096 per ms, exceeded in the top half of the
+	// last percent.
+	if got, _ := p.Exhaustion(DefaultLayout, time.Millisecond); got < 0.001 || got > 0.01 {
+		t.Errorf("expected DefaultLayout to exhaust in 0.1%% to 1%% of ticks, got %v", got)
+	}
+
+	quiet := Planner{RatePercentiles: map[float64]float64{0.99: 100}, Machines: 1000, Lifetime: p.Lifetime}
+	if rec, err := quiet.Recommend(); err != nil || rec.TimeUnit != time.Millisecond || rec.Exhaustion > 1e-12 {
+		t.Errorf("expected 1ms to suffice for a quiet system, got %+v, %v", rec, err)
+	}
+
+	if _, err := (Planner{RatePercentiles: map[float64]float64{1.5: 1}, Machines: 1, Lifetime: time.Hour}).Plans(); !errors.Is(err, ErrInvalidPlanner) {
+		t.Errorf("expected ErrInvalidPlanner, got %v", err)
+	}
+	huge := Planner{RatePercentiles: map[float64]float64{1: 1}, Machines: 1 << 40, Lifetime: 100 * 365 * 24 * time.Hour, Units: []time.Duration{time.Millisecond}}
+	if _, err := huge.Plans(); !errors.Is(err, ErrNoLayout) {
+		t.Errorf("expected ErrNoLayout, got %v", err)
+	}
+}
+
+func TestPoissonTail(t *testing.T) {
+	for _, tt := range []struct {
+		lambda float64
+		k      uint64
+		want   float64
+	}{
+		{0, 10, 0},
+		{1, 0, 1 - math.Exp(-1)},
+		{1, 1, 1 - 2*math.Exp(-1)},
+		{4096, 4096, 0.4958},
+		{100, 4095, 0},
+	} {
+		if got := poissonTail(tt.lambda, tt.k); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("poissonTail(%v, %d) = %v, want %v", tt.lambda, tt.k, got, tt.want)
+		}
+	}
+}
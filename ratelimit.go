@@ -0,0 +1,80 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrRateLimited = errors.New("id rate limit exceeded")
+
+// RateLimitPolicy decides what NextID does when the rate limit is reached.
+type RateLimitPolicy int
+
+const (
+	// RateLimitWait makes NextID sleep until a token is available. Shutdown
+	// and the NextIDContext context interrupt the wait.
+	RateLimitWait RateLimitPolicy = iota
+	// RateLimitReject makes NextID return ErrRateLimited.
+	RateLimitReject
+)
+
+// tokenBucket is guarded by the generator lock.
+type tokenBucket struct {
+	perNano float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	policy  RateLimitPolicy
+}
+
+// WithMaxIDsPerSecond caps issuance at n IDs per second, allowing bursts of
+// up to burst IDs, with a token bucket kept under the generator lock.
+// ReserveBlock takes one token per reserved ID and fails with
+// ErrRateLimited for blocks larger than burst. NewSnowflake returns nil if
+// n is not positive; burst defaults to 1.
+func WithMaxIDsPerSecond(n, burst int, policy RateLimitPolicy) Option {
+	return func(sf *Snowflake) {
+		if burst < 1 {
+			burst = 1
+		}
+		sf.limiter = &tokenBucket{
+			perNano: float64(n) / float64(time.Second),
+			burst:   float64(burst),
+			tokens:  float64(burst),
+			policy:  policy,
+		}
+	}
+}
+
+// takeTokens removes n tokens from the bucket, waiting for them if the
+// policy allows until Shutdown or ctx interrupts the wait. It must be
+// called with sf.mutex held.
+func (sf *Snowflake) takeTokens(ctx context.Context, req *idRequest, n int) error {
+	b := sf.limiter
+	if float64(n) > b.burst {
+		return ErrRateLimited
+	}
+
+	for {
+		now := sf.clock.Now()
+		if !b.last.IsZero() && now.After(b.last) {
+			b.tokens = min(b.burst, b.tokens+float64(now.Sub(b.last))*b.perNano)
+		}
+		if now.After(b.last) {
+			b.last = now
+		}
+
+		if b.tokens >= float64(n) {
+			b.tokens -= float64(n)
+			return nil
+		}
+		if b.policy == RateLimitReject || req.noWait {
+			return ErrRateLimited
+		}
+
+		if err := sf.wait(ctx, time.Duration((float64(n)-b.tokens)/b.perNano)+1); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestMaxIDsPerSecondReject(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithMaxIDsPerSecond(1000, 5, RateLimitReject))
+
+	for i := 0; i < 5; i++ {
+		if _, err := sf.NextID(); err != nil {
+			t.Fatalf("id %d within the burst: %v", i, err)
+		}
+	}
+	if _, err := sf.NextID(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited after the burst, got %v", err)
+	}
+
+	clock.Advance(2 * time.Millisecond)
+	if _, err := sf.ReserveBlock(2); err != nil {
+		t.Errorf("expected two tokens after 2ms: %v", err)
+	}
+	if _, err := sf.ReserveBlock(6); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected a block larger than the burst to fail, got %v", err)
+	}
+}
+
+func TestMaxIDsPerSecondWait(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := clocktest.NewFakeClock(start)
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithMaxIDsPerSecond(100, 1, RateLimitWait))
+
+	for i := 0; i < 11; i++ {
+		if _, err := sf.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first ID uses the burst, the other ten wait 10ms each.
+	if elapsed := clock.Now().Sub(start); elapsed < 100*time.Millisecond || elapsed > 101*time.Millisecond {
+		t.Errorf("expected about 100ms of waiting, got %s", elapsed)
+	}
+
+	if NewSnowflake(epoch, 1, WithMaxIDsPerSecond(0, 1, RateLimitWait)) != nil {
+		t.Error("expected a zero rate to be rejected")
+	}
+}
+
+func TestMaxIDsPerSecondWaitInterrupted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &scriptedClock{readings: []time.Time{epoch.Add(time.Hour)}}
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithMaxIDsPerSecond(1, 1, RateLimitWait))
+
+	if _, err := sf.NextID(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The frozen clock never refills the bucket.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := sf.NextIDContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("NextIDContext = %v", err)
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := sf.NextID()
+		errc <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	sf.Close()
+	if err := <-errc; !errors.Is(err, ErrClosed) {
+		t.Fatalf("NextID during Close = %v", err)
+	}
+}
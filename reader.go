@@ -0,0 +1,72 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// IDEncoding selects how an IDReader writes IDs.
+type IDEncoding int
+
+const (
+	// BinaryIDs writes every ID as 8 big-endian bytes, as ID.Bytes.
+	BinaryIDs IDEncoding = iota
+	// TextIDs writes every ID in decimal followed by a newline.
+	TextIDs
+)
+
+// IDReader is an io.Reader producing an endless stream of newly generated
+// IDs, e.g. to pipe bulk IDs into files, fixtures or load test tools:
+//
+//	io.CopyN(f, snowflake.NewIDReader(gen, snowflake.BinaryIDs), 8*1_000_000)
+//
+// It reserves IDs with ReserveBlock, as many per call as fit into the
+// buffer, so it is not slowed down by per-ID locking. An ID cut off at the
+// end of a buffer continues in the next Read. Generation errors are
+// returned by Read and may be retried.
+type IDReader struct {
+	gen      *Snowflake
+	encoding IDEncoding
+	pending  []byte // encoded IDs not yet read
+	buf      []byte
+}
+
+// NewIDReader returns an IDReader issuing IDs from gen.
+func NewIDReader(gen *Snowflake, encoding IDEncoding) *IDReader {
+	return &IDReader{gen: gen, encoding: encoding}
+}
+
+func (r *IDReader) Read(p []byte) (int, error) {
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	for n < len(p) {
+		size := 8
+		if r.encoding == TextIDs {
+			size = 20 // digits of a typical ID and its newline
+		}
+		count := min((len(p)-n+size-1)/size, int(r.gen.layout.MaxSequence())+1)
+		b, err := r.gen.ReserveBlock(count)
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		r.buf = r.buf[:0]
+		for i := range b.Len() {
+			if r.encoding == TextIDs {
+				r.buf = strconv.AppendUint(r.buf, b.ID(i), 10)
+				r.buf = append(r.buf, '\n')
+			} else {
+				r.buf = binary.BigEndian.AppendUint64(r.buf, b.ID(i))
+			}
+		}
+		c := copy(p[n:], r.buf)
+		n += c
+		r.pending = r.buf[c:]
+	}
+
+	return n, nil
+}
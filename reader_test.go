@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestIDReader(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 3, WithClock(clock))
+
+	// Read byte by byte, then in large chunks spanning several ticks.
+	buf := make([]byte, 8*10000)
+	r := NewIDReader(sf, BinaryIDs)
+	if _, err := io.ReadFull(iotest.OneByteReader(r), buf[:8*10]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(r, buf[8*10:]); err != nil {
+		t.Fatal(err)
+	}
+	var last uint64
+	for i := 0; i < len(buf); i += 8 {
+		id := binary.BigEndian.Uint64(buf[i:])
+		if id <= last || sf.Decompose(id).MachineID != 3 {
+			t.Fatalf("unexpected ID %d after %d", id, last)
+		}
+		last = id
+	}
+
+	text := bufio.NewReader(io.LimitReader(NewIDReader(sf, TextIDs), 20*5000))
+	lines := 0
+	for {
+		// The limit cuts off the last line, which has no newline.
+		line, err := text.ReadString('\n')
+		if err != nil {
+			break
+		}
+		id, err := strconv.ParseUint(line[:len(line)-1], 10, 64)
+		if err != nil || id <= last {
+			t.Fatalf("unexpected ID %q after %d", line, last)
+		}
+		last = id
+		lines++
+	}
+	if lines < 4999 {
+		t.Errorf("read %d lines", lines)
+	}
+
+	sf.Close()
+	if _, err := r.Read(buf); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
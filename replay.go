@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ReplayGenerator is a Snowflake driven by a simulated clock, so that the
+// same arguments produce the same IDs on every run. It is meant for
+// integration tests and golden files.
+type ReplayGenerator struct {
+	*Snowflake
+}
+
+// NewReplay returns a ReplayGenerator whose clock starts at start and moves
+// forward by a pseudo-random step below one tick, derived from seed, on
+// every reading; sleeps advance it without blocking. The IDs only repeat if
+// the generator is used from a single goroutine and called the same way,
+// since Capacity, Stats and NextID all read the clock. A WithClock option
+// in opts is overridden. It returns nil if NewSnowflake would.
+func NewReplay(epoch, start time.Time, machineID int, seed int64, opts ...Option) *ReplayGenerator {
+	c := &replayClock{now: start, rng: rand.New(rand.NewSource(seed))}
+
+	sf := NewSnowflake(epoch, machineID, append(opts[:len(opts):len(opts)], WithClock(c))...)
+	if sf == nil {
+		return nil
+	}
+	c.step = sf.unit
+
+	return &ReplayGenerator{Snowflake: sf}
+}
+
+type replayClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	rng  *rand.Rand
+	step time.Duration
+}
+
+func (c *replayClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.step > 0 {
+		c.now = c.now.Add(time.Duration(c.rng.Int63n(int64(c.step))))
+	}
+
+	return c.now
+}
+
+func (c *replayClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayGenerator(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	run := func(seed int64) []uint64 {
+		r := NewReplay(epoch, start, 3, seed, WithLayout(Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 4}))
+
+		ids := make([]uint64, 200)
+		for i := range ids {
+			ids[i], _ = r.NextID()
+		}
+
+		return ids
+	}
+
+	a, b, other := run(1), run(1), run(2)
+
+	differs := false
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("runs with the same seed differ at %d: %d != %d", i, a[i], b[i])
+		}
+		if i > 0 && a[i] <= a[i-1] {
+			t.Fatalf("id %d is not greater than %d", a[i], a[i-1])
+		}
+		differs = differs || a[i] != other[i]
+	}
+	if !differs {
+		t.Error("expected a different seed to produce different ids")
+	}
+
+	r := NewReplay(epoch, start, 3, 1)
+	id, _ := r.NextID()
+	if got := r.Decompose(id).Time; got.Before(start) || got.After(start.Add(time.Millisecond)) {
+		t.Errorf("first id time %s is not at the replay start", got)
+	}
+}
@@ -0,0 +1,29 @@
+package snowflake
+
+// WithStrictMonotonicRestart guarantees that the first ID after a restart
+// is strictly greater than any ID issued before it, even if the process
+// restarts within the same tick. Like WithStateStore it restores the state
+// Shutdown saved to store; in addition the generator skips both the
+// restored tick and the tick it is created in, so its first ID waits for
+// the next tick if need be, and longer if the clock is behind the restored
+// state.
+//
+// Without a graceful Shutdown, e.g. after a crash, the skipped tick still
+// covers the IDs the previous process issued until it died, as long as the
+// clock does not move backwards across the restart; WithHighWaterMark also
+// covers that case.
+func WithStrictMonotonicRestart(store StateStore) Option {
+	return func(sf *Snowflake) {
+		sf.store = store
+		sf.strictRestart = true
+	}
+}
+
+// skipStartTick makes the next ID use a tick after the restored state and
+// the current time.
+func (sf *Snowflake) skipStartTick() {
+	sf.lastTimestamp = max(sf.lastTimestamp, sf.elapsedTime())
+	sf.sequence = sf.seqMask
+	sf.seqIndex = sf.seqMask
+	sf.current.Store(uint64(sf.lastTimestamp)<<16 | uint64(sf.seqIndex))
+}
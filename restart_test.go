@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestStrictMonotonicRestart(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := clocktest.NewFakeClock(start)
+	store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+
+	// Restart within the same tick, once gracefully and once after a crash.
+	sf := NewSnowflake(epoch, 1, WithClock(clock), WithStrictMonotonicRestart(store))
+	var last uint64
+	for _, graceful := range []bool{true, false} {
+		for range 3 {
+			id, err := sf.NextID()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id <= last {
+				t.Fatalf("ID %d not above %d", id, last)
+			}
+			last = id
+		}
+		if graceful {
+			sf.Close()
+		}
+		sf = NewSnowflake(epoch, 1, WithClock(clock), WithStrictMonotonicRestart(store))
+	}
+	if id, _ := sf.NextID(); id <= last {
+		t.Errorf("ID %d after the restart not above %d", id, last)
+	}
+
+	// A clock set back across a graceful restart waits for the saved state.
+	sf.Close()
+	saved := sf.LastTime()
+	clock.Set(start)
+	sf = NewSnowflake(epoch, 1, WithClock(clock), WithStrictMonotonicRestart(store))
+	id, err := sf.NextID()
+	if err != nil || !sf.IDToTime(id).After(saved) {
+		t.Errorf("first ID at %s, want after %s, %v", sf.IDToTime(id), saved, err)
+	}
+}
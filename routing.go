@@ -0,0 +1,178 @@
+package snowflake
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoRoute      = errors.New("no route for machine ID")
+	ErrInvalidRoute = errors.New("invalid routing table")
+)
+
+// Route sends the machine IDs First to Last, inclusive, to Target, e.g. a
+// datastore name or an endpoint.
+type Route struct {
+	First, Last uint64
+	Target      string
+}
+
+// RoutingTable is one version of the mapping from machine IDs to targets,
+// in effect for IDs created at or after Since. Default, if not empty,
+// catches the machine IDs no route covers.
+type RoutingTable struct {
+	Since   time.Time
+	Routes  []Route
+	Default string
+}
+
+// Router tells where an entity was likely created from the machine field
+// of its ID, for reading from the shard that wrote it. Tables are versioned
+// by time, so IDs keep routing to their original shard after machine IDs
+// are reassigned.
+type Router struct {
+	decoder *Snowflake
+	tables  []RoutingTable // by Since, newest first
+}
+
+// NewRouter returns a Router decoding IDs with decoder. It returns
+// ErrInvalidRoute if two tables have the same Since, or the routes of a
+// table overlap or have First after Last.
+func NewRouter(decoder *Snowflake, tables ...RoutingTable) (*Router, error) {
+	tables = slices.Clone(tables)
+	slices.SortFunc(tables, func(a, b RoutingTable) int { return b.Since.Compare(a.Since) })
+
+	for i := range tables {
+		if i > 0 && tables[i].Since.Equal(tables[i-1].Since) {
+			return nil, fmt.Errorf("%w: two tables since %s", ErrInvalidRoute, tables[i].Since.Format(time.RFC3339))
+		}
+
+		routes := slices.Clone(tables[i].Routes)
+		slices.SortFunc(routes, func(a, b Route) int { return cmp.Compare(a.First, b.First) })
+		for j, r := range routes {
+			if r.First > r.Last {
+				return nil, fmt.Errorf("%w: range %d-%d is reversed", ErrInvalidRoute, r.First, r.Last)
+			}
+			if j > 0 && r.First <= routes[j-1].Last {
+				return nil, fmt.Errorf("%w: ranges %d-%d and %d-%d overlap", ErrInvalidRoute, routes[j-1].First, routes[j-1].Last, r.First, r.Last)
+			}
+		}
+		tables[i].Routes = routes
+	}
+
+	return &Router{decoder: decoder, tables: tables}, nil
+}
+
+// Route returns the target of the machine ID of id in the table in effect
+// when id was created. It returns ErrNoRoute if no table or route covers id.
+func (r *Router) Route(id uint64) (string, error) {
+	p := r.decoder.Decompose(id)
+
+	return r.RouteAt(p.MachineID, p.Time)
+}
+
+// RouteAt returns the target of machineID in the table in effect at t.
+func (r *Router) RouteAt(machineID uint64, t time.Time) (string, error) {
+	for _, table := range r.tables {
+		if t.Before(table.Since) {
+			continue
+		}
+		routes := table.Routes
+		i, _ := slices.BinarySearchFunc(routes, machineID, func(r Route, mid uint64) int { return cmp.Compare(r.Last, mid) })
+		if i < len(routes) && routes[i].First <= machineID {
+			return routes[i].Target, nil
+		}
+		if table.Default != "" {
+			return table.Default, nil
+		}
+		break
+	}
+
+	return "", fmt.Errorf("%w: %d at %s", ErrNoRoute, machineID, t.Format(time.RFC3339))
+}
+
+// ParseRoutingTables reads routing tables in a line-based text format:
+//
+//	# Routes in effect since the epoch.
+//	0-15    pg-eu-1
+//	16      pg-eu-2
+//	*       pg-default
+//
+//	[2024-06-01T00:00:00Z]
+//	0-31    pg-eu-3
+//
+// Each line maps a machine ID or an inclusive range to a target; "*" sets
+// the table's default. A line with an RFC 3339 time in brackets starts a
+// new table in effect from that time. Blank lines and lines starting with
+// # are ignored.
+func ParseRoutingTables(r io.Reader) ([]RoutingTable, error) {
+	var (
+		tables []RoutingTable
+		table  *RoutingTable
+	)
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "["); ok {
+			since, err := time.Parse(time.RFC3339, strings.TrimSuffix(rest, "]"))
+			if err != nil || !strings.HasSuffix(rest, "]") {
+				return nil, fmt.Errorf("%w: line %d: bad table header %q", ErrInvalidRoute, n, line)
+			}
+			tables = append(tables, RoutingTable{Since: since})
+			table = &tables[len(tables)-1]
+			continue
+		}
+
+		if table == nil {
+			tables = append(tables, RoutingTable{})
+			table = &tables[0]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: line %d: want machine IDs and a target", ErrInvalidRoute, n)
+		}
+		ids, target := fields[0], fields[1]
+		if ids == "*" {
+			table.Default = target
+			continue
+		}
+		route, err := parseRange(ids)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRoute, n, err)
+		}
+		route.Target = target
+		table.Routes = append(table.Routes, route)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func parseRange(s string) (Route, error) {
+	first, last, isRange := strings.Cut(s, "-")
+	lo, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return Route{}, err
+	}
+	hi := lo
+	if isRange {
+		if hi, err = strconv.ParseUint(last, 10, 64); err != nil {
+			return Route{}, err
+		}
+	}
+
+	return Route{First: lo, Last: hi}, nil
+}
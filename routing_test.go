@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+const testRoutes = `
+# Routes in effect since the epoch.
+0-15	pg-eu-1
+16      pg-eu-2
+*       pg-default
+
+[2024-06-01T00:00:00Z]
+0-31    pg-eu-3
+`
+
+func TestRouter(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cutover := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tables, err := ParseRoutingTables(strings.NewReader(testRoutes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := NewSnowflake(epoch, 0)
+	r, err := NewRouter(decoder, tables...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		machineID int
+		at        time.Time
+		want      string
+	}{
+		{3, epoch.Add(time.Hour), "pg-eu-1"},
+		{15, epoch.Add(time.Hour), "pg-eu-1"},
+		{16, epoch.Add(time.Hour), "pg-eu-2"},
+		{17, epoch.Add(time.Hour), "pg-default"},
+		{3, cutover, "pg-eu-3"},
+		{31, cutover.Add(time.Hour), "pg-eu-3"},
+		{32, cutover.Add(time.Hour), ""},
+	}
+	for _, tt := range tests {
+		gen := NewSnowflake(epoch, tt.machineID, WithClock(clocktest.NewFakeClock(tt.at)))
+		id, _ := gen.NextID()
+		got, err := r.Route(id)
+		if tt.want == "" {
+			if !errors.Is(err, ErrNoRoute) {
+				t.Errorf("machine %d at %s: expected ErrNoRoute, got %q, %v", tt.machineID, tt.at, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("machine %d at %s: got %q, %v, want %q", tt.machineID, tt.at, got, err, tt.want)
+		}
+	}
+}
+
+func TestRouterInvalid(t *testing.T) {
+	decoder := NewSnowflake(time.Time{}, 0)
+	for _, text := range []string{"0-7 a\n4 b", "7-3 a", "[2024-06-01T00:00:00Z]\n[2024-06-01T00:00:00Z]"} {
+		tables, err := ParseRoutingTables(strings.NewReader(text))
+		if err == nil {
+			_, err = NewRouter(decoder, tables...)
+		}
+		if !errors.Is(err, ErrInvalidRoute) {
+			t.Errorf("%q: expected ErrInvalidRoute, got %v", text, err)
+		}
+	}
+
+	for _, text := range []string{"x a", "1", "[yesterday]", "1 a b"} {
+		if _, err := ParseRoutingTables(strings.NewReader(text)); !errors.Is(err, ErrInvalidRoute) {
+			t.Errorf("%q: expected ErrInvalidRoute, got %v", text, err)
+		}
+	}
+}
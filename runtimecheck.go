@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvariantViolation = errors.New("generator invariant violated")
+
+// WithRuntimeChecks verifies the generator's invariants on every NextID:
+// IDs without their tag are strictly increasing (or, with a randomized
+// sequence, their ticks never decrease), every field is within its bit
+// range and the generator lock is held while the ID is built. A violation
+// is counted and returned as ErrInvariantViolation instead of the ID.
+// Intended for staging, where suspected state corruption needs to be
+// confirmed.
+func WithRuntimeChecks() Option {
+	return func(sf *Snowflake) {
+		sf.runtimeChecks = true
+	}
+}
+
+// InvariantViolations returns the number of violations detected by
+// WithRuntimeChecks. It is safe to call concurrently with NextID.
+func (sf *Snowflake) InvariantViolations() uint64 {
+	return sf.violations.Load()
+}
+
+// checkInvariants must be called with sf.mutex held.
+func (sf *Snowflake) checkInvariants(id uint64) error {
+	// The tag sits above the machine ID and sequence, so only the untagged
+	// ID increases.
+	untagged := sf.layout.untagged(id)
+
+	var reason string
+
+	switch {
+	case sf.mutex.TryLock():
+		sf.mutex.Unlock()
+		reason = "generator lock not held"
+	case uint64(sf.sequence) > sf.layout.MaxSequence():
+		reason = fmt.Sprintf("sequence %d out of range", sf.sequence)
+	case sf.machineID > sf.layout.MaxMachineID():
+		reason = fmt.Sprintf("machine id %d out of range", sf.machineID)
+	case sf.lastTimestamp < 0 || uint64(sf.lastTimestamp) > sf.layout.MaxTimestamp():
+		reason = fmt.Sprintf("timestamp %d out of range", sf.lastTimestamp)
+	case sf.randomizedSequence() && id>>sf.layout.timeShift() < sf.watermark>>sf.layout.timeShift():
+		reason = fmt.Sprintf("id %d from before the tick of %d", id, sf.watermark)
+	case !sf.randomizedSequence() && untagged <= sf.watermark:
+		reason = fmt.Sprintf("id %d not above watermark %d", untagged, sf.watermark)
+	default:
+		sf.watermark = untagged
+		return nil
+	}
+
+	sf.violations.Add(1)
+
+	return fmt.Errorf("%w: %s", ErrInvariantViolation, reason)
+}
@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestRuntimeChecks(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	sf := NewSnowflake(epoch, 5, WithClock(clock), WithRuntimeChecks())
+
+	for i := 0; i < 1<<SequenceBits+10; i++ {
+		if _, err := sf.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Simulate corrupted state: a rewound timestamp would reissue ids.
+	sf.lastTimestamp -= 5
+
+	_, err := sf.NextID()
+	if !errors.Is(err, ErrInvariantViolation) {
+		t.Fatalf("expected ErrInvariantViolation, got %v", err)
+	}
+	if sf.InvariantViolations() != 1 {
+		t.Errorf("expected 1 violation, got %d", sf.InvariantViolations())
+	}
+}
+
+func TestRuntimeChecksTagged(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	sf := NewSnowflake(epoch, 5, WithClock(clock), WithRuntimeChecks(),
+		WithLayout(Layout{TimeBits: 41, TagBits: 1, MachineBits: 10, SequenceBits: 12}))
+
+	// A lower tag in the same tick gives a smaller ID, which is not a
+	// violation.
+	for _, tag := range []uint8{1, 0} {
+		if _, err := sf.NextIDWithTag(tag); err != nil {
+			t.Fatalf("tag %d: %v", tag, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+)
+
+// WithRandomSequenceOffset starts every tick's sequence at a random value,
+// so that the sequence no longer reveals how many IDs were issued in the
+// tick. The sequence wraps around within the tick, so IDs from the same
+// tick are no longer ordered; IDs from different ticks still are.
+func WithRandomSequenceOffset() Option {
+	return func(sf *Snowflake) {
+		sf.randomOffset = true
+	}
+}
+
+// WithRandomSequenceStride steps through every tick's sequence space with a
+// random odd stride, which visits each sequence number exactly once, so
+// that adjacent IDs cannot be guessed from one another. Like
+// WithRandomSequenceOffset it gives up ordering within a tick.
+//
+// Both options draw from the WithEntropy source once per tick. ReserveBlock
+// always starts a fresh tick on a randomized generator.
+func WithRandomSequenceStride() Option {
+	return func(sf *Snowflake) {
+		sf.randomStride = true
+	}
+}
+
+func (sf *Snowflake) randomizedSequence() bool {
+	return sf.randomOffset || sf.randomStride
+}
+
+// startTick resets the sequence for a new tick. It must be called with
+// sf.mutex held.
+func (sf *Snowflake) startTick() {
+	sf.seqIndex = 0
+	if sf.randomOffset {
+		sf.seqOffset = sf.random16() & sf.seqMask
+	}
+	if sf.randomStride {
+		sf.seqStride = sf.random16()&sf.seqMask | 1
+	}
+	sf.sequence = sf.seqOffset
+}
+
+// random16 reads a random value from the entropy source. A failed read
+// leaves the tick's sequence unrandomized rather than failing NextID.
+func (sf *Snowflake) random16() uint16 {
+	entropy := sf.entropy
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+
+	var b [2]byte
+	io.ReadFull(entropy, b[:])
+
+	return binary.BigEndian.Uint16(b[:])
+}
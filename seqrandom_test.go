@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestRandomSequence(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	layout := Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 6}
+
+	for name, opts := range map[string][]Option{
+		"offset": {WithRandomSequenceOffset()},
+		"stride": {WithRandomSequenceStride()},
+		"both":   {WithRandomSequenceOffset(), WithRandomSequenceStride()},
+	} {
+		clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+		sf := NewSnowflake(epoch, 1, append(opts, WithClock(clock), WithLayout(layout), WithRuntimeChecks())...)
+
+		seen := make(map[uint64]bool)
+		firsts := make(map[uint64]bool)
+		var lastTick uint64
+		for i := 0; i < 10*64; i++ {
+			id, err := sf.NextID()
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if seen[id] {
+				t.Fatalf("%s: duplicate id %d", name, id)
+			}
+			seen[id] = true
+
+			ts, _, seq := layout.Decompose(id)
+			if ts < lastTick {
+				t.Fatalf("%s: tick went backwards", name)
+			}
+			if i%64 == 0 {
+				firsts[seq] = true
+			}
+			lastTick = ts
+		}
+
+		if lastTick != uint64(time.Hour/time.Millisecond)+9 {
+			t.Errorf("%s: expected 64 ids per tick over 10 ticks, ended at tick %d", name, lastTick)
+		}
+		if name != "stride" && len(firsts) < 3 {
+			t.Errorf("%s: first sequences of the ticks are not random: %v", name, firsts)
+		}
+
+		b, err := sf.ReserveBlock(8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Timestamp != int64(lastTick)+1 || b.FirstSequence != 0 {
+			t.Errorf("%s: expected the block to start a fresh tick, got %+v", name, b)
+		}
+		if id, _ := sf.NextID(); seen[id] || id <= b.ID(b.Len()-1) {
+			t.Errorf("%s: id %d after the block is not fresh", name, id)
+		}
+	}
+}
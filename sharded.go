@@ -0,0 +1,93 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sharded splits the sequence bits of a generator into a shard prefix and a
+// local counter, e.g. 4+8 of the default 12, and issues IDs from one
+// independent generator per shard. Goroutines on different shards never
+// contend, and a rollover only makes the goroutines of one shard wait.
+//
+// The IDs are laid out exactly like those of a single generator with the
+// same layout, epoch and machine ID, and decode with it, but IDs from
+// different shards in the same tick are not ordered by issue time. Each
+// shard can issue 1<<(SequenceBits-shardBits) IDs per tick.
+type Sharded struct {
+	shards []*Snowflake
+	pool   sync.Pool
+	next   atomic.Uint32
+}
+
+// NewSharded returns a generator with 1<<shardBits shards. Shards are
+// handed to goroutines through a sync.Pool, which keeps one per P in the
+// common case. NewSharded returns nil where NewSnowflake would, if
+// shardBits leaves no local sequence bits, or if opts include
+// WithMachineIDProvider, WithStateStore or WithHighWaterMark, which cannot
+// be shared between shards.
+func NewSharded(starttime time.Time, machineID int, shardBits uint, opts ...Option) *Sharded {
+	probe := Snowflake{layout: DefaultLayout}
+	for _, opt := range opts {
+		opt(&probe)
+	}
+	base := probe.layout
+	if probe.provider != nil || probe.store != nil || probe.highWater != nil || base.Validate() != nil {
+		return nil
+	}
+	if shardBits == 0 || shardBits >= base.SequenceBits || base.ValidateMachineID(machineID) != nil {
+		return nil
+	}
+
+	layout := base
+	layout.MachineBits += shardBits
+	layout.SequenceBits -= shardBits
+	opts = append(opts[:len(opts):len(opts)], WithLayout(layout))
+
+	s := &Sharded{shards: make([]*Snowflake, 1<<shardBits)}
+	for i := range s.shards {
+		sf := NewSnowflake(starttime, machineID<<shardBits|i, opts...)
+		if sf == nil {
+			return nil
+		}
+		s.shards[i] = sf
+	}
+
+	// The pool is only empty when it was cleared by the GC or every shard is
+	// in use; shards are then shared round-robin, which their own locks make
+	// safe.
+	s.pool.New = func() any {
+		return s.shards[int(s.next.Add(1))%len(s.shards)]
+	}
+	for _, sf := range s.shards {
+		s.pool.Put(sf)
+	}
+
+	return s
+}
+
+// NextID issues an ID from the calling goroutine's shard.
+func (s *Sharded) NextID() (uint64, error) {
+	sf := s.pool.Get().(*Snowflake)
+	id, err := sf.NextID()
+	s.pool.Put(sf)
+
+	return id, err
+}
+
+// Shards returns the per-shard generators, e.g. to read their Stats.
+func (s *Sharded) Shards() []*Snowflake {
+	return s.shards
+}
+
+// Close closes all shards.
+func (s *Sharded) Close() error {
+	var errs []error
+	for _, sf := range s.shards {
+		errs = append(errs, sf.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,89 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestSharded(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	s := NewSharded(epoch, 5, 4, WithClock(clock))
+	if s == nil {
+		t.Fatal("NewSharded returned nil")
+	}
+	if len(s.Shards()) != 16 {
+		t.Fatalf("expected 16 shards, got %d", len(s.Shards()))
+	}
+
+	decoder := NewSnowflake(epoch, 5, WithClock(clock))
+	seen := make(map[uint64]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				id, err := s.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate id %d", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for id := range seen {
+		p := decoder.Decompose(id)
+		if p.MachineID != 5 || p.Time.Before(epoch.Add(time.Hour)) || p.Time.After(clock.Now()) {
+			t.Fatalf("id %d decodes to %+v", id, p)
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		shardBits uint
+		opts      []Option
+	}{
+		{"no shard bits", 0, nil},
+		{"no local bits", 12, nil},
+		{"provider", 4, []Option{WithMachineIDProvider(new(poolProvider))}},
+		{"high water mark", 4, []Option{WithHighWaterMark(new(memoryHighWater), time.Second)}},
+		{"machine fields", 4, []Option{WithMachineFields(RegionWorkerFields)}},
+	} {
+		if NewSharded(epoch, 5, tc.shardBits, append(tc.opts, WithClock(clock))...) != nil {
+			t.Errorf("%s: expected nil", tc.name)
+		}
+	}
+	if NewSharded(epoch, 1024, 4) != nil {
+		t.Error("expected nil for an out of range machine ID")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.NextID(); err == nil {
+		t.Error("expected an error after Close")
+	}
+}
+
+func BenchmarkShardedParallel(b *testing.B) {
+	s := NewSharded(time.Time{}, 1, 4)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.NextID()
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrClosed = errors.New("generator is closed")
+
+// MachineIDProvider leases machine IDs, e.g. from a coordination service,
+// so that no two running generators share one.
+type MachineIDProvider interface {
+	Acquire(ctx context.Context) (uint64, error)
+	Release(ctx context.Context, machineID uint64) error
+}
+
+// WithMachineIDProvider makes NewSnowflake lease its machine ID from p,
+// ignoring the machineID argument; NewSnowflake returns nil if the lease
+// fails. Shutdown returns the ID to p.
+func WithMachineIDProvider(p MachineIDProvider) Option {
+	return func(sf *Snowflake) {
+		sf.provider = p
+	}
+}
+
+// Close is Shutdown without a deadline.
+func (sf *Snowflake) Close() error {
+	return sf.Shutdown(context.Background())
+}
+
+// Shutdown interrupts calls waiting for the next tick, which return
+// ErrClosed, and waits for in-flight calls to finish, after which NextID and
+// ReserveBlock return ErrClosed. It then persists the final state to the
+// configured StateStore and releases the machine ID to the
+// MachineIDProvider, passing ctx to the provider. Calling it again returns
+// ErrClosed.
+func (sf *Snowflake) Shutdown(ctx context.Context) error {
+	sf.stopOnce.Do(func() {
+		if sf.done != nil {
+			close(sf.done)
+		}
+	})
+
+	sf.mutex.Lock()
+	if sf.closed {
+		sf.mutex.Unlock()
+		return ErrClosed
+	}
+	sf.closed = true
+	sf.mutex.Unlock()
+
+	var errs []error
+	if sf.store != nil {
+		errs = append(errs, sf.store.SaveState(sf.Snapshot()))
+	}
+	if sf.provider != nil {
+		errs = append(errs, sf.provider.Release(ctx, sf.machineID))
+	}
+
+	return errors.Join(errs...)
+}
+
+// release gives a leased machine ID back when NewSnowflake fails after
+// acquiring it.
+func (sf *Snowflake) release() {
+	if sf.provider != nil {
+		sf.provider.Release(context.Background(), sf.machineID)
+	}
+}
@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// poolProvider leases IDs from a fixed pool.
+type poolProvider struct {
+	mu   sync.Mutex
+	free []uint64
+}
+
+func (p *poolProvider) Acquire(ctx context.Context) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.free) == 0 {
+		return 0, errors.New("pool exhausted")
+	}
+	id := p.free[0]
+	p.free = p.free[1:]
+
+	return id, nil
+}
+
+func (p *poolProvider) Release(ctx context.Context, machineID uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.free = append(p.free, machineID)
+
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	pool := &poolProvider{free: []uint64{7}}
+
+	sf := NewSnowflake(epoch, 0, WithStateStore(store), WithMachineIDProvider(pool))
+	if sf.MachineID() != 7 {
+		t.Fatalf("expected leased machine id 7, got %d", sf.MachineID())
+	}
+	if NewSnowflake(epoch, 0, WithMachineIDProvider(pool)) != nil {
+		t.Fatal("expected creation to fail while the pool is empty")
+	}
+
+	last, _ := sf.NextID()
+
+	if err := sf.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sf.NextID(); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from NextID, got %v", err)
+	}
+	if _, err := sf.ReserveBlock(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from ReserveBlock, got %v", err)
+	}
+	if err := sf.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from a second Close, got %v", err)
+	}
+
+	if st, err := store.LoadState(); err != nil || st.LastTimestamp != sf.Snapshot().LastTimestamp {
+		t.Errorf("final state not flushed: %+v, %v", st, err)
+	}
+
+	next := NewSnowflake(epoch, 0, WithStateStore(store), WithMachineIDProvider(pool))
+	if next == nil {
+		t.Fatal("expected the released machine id to be leased again")
+	}
+	if id, _ := next.NextID(); id <= last {
+		t.Errorf("id %d issued after restart is not greater than %d", id, last)
+	}
+}
+
+func TestSupervisorShutdown(t *testing.T) {
+	s := NewSupervisor(nil)
+	gen := NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+	s.Add("a", Supervised{Factory: func() *Snowflake { return gen }})
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.NextID("a"); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
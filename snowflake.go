@@ -12,8 +12,13 @@ You should use NTP to keep your system clock accurate
 */
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,148 +27,571 @@ const (
 	EpochBits     = 42 // 139 years with custom epoch in milliseconds
 	MachineIDBits = 10 // up to 1024 nodes
 	SequenceBits  = 12 // up to 4096 unique ids for the same timestamp
-
-	maxNodeID = int(1<<MachineIDBits - 1)
 )
 
+// Snowflake is an ID generator. All of its methods are safe for concurrent
+// use; its state is only reachable through them. A Snowflake must not be
+// copied after first use; see Clone.
 type Snowflake struct {
-	StartTime int64
-	MachineID uint64
-	Sequence  uint16
+	_ noCopy
 
+	// Fields touched by every NextID come first so they share a cache line
+	// with the mutex guarding them.
+	mutex         sync.Mutex
 	lastTimestamp int64
-
-	mutex *sync.Mutex
+	lastObserved  int64
+	sequence      uint16
+	seqMask       uint16 // layout.MaxSequence(), precomputed
+	seqIndex      uint16 // IDs issued in the current tick, minus one
+	seqOffset     uint16
+	seqStride     uint16
+	randomOffset  bool
+	randomStride  bool
+	machineID     uint64
+	startTime     int64
+	unit          time.Duration
+	lowPrecision  time.Duration
+
+	layout Layout
+
+	checkBackward  bool
+	maxBackward    time.Duration
+	randomFallback bool
+	maxBorrow      time.Duration // 0 sleeps on rollover
+
+	runtimeChecks bool
+	watermark     uint64
+	violations    atomic.Uint64
+
+	current   atomic.Uint64 // last tick << 16 | seqIndex, for Capacity
+	issued    atomic.Uint64
+	rollovers atomic.Uint64
+	peak      atomic.Uint64
+	degraded  atomic.Uint64
+
+	clock      Clock
+	timeOffset time.Duration
+	store      StateStore
+	metrics    Metrics
+	drift      *DriftMonitor
+	entropy    io.Reader
+	allowed    map[uint64]struct{}
+
+	highWater     HighWaterStore
+	highWaterLead time.Duration
+	highWaterMark int64 // no ID has been issued at or after this tick
+
+	borrowMetrics BorrowMetrics
+	borrowed      int64 // ticks, as last reported to borrowMetrics
+
+	machineFields MachineFields
+	limiter       *tokenBucket
+	thresholds    []*epochThreshold
+	guard         *duplicateGuard
+	journal       *journal
+	hooks         *Hooks
+
+	health           healthWatch
+	healthThresholds HealthThresholds
+
+	provider      MachineIDProvider
+	closed        bool
+	done          chan struct{} // closed by Shutdown to interrupt waits
+	timer         *time.Timer   // for waits, guarded by mutex
+	stopOnce      sync.Once
+	strictRestart bool
+
+	backfillMachine uint64
+	backfill        bool
+
+	opts []Option // for Clone
+
+	// Keeps the hot fields of generators allocated next to each other, e.g.
+	// in a slice, off this generator's cache lines.
+	_ [cacheLineSize]byte
 }
 
-const snowflakeTimeUnit = 1e6 // nsec, i.e. 1 msec
+const cacheLineSize = 64
+
+// Option configures a Snowflake generator.
+type Option func(*Snowflake)
+
+const snowflakeTimeUnit = time.Millisecond // default tick length
 
+// epochStart is the epoch used when NewSnowflake is given a zero start time,
+// and the one assumed by ID methods.
 var epochStart = time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)
 
-func NewSnowflake(starttime time.Time, machineID int) *Snowflake {
+// NewSnowflake returns a generator issuing IDs with the given machine ID
+// and epoch, or nil if an option is invalid, the machine ID does not fit
+// the layout or starttime is in the future. A zero starttime selects the
+// 2019 package default; see RecommendedEpoch.
+func NewSnowflake(starttime time.Time, machineID int, opts ...Option) *Snowflake {
 	sf := new(Snowflake)
-	sf.mutex = new(sync.Mutex)
+	sf.clock = systemClock{}
+	sf.metrics = nopMetrics{}
+	sf.layout = DefaultLayout
+	sf.unit = snowflakeTimeUnit
+	sf.done = make(chan struct{})
+	sf.opts = slices.Clone(opts)
+
+	for _, opt := range opts {
+		opt(sf)
+	}
+	if sf.timeOffset != 0 {
+		sf.clock = offsetClock{Clock: sf.clock, offset: sf.timeOffset}
+	}
+	if sf.lowPrecision != 0 && (sf.unit <= 0 || sf.coarsen() != nil) {
+		return nil
+	}
+
+	if sf.layout.Validate() != nil || !sf.layout.TimeMajor() || !validUnit(sf.unit) {
+		return nil
+	}
+	if sf.machineFields != nil && sf.machineFields.Bits() != sf.layout.MachineBits {
+		return nil
+	}
+	if sf.limiter != nil && sf.limiter.perNano <= 0 {
+		return nil
+	}
+	if sf.guard != nil && sf.guard.window <= 0 {
+		return nil
+	}
+	if sf.highWater != nil && sf.highWaterLead < sf.unit {
+		return nil
+	}
+	if sf.journal != nil && len(sf.journal.entries) == 0 {
+		return nil
+	}
+	if sf.maxBorrow != 0 {
+		if sf.maxBorrow < sf.unit {
+			return nil
+		}
+		sf.borrowMetrics, _ = sf.metrics.(BorrowMetrics)
+	}
+	for _, th := range sf.thresholds {
+		if th.fraction < 0 || th.fraction > 1 {
+			return nil
+		}
+		th.tick = int64(th.fraction * float64(sf.layout.MaxTimestamp()))
+	}
+	sf.seqMask = uint16(sf.layout.MaxSequence())
+	sf.seqStride = 1
 
-	if starttime.After(time.Now()) {
+	if starttime.After(sf.clock.Now()) {
 		// Cannot be later than now
 		return nil
 	}
 
 	if starttime.IsZero() {
-		sf.StartTime = timeToSnowflakeUnit(epochStart)
+		sf.startTime = sf.toUnit(epochStart)
 	} else {
-		sf.StartTime = timeToSnowflakeUnit(starttime)
+		sf.startTime = sf.toUnit(starttime)
+	}
+	if ts := sf.elapsedTime(); ts > 0 && uint64(ts) > sf.layout.MaxTimestamp() {
+		// The time bits ran out before the generator was created, e.g.
+		// with a fine time unit and an old epoch.
+		return nil
+	}
+
+	if sf.provider != nil {
+		mid, err := sf.provider.Acquire(context.Background())
+		if err != nil {
+			return nil
+		}
+		if mid > sf.layout.MaxMachineID() {
+			sf.provider.Release(context.Background(), mid)
+			return nil
+		}
+		machineID = int(mid)
+	}
 
-		// RM ME
-		epochStart = starttime
+	// Check the ID as given: masking it would turn e.g. 1025 into an
+	// allowed 1.
+	if sf.layout.ValidateMachineID(machineID) != nil {
+		return nil
+	}
+	sf.machineID = uint64(machineID)
+	if !sf.machineAllowed(sf.machineID) || sf.randomFallback && !sf.validRandomFallback() || !sf.validBackfill() {
+		sf.release()
+		return nil
 	}
 
-	sf.MachineID = uint64(machineID & maxNodeID)
+	if sf.store != nil {
+		if err := sf.loadState(); err != nil {
+			sf.release()
+			return nil
+		}
+	}
+	if sf.strictRestart {
+		sf.skipStartTick()
+	}
+	if sf.highWater != nil {
+		if err := sf.loadHighWater(); err != nil {
+			sf.release()
+			return nil
+		}
+	}
 
 	return sf
 }
 
 func (sf *Snowflake) NextID() (uint64, error) {
+	return sf.issue(context.Background(), idRequest{})
+}
+
+// NextIDContext is NextID, but gives up waiting for the next tick after a
+// sequence rollover or for rate limit tokens when ctx is done and returns
+// its error.
+func (sf *Snowflake) NextIDContext(ctx context.Context) (uint64, error) {
+	return sf.issue(ctx, idRequest{})
+}
+
+// idRequest is the state of one ID request, passed down the calls made
+// while it holds the generator lock.
+type idRequest struct {
+	tag    uint64 // for NextIDWithTag
+	label  string // for NextIDLabeled
+	noWait bool   // for TryNextID, which must not sleep
+	events hookEvents
+}
+
+// issue runs nextID and the hooks.
+func (sf *Snowflake) issue(ctx context.Context, req idRequest) (uint64, error) {
+	id, err := sf.nextID(ctx, &req)
+	if sf.hooks != nil && err != errWouldBlock {
+		sf.hooks.run(id, req.events, err)
+	}
+
+	return id, err
+}
+
+// nextID issues an ID for req. If req.noWait is set it returns
+// errWouldBlock instead of sleeping, otherwise it sleeps until ctx is done.
+func (sf *Snowflake) nextID(ctx context.Context, req *idRequest) (uint64, error) {
+	if sf.drift != nil && !sf.drift.Synced() {
+		return 0, ErrClockUnsynced
+	}
+
 	sf.mutex.Lock()
 	defer sf.mutex.Unlock()
 
-	currentTimestamp := elapsedTime(sf.StartTime)
+	if sf.closed {
+		return 0, ErrClosed
+	}
+	// Waiting for a token must come before reading the clock, but a
+	// request that cannot wait only takes one once it is sure to be issued.
+	if sf.limiter != nil && !req.noWait {
+		if err := sf.takeTokens(ctx, req, 1); err != nil {
+			return 0, err
+		}
+	}
 
-	if sf.lastTimestamp < currentTimestamp {
-		sf.lastTimestamp = currentTimestamp
-		sf.Sequence = 0
-	} else {
-		sf.Sequence = (sf.Sequence + 1) & uint16(1<<SequenceBits-1)
-		if sf.Sequence == 0 {
-			sf.lastTimestamp++
+	currentTimestamp, err := sf.tick(ctx, req)
+	if err != nil {
+		return sf.degradedID(req, err)
+	}
 
-			// Adjust sleep time until next snowflakeTimeUnit which is < 1msec
-			standby := time.Duration(sf.lastTimestamp-currentTimestamp)*snowflakeTimeUnit - time.Duration(time.Now().UTC().UnixNano()%snowflakeTimeUnit)*time.Nanosecond
-			time.Sleep(standby)
+	if req.noWait {
+		if sf.mustWait(currentTimestamp) {
+			return 0, errWouldBlock
 		}
+		if sf.limiter != nil {
+			if err := sf.takeTokens(ctx, req, 1); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if sf.maxBorrow != 0 && sf.exhausted(currentTimestamp) {
+		if err := sf.checkBorrow(currentTimestamp); err != nil {
+			return 0, err
+		}
+	}
+	if err := sf.advance(ctx, req, currentTimestamp); err != nil {
+		return 0, err
 	}
 
-	if sf.Sequence > (1<<SequenceBits - 1) {
-		panic("Max sequence has been reached")
+	if sf.sequence > sf.seqMask {
+		return 0, sf.generationError(ErrSequenceExhausted)
 	}
 
-	if sf.lastTimestamp >= 1<<EpochBits {
-		return 0, errors.New("maximum timestamp has been reached")
+	if uint64(sf.lastTimestamp) > sf.layout.MaxTimestamp() {
+		return 0, sf.generationError(ErrEpochExhausted)
 	}
 
-	var id uint64
+	if sf.thresholds != nil {
+		sf.checkEpochThresholds()
+	}
+	if sf.highWater != nil {
+		if err := sf.guardHighWater(); err != nil {
+			return 0, err
+		}
+	}
 
-	id = uint64(sf.lastTimestamp) << (MachineIDBits + SequenceBits)
-	id |= sf.MachineID << SequenceBits
-	id |= uint64(sf.Sequence)
+	id := sf.layout.ComposeTagged(uint64(sf.lastTimestamp), req.tag, sf.machineID, uint64(sf.sequence))
+
+	if sf.guard != nil {
+		if id, err = sf.guardDuplicate(ctx, req, id); err != nil {
+			return 0, err
+		}
+	}
+
+	if sf.runtimeChecks {
+		if err := sf.checkInvariants(id); err != nil {
+			return 0, err
+		}
+	}
+
+	sf.record(1)
+	if sf.journal != nil {
+		sf.addJournal(id, 1, req.label)
+	}
 
 	return id, nil
 }
 
-func timeToSnowflakeUnit(t time.Time) int64 {
-	return t.UTC().UnixNano() / snowflakeTimeUnit
+// advance moves the generator to the next sequence number, or to the next
+// tick if currentTimestamp is newer or the sequence is exhausted. If the
+// wait for the next tick is interrupted, it returns the error and leaves
+// the generator in the exhausted tick. It must be called with sf.mutex
+// held.
+func (sf *Snowflake) advance(ctx context.Context, req *idRequest, currentTimestamp int64) error {
+	if sf.lastTimestamp < currentTimestamp {
+		sf.lastTimestamp = currentTimestamp
+		sf.startTick()
+	} else if sf.seqIndex < sf.seqMask {
+		sf.seqIndex++
+		sf.sequence = (sf.seqOffset + sf.seqIndex*sf.seqStride) & sf.seqMask
+	} else {
+		sf.rollover(req)
+		if sf.maxBorrow == 0 {
+			if err := sf.waitFor(ctx, req, sf.lastTimestamp+1); err != nil {
+				return err
+			}
+		}
+		sf.lastTimestamp++
+		sf.startTick()
+	}
+
+	return nil
 }
 
-func (sf Snowflake) SnowflakeUnitToTime(t int64) time.Time {
-	return time.Unix(0, (sf.StartTime*snowflakeTimeUnit)+(t*snowflakeTimeUnit))
+// mustWait reports if advance would have to sleep for the next tick. It
+// must be called with sf.mutex held.
+func (sf *Snowflake) mustWait(currentTimestamp int64) bool {
+	return sf.maxBorrow == 0 && sf.exhausted(currentTimestamp)
 }
 
-func elapsedTime(startTime int64) int64 {
-	return timeToSnowflakeUnit(time.Now()) - startTime
+// exhausted reports if advance has to roll over into the next tick. It
+// must be called with sf.mutex held.
+func (sf *Snowflake) exhausted(currentTimestamp int64) bool {
+	return sf.lastTimestamp >= currentTimestamp && sf.seqIndex >= sf.seqMask
 }
 
-func DecomposeParts(id uint64) (uint64, uint64, uint64) {
-	const maskMachineID = uint64(1<<MachineIDBits-1) << SequenceBits
-	const maskSequence = uint64(1<<SequenceBits - 1)
+// observe reads the clock and reports if it went backwards since the last
+// reading. It must be called with sf.mutex held.
+func (sf *Snowflake) observe() int64 {
+	currentTimestamp := sf.elapsedTime()
+	if currentTimestamp < sf.lastObserved {
+		sf.metrics.ClockBackwards(time.Duration(sf.lastObserved-currentTimestamp) * sf.unit)
+	}
+	sf.lastObserved = currentTimestamp
+
+	return currentTimestamp
+}
 
-	t := id >> (MachineIDBits + SequenceBits)
-	mid := id & maskMachineID >> SequenceBits
-	seq := id & maskSequence
+// waitFor sleeps until the generator's clock reaches tick. It sleeps
+// towards the start of the tick on the generator's clock and reads the
+// clock again after every wake-up, so an early wake-up or a clock stepped
+// back by a little only extends the wait. It returns ErrClockMovedBackwards
+// if the clock's wall time went back past the reading it started with,
+// ErrClosed if the generator is shut down and the error of ctx if it is
+// done. The time actually spent waiting is reported to the metrics and
+// hooks.
+func (sf *Snowflake) waitFor(ctx context.Context, req *idRequest, tick int64) error {
+	deadline := sf.fromUnit(sf.startTime + tick)
+	start := sf.clock.Now()
+	// Compare wall times: with monotonic readings Before would ignore a
+	// clock stepped back and wait for the whole step.
+	wall := start.Round(0)
+	slept := false
+	defer func() {
+		if !slept {
+			return
+		}
+		if waited := sf.clock.Now().Sub(start); waited > 0 {
+			sf.metrics.Waited(waited)
+			req.events.waited += waited
+		}
+	}()
+
+	for now := start; sf.toUnit(now)-sf.startTime < tick; now = sf.clock.Now() {
+		if now = now.Round(0); now.Before(wall) {
+			return fmt.Errorf("%w: by %s while waiting for the next tick", ErrClockMovedBackwards, wall.Sub(now))
+		}
+		slept = true
+		if err := sf.wait(ctx, deadline.Sub(now)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-	return t, mid, seq
+func timeToSnowflakeUnit(t time.Time) int64 {
+	return t.UTC().UnixNano() / int64(snowflakeTimeUnit)
+}
+
+func (sf *Snowflake) SnowflakeUnitToTime(t int64) time.Time {
+	return sf.fromUnit(sf.startTime + t)
+}
+
+func (sf *Snowflake) elapsedTime() int64 {
+	return sf.toUnit(sf.clock.Now()) - sf.startTime
+}
+
+// DecomposeParts splits an ID in DefaultLayout into timestamp, machine ID
+// and sequence. The timestamp counts ticks from whatever epoch the ID was
+// issued with; Snowflake.Decompose returns the time itself.
+//
+// Deprecated: DecomposeParts ignores custom layouts. Use Parser.Parse, or
+// Layout.Decompose for the raw fields.
+func DecomposeParts(id uint64) (uint64, uint64, uint64) {
+	return DefaultLayout.Decompose(id)
 }
 
 // Converts given time to comparable snowflake ID.
 // Can be used to check if an ID was created before or after.
 func (sf *Snowflake) TimeToSnowflakeID(t time.Time) uint64 {
-	nt := uint64(timeToSnowflakeUnit(t) - sf.StartTime)
+	nt := uint64(sf.toUnit(t) - sf.startTime)
 
-	return nt << (MachineIDBits + SequenceBits)
+	return sf.layout.Compose(nt, 0, 0)
 }
 
 // Converts given snowflake ID to time (in millisecond precision).
 func (sf *Snowflake) IDToTime(id uint64) time.Time {
-	t := uint64(id >> (MachineIDBits + SequenceBits))
+	t, _, _ := sf.layout.Decompose(id)
 
 	return sf.SnowflakeUnitToTime(int64(t))
 }
 
-/*
-func (sf Snowflake) ToString() string {
-	fromElapsedToTime(sf.StartTime)
-	return fmt.Sprintf("MachineID: %d\nSequence: %d\n", sf.MachineID, sf.Sequence)
-}*/
-/*
-func fromElapsedToTime(elapsed int64)  {
-	t := time.Unix(0, elapsed*snowflakeTimeUnit)
-	fmt.Printf("Time: %s (%d)\n", t.UTC(), t.UnixNano())
-}*/
+// Parts are the fields of an ID.
+type Parts struct {
+	Time time.Time
+	// Timestamp is the raw time field, in ticks since the epoch.
+	Timestamp uint64
+	MachineID uint64
+	Sequence  uint64
+	// Tag is the value given to NextIDWithTag, if the layout has tag bits.
+	Tag uint64
 
-/*
-func Decompose(id uint64, starttime time.Time) {
-	t, mid, seq := DecomposeParts(id)
+	// Machine holds the machine ID split into the fields declared with
+	// WithMachineFields, or nil.
+	Machine map[string]uint64
 
-	if !starttime.IsZero() {
-		t += uint64(timeToSnowflakeUnit(starttime)) + t
+	// UnknownMachine is set if the generator has an allowlist of machine
+	// IDs and MachineID is not on it.
+	UnknownMachine bool
+}
+
+// Decompose splits id using the generator's own layout and epoch.
+func (sf *Snowflake) Decompose(id uint64) Parts {
+	p := sf.Parser().Parse(id)
+	p.UnknownMachine = !sf.machineAllowed(p.MachineID)
+	if sf.machineFields != nil {
+		p.Machine = sf.machineFields.Decompose(p.MachineID)
 	}
 
-	t2 := time.Unix(0, epochStart.UTC().UnixNano()+ int64(t)*snowflakeTimeUnit)
+	return p
+}
+
+// Compose builds the untagged ID the generator would issue at t with the
+// given machine ID and sequence; it is the inverse of Decompose for times
+// that are a whole number of ticks. It returns ErrPartRange if t is before
+// the epoch or past the last timestamp, or machine or seq does not fit.
+func (sf *Snowflake) Compose(t time.Time, machine, seq uint64) (uint64, error) {
+	epoch := sf.Epoch()
+	if t.Before(epoch) {
+		return 0, fmt.Errorf("%w: %s is before the epoch %s", ErrPartRange, t, epoch)
+	}
+	ts := sf.ticksSince(epoch, t)
+	switch {
+	case uint64(ts) > sf.layout.MaxTimestamp():
+		return 0, fmt.Errorf("%w: %s is past the last timestamp", ErrPartRange, t)
+	case machine > sf.layout.MaxMachineID():
+		return 0, fmt.Errorf("%w: machine id %d does not fit in %d bits", ErrPartRange, machine, sf.layout.MachineBits)
+	case seq > sf.layout.MaxSequence():
+		return 0, fmt.Errorf("%w: sequence %d does not fit in %d bits", ErrPartRange, seq, sf.layout.SequenceBits)
+	}
+
+	return sf.layout.Compose(uint64(ts), machine, seq), nil
+}
+
+// ticksSince returns the whole ticks from epoch to the later time t. Unlike
+// toUnit it works on seconds, so it does not overflow for times centuries
+// from 1970.
+func (sf *Snowflake) ticksSince(epoch, t time.Time) int64 {
+	secs := t.Unix() - epoch.Unix()
+	nanos := int64(t.Nanosecond() - epoch.Nanosecond())
+	if nanos < 0 {
+		secs--
+		nanos += int64(time.Second)
+	}
+	if sf.unit > time.Second {
+		return secs / int64(sf.unit/time.Second)
+	}
+
+	return secs*int64(time.Second/sf.unit) + nanos/int64(sf.unit)
+}
 
-	fmt.Printf(
-		"time: %s (%d)\nmachine id: %d\nseq: %d\n",
-		t2.UTC(),
-		t2.UnixNano(),
-		mid,
-		seq,
-		)
-}*/
+// MachineID returns the machine ID embedded in the generator's IDs.
+func (sf *Snowflake) MachineID() uint64 {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	return sf.machineID
+}
+
+// Epoch returns the time the generator's timestamps count from.
+func (sf *Snowflake) Epoch() time.Time {
+	return sf.SnowflakeUnitToTime(0)
+}
+
+// LastTime returns the time embedded in the most recently issued ID, or the
+// epoch if none has been issued.
+func (sf *Snowflake) LastTime() time.Time {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	return sf.SnowflakeUnitToTime(sf.lastTimestamp)
+}
+
+// String describes the generator for diagnostics, e.g. in startup logs. It
+// takes the generator lock only to read the machine ID.
+func (sf *Snowflake) String() string {
+	st := sf.Stats()
+
+	return fmt.Sprintf("snowflake(machine=%d epoch=%s unit=%s layout=%s issued=%d rollovers=%d peak=%d)",
+		sf.MachineID(), sf.Epoch().UTC().Format(time.RFC3339), sf.unit, sf.layout,
+		st.Issued, st.Rollovers, st.PeakPerTick)
+}
+
+// LogValue implements slog.LogValuer with the fields of String.
+func (sf *Snowflake) LogValue() slog.Value {
+	st := sf.Stats()
+
+	return slog.GroupValue(
+		slog.Uint64("machine_id", sf.MachineID()),
+		slog.Time("epoch", sf.Epoch().UTC()),
+		slog.Duration("unit", sf.unit),
+		slog.Group("layout",
+			slog.Uint64("time_bits", uint64(sf.layout.TimeBits)),
+			slog.Uint64("tag_bits", uint64(sf.layout.TagBits)),
+			slog.Uint64("machine_bits", uint64(sf.layout.MachineBits)),
+			slog.Uint64("sequence_bits", uint64(sf.layout.SequenceBits)),
+		),
+		slog.Uint64("issued", st.Issued),
+		slog.Uint64("rollovers", st.Rollovers),
+		slog.Uint64("peak_per_tick", st.PeakPerTick),
+	)
+}
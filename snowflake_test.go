@@ -1,11 +1,16 @@
 package snowflake
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	mapset "github.com/deckarep/golang-set"
+	"github.com/fethican/snowflake-go/clocktest"
 )
 
 func TestGenerate10Sec(t *testing.T) {
@@ -78,16 +83,16 @@ func TestGenerateParallel(t *testing.T) {
 		go generate()
 	}
 
-	set := mapset.NewSet()
+	set := make(map[uint64]struct{})
 	for i := 0; i < numID*numGenerator; i++ {
 		id := <-consumer
-		if set.Contains(id) {
+		if _, ok := set[id]; ok {
 			t.Fatal("duplicated id")
 		} else {
-			set.Add(id)
+			set[id] = struct{}{}
 		}
 	}
-	t.Logf("number of id: %d\n", set.Cardinality())
+	t.Logf("number of id: %d\n", len(set))
 }
 
 func TestEpochOverflow(t *testing.T) {
@@ -109,17 +114,226 @@ func TestEpochOverflow(t *testing.T) {
 
 	sf2 := NewSnowflake(year140, 137)
 
-	_, err = sf2.NextID()
+	if sf2 != nil {
+		t.Error("should reject an epoch whose timestamps ran out")
+	}
+}
+
+// NextID and ReserveBlock must not allocate on the happy path, including
+// when a tick's sequence is exhausted and the generator waits.
+func TestNextIDAllocs(t *testing.T) {
+	sf := NewSnowflake(time.Now(), 34)
+
+	if got := testing.AllocsPerRun(10000, func() { sf.NextID() }); got != 0 {
+		t.Errorf("NextID: %v allocations, want 0", got)
+	}
+	if got := testing.AllocsPerRun(100, func() { sf.ReserveBlock(64) }); got != 0 {
+		t.Errorf("ReserveBlock: %v allocations, want 0", got)
+	}
+
+	// AllocsPerRun rounds down, which hides an allocation per rollover;
+	// count the mallocs of calls that exhaust several ticks instead. The
+	// runtime rarely allocates in the background, so a few attempts are
+	// allowed; an allocation per rollover fails all of them.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	for range 4 << SequenceBits {
+		sf.NextID() // the first rollover creates the reused timer
+	}
+	var n uint64
+	for range 3 {
+		rollovers := sf.Stats().Rollovers
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		for range 20 << SequenceBits {
+			sf.NextID()
+		}
+		runtime.ReadMemStats(&after)
 
-	if err == nil {
-		t.Error("should return maximum timestamp error")
+		if sf.Stats().Rollovers == rollovers {
+			t.Fatal("expected rollovers")
+		}
+		if n = after.Mallocs - before.Mallocs; n == 0 {
+			break
+		}
+	}
+	if n != 0 {
+		t.Errorf("NextID with rollovers: %d allocations, want 0", n)
 	}
 }
 
 func BenchmarkSnowflake(b *testing.B) {
 	sf := NewSnowflake(time.Now(), 34)
 
+	b.ReportAllocs()
 	for n := 0; n < b.N; n++ {
 		sf.NextID()
 	}
 }
+
+func BenchmarkNextIDParallel(b *testing.B) {
+	sf := NewSnowflake(time.Now(), 34)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sf.NextID()
+		}
+	})
+}
+
+// BenchmarkNextIDAdjacentGenerators gives every goroutine its own generator,
+// all allocated back to back, to expose false sharing between them.
+func BenchmarkNextIDAdjacentGenerators(b *testing.B) {
+	gens := make([]*Snowflake, runtime.GOMAXPROCS(0))
+	for i := range gens {
+		gens[i] = NewSnowflake(time.Now(), i)
+	}
+
+	var next atomic.Int32
+	b.RunParallel(func(pb *testing.PB) {
+		sf := gens[int(next.Add(1)-1)%len(gens)]
+		for pb.Next() {
+			sf.NextID()
+		}
+	})
+}
+
+func TestAccessors(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	if NewSnowflake(epoch, 1025, WithClock(clock), WithAllowedMachineIDs(1)) != nil {
+		t.Error("expected a machine id out of range not to be masked into the allowlist")
+	}
+
+	sf := NewSnowflake(epoch, 1023, WithClock(clock))
+	if sf.MachineID() != 1023 {
+		t.Errorf("got machine id %d, want 1023", sf.MachineID())
+	}
+	if !sf.Epoch().Equal(epoch) {
+		t.Errorf("got epoch %s, want %s", sf.Epoch(), epoch)
+	}
+	if !sf.LastTime().Equal(epoch) {
+		t.Errorf("expected last time to be the epoch before any id, got %s", sf.LastTime())
+	}
+
+	sf.NextID()
+	if want := epoch.Add(time.Hour); !sf.LastTime().Equal(want) {
+		t.Errorf("got last time %s, want %s", sf.LastTime(), want)
+	}
+}
+
+func TestIndependentEpochs(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(now)
+
+	def := NewSnowflake(time.Time{}, 1, WithClock(clock))
+	custom := NewSnowflake(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), 2, WithClock(clock))
+
+	a, _ := def.NextID()
+	b, _ := custom.NextID()
+
+	for _, tc := range []struct {
+		sf  *Snowflake
+		id  uint64
+		mid uint64
+	}{{def, a, 1}, {custom, b, 2}} {
+		p := tc.sf.Decompose(tc.id)
+		if !p.Time.Equal(now) || p.MachineID != tc.mid || p.Sequence != 0 {
+			t.Errorf("Decompose(%d) = %+v, want time %s machine %d", tc.id, p, now, tc.mid)
+		}
+	}
+
+	if !ID(a).Time().Equal(now) {
+		t.Errorf("a custom epoch generator changed the package epoch: got %s", ID(a).Time())
+	}
+}
+
+func TestStringAndLogValue(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 5, WithClock(clock))
+	sf.NextID()
+	sf.NextID()
+
+	want := "snowflake(machine=5 epoch=2020-01-01T00:00:00Z unit=1ms layout=42/10/12 issued=2 rollovers=0 peak=2)"
+	if got := sf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("started", "generator", sf)
+	var rec struct {
+		Generator struct {
+			MachineID uint64 `json:"machine_id"`
+			Epoch     time.Time
+			Layout    Layout
+			Issued    uint64
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	g := rec.Generator
+	if g.MachineID != 5 || !g.Epoch.Equal(epoch) || g.Layout != DefaultLayout || g.Issued != 2 {
+		t.Errorf("unexpected log record %s", buf.String())
+	}
+}
+
+func TestComposeDecompose(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(time.Hour)
+
+	for _, sf := range []*Snowflake{
+		NewSnowflake(epoch, 1, WithClock(clocktest.NewFakeClock(now))),
+		NewSnowflake(epoch, 1, WithClock(clocktest.NewFakeClock(now)), WithTimeUnit(TimeUnit10Milliseconds)),
+		NewSnowflake(epoch, 1, WithClock(clocktest.NewFakeClock(now)), WithTimeUnit(TimeUnitSecond),
+			WithLayout(Layout{TimeBits: 41, TagBits: 3, MachineBits: 8, SequenceBits: 12})),
+	} {
+		l := sf.Layout()
+		maxTime := sf.SnowflakeUnitToTime(int64(l.MaxTimestamp()))
+		for _, tt := range []struct {
+			at       time.Time
+			mid, seq uint64
+		}{
+			{epoch, 0, 0},
+			{epoch, l.MaxMachineID(), l.MaxSequence()},
+			{now, 7, l.MaxSequence()},
+			{maxTime, 0, 0},
+			{maxTime, l.MaxMachineID(), l.MaxSequence()},
+		} {
+			id, err := sf.Compose(tt.at, tt.mid, tt.seq)
+			if err != nil {
+				t.Errorf("%s: Compose(%s, %d, %d): %v", l, tt.at, tt.mid, tt.seq, err)
+				continue
+			}
+			p := sf.Decompose(id)
+			if !p.Time.Equal(tt.at) || p.MachineID != tt.mid || p.Sequence != tt.seq {
+				t.Errorf("%s: Decompose(Compose(%s, %d, %d)) = %+v", l, tt.at, tt.mid, tt.seq, p)
+			}
+			if again, err := sf.Compose(p.Time, p.MachineID, p.Sequence); err != nil || again != id {
+				t.Errorf("%s: Compose(Decompose(%d)) = %d, %v", l, id, again, err)
+			}
+		}
+
+		for _, tt := range []struct {
+			at       time.Time
+			mid, seq uint64
+		}{
+			{epoch.Add(-time.Nanosecond), 0, 0},
+			{maxTime.Add(sf.TimeUnit()), 0, 0},
+			{now, l.MaxMachineID() + 1, 0},
+			{now, 0, l.MaxSequence() + 1},
+		} {
+			if _, err := sf.Compose(tt.at, tt.mid, tt.seq); !errors.Is(err, ErrPartRange) {
+				t.Errorf("%s: Compose(%s, %d, %d): expected ErrPartRange, got %v", l, tt.at, tt.mid, tt.seq, err)
+			}
+		}
+	}
+
+	sf := NewSnowflake(epoch, 3, WithClock(clocktest.NewFakeClock(now)))
+	id, _ := sf.NextID()
+	p := sf.Decompose(id)
+	if again, _ := sf.Compose(p.Time, p.MachineID, p.Sequence); again != id {
+		t.Errorf("Compose(Decompose(%d)) = %d", id, again)
+	}
+}
@@ -0,0 +1,239 @@
+// Package snowflakeaudit scans streams of snowflake IDs for duplicates and
+// anomalies, e.g. after a suspected collision.
+package snowflakeaudit
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"math/bits"
+	"slices"
+	"strings"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+// Duplicate is an ID seen more than once.
+type Duplicate struct {
+	ID    uint64
+	Count int
+	// Lines are the positions of its occurrences, counting from 1.
+	Lines []int
+}
+
+// Regression is an ID whose time is earlier than that of the previous ID
+// from the same machine.
+type Regression struct {
+	ID, Previous uint64
+	MachineID    uint64
+	Line         int
+	By           time.Duration
+}
+
+// MachineStats summarizes the IDs of one machine.
+type MachineStats struct {
+	IDs         int
+	First, Last time.Time
+	// PeakPerBucket is the largest number of IDs in one bucket.
+	PeakPerBucket int
+	// Histogram[i] counts the buckets holding between 2^i and 2^(i+1)-1 IDs.
+	Histogram []int
+
+	buckets map[int64]int
+}
+
+// Report is the result of an audit.
+type Report struct {
+	Total       int
+	Invalid     int
+	Duplicates  []Duplicate
+	Regressions []Regression
+	// UnknownMachines counts the IDs of each machine ID the decoder does
+	// not allow, see snowflake.WithAllowedMachineIDs.
+	UnknownMachines map[uint64]int
+	Machines        map[uint64]*MachineStats
+	// Bucket is the width of the throughput histogram buckets.
+	Bucket time.Duration
+}
+
+// Auditor accumulates a Report from IDs added in stream order. It keeps
+// every ID in memory.
+type Auditor struct {
+	decoder *snowflake.Snowflake
+	bucket  time.Duration
+
+	line   int
+	seen   map[uint64][]int
+	last   map[uint64]snowflake.Parts
+	lastID map[uint64]uint64
+	report Report
+}
+
+// New returns an Auditor decoding IDs with the epoch, layout, time unit and
+// allowed machine IDs of decoder and counting throughput per bucket, which
+// defaults to one second.
+func New(decoder *snowflake.Snowflake, bucket time.Duration) *Auditor {
+	if bucket <= 0 {
+		bucket = time.Second
+	}
+
+	return &Auditor{
+		decoder: decoder,
+		bucket:  bucket,
+		seen:    make(map[uint64][]int),
+		last:    make(map[uint64]snowflake.Parts),
+		lastID:  make(map[uint64]uint64),
+		report: Report{
+			UnknownMachines: make(map[uint64]int),
+			Machines:        make(map[uint64]*MachineStats),
+			Bucket:          bucket,
+		},
+	}
+}
+
+// Add records the next ID of the stream.
+func (a *Auditor) Add(id uint64) {
+	a.line++
+	a.report.Total++
+	a.seen[id] = append(a.seen[id], a.line)
+
+	p := a.decoder.Decompose(id)
+	if p.UnknownMachine {
+		a.report.UnknownMachines[p.MachineID]++
+	}
+
+	if prev, ok := a.last[p.MachineID]; ok && p.Time.Before(prev.Time) {
+		a.report.Regressions = append(a.report.Regressions, Regression{
+			ID:        id,
+			Previous:  a.lastID[p.MachineID],
+			MachineID: p.MachineID,
+			Line:      a.line,
+			By:        prev.Time.Sub(p.Time),
+		})
+	}
+	a.last[p.MachineID], a.lastID[p.MachineID] = p, id
+
+	m := a.report.Machines[p.MachineID]
+	if m == nil {
+		m = &MachineStats{First: p.Time, Last: p.Time, buckets: make(map[int64]int)}
+		a.report.Machines[p.MachineID] = m
+	}
+	m.IDs++
+	if p.Time.Before(m.First) {
+		m.First = p.Time
+	}
+	if p.Time.After(m.Last) {
+		m.Last = p.Time
+	}
+	m.buckets[p.Time.UnixNano()/int64(a.bucket)]++
+}
+
+// Skip records a line that could not be parsed as an ID.
+func (a *Auditor) Skip() {
+	a.line++
+	a.report.Invalid++
+}
+
+// Report returns the findings so far. Duplicates are sorted by ID.
+func (a *Auditor) Report() Report {
+	r := a.report
+	r.Duplicates = nil
+	for id, lines := range a.seen {
+		if len(lines) > 1 {
+			r.Duplicates = append(r.Duplicates, Duplicate{ID: id, Count: len(lines), Lines: lines})
+		}
+	}
+	slices.SortFunc(r.Duplicates, func(x, y Duplicate) int {
+		return cmp.Compare(x.ID, y.ID)
+	})
+
+	for _, m := range r.Machines {
+		m.PeakPerBucket, m.Histogram = 0, nil
+		for _, n := range m.buckets {
+			m.PeakPerBucket = max(m.PeakPerBucket, n)
+			i := bits.Len(uint(n)) - 1
+			for len(m.Histogram) <= i {
+				m.Histogram = append(m.Histogram, 0)
+			}
+			m.Histogram[i]++
+		}
+	}
+
+	return r
+}
+
+// Scan audits one ID per line of r, in any encoding accepted by
+// snowflake.ParseAny. Blank lines are ignored, unparsable lines counted as
+// Invalid.
+func Scan(r io.Reader, decoder *snowflake.Snowflake, bucket time.Duration) (Report, error) {
+	a := New(decoder, bucket)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			a.line++
+			continue
+		}
+		id, _, err := snowflake.ParseAny(line)
+		if err != nil {
+			a.Skip()
+			continue
+		}
+		a.Add(uint64(id))
+	}
+
+	return a.Report(), sc.Err()
+}
+
+// Clean reports whether the audit found no duplicates, regressions or
+// unknown machines.
+func (r Report) Clean() bool {
+	return len(r.Duplicates) == 0 && len(r.Regressions) == 0 && len(r.UnknownMachines) == 0
+}
+
+// WriteTo writes a human-readable summary of r.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "ids: %d, invalid lines: %d, machines: %d\n", r.Total, r.Invalid, len(r.Machines))
+	fmt.Fprintf(&b, "duplicates: %d\n", len(r.Duplicates))
+	for _, d := range r.Duplicates {
+		fmt.Fprintf(&b, "  %d seen %d times, lines %v\n", d.ID, d.Count, d.Lines)
+	}
+	fmt.Fprintf(&b, "time regressions: %d\n", len(r.Regressions))
+	for _, g := range r.Regressions {
+		fmt.Fprintf(&b, "  line %d: machine %d id %d is %s before %d\n", g.Line, g.MachineID, g.ID, g.By, g.Previous)
+	}
+	fmt.Fprintf(&b, "unknown machines: %d\n", len(r.UnknownMachines))
+	for _, mid := range sortedKeys(r.UnknownMachines) {
+		fmt.Fprintf(&b, "  machine %d: %d ids\n", mid, r.UnknownMachines[mid])
+	}
+	fmt.Fprintf(&b, "throughput per %s:\n", r.Bucket)
+	for _, mid := range sortedKeys(r.Machines) {
+		m := r.Machines[mid]
+		fmt.Fprintf(&b, "  machine %d: %d ids from %s to %s, peak %d\n",
+			mid, m.IDs, m.First.UTC().Format(time.RFC3339Nano), m.Last.UTC().Format(time.RFC3339Nano), m.PeakPerBucket)
+		for i, n := range m.Histogram {
+			if n > 0 {
+				fmt.Fprintf(&b, "    %d-%d ids: %d buckets\n", 1<<i, 1<<(i+1)-1, n)
+			}
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+func sortedKeys[V any](m map[uint64]V) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
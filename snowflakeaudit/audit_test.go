@@ -0,0 +1,97 @@
+package snowflakeaudit
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestScan(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	decoder := snowflake.NewSnowflake(epoch, 1, snowflake.WithAllowedMachineIDs(1, 2))
+
+	gen1 := snowflake.NewSnowflake(epoch, 1, snowflake.WithClock(clock))
+	gen2 := snowflake.NewSnowflake(epoch, 2, snowflake.WithClock(clock))
+	rogue := snowflake.NewSnowflake(epoch, 7, snowflake.WithClock(clock))
+
+	var ids []uint64
+	for i := 0; i < 10; i++ {
+		a, _ := gen1.NextID()
+		b, _ := gen2.NextID()
+		ids = append(ids, a, b)
+		clock.Advance(300 * time.Millisecond)
+	}
+	late, _ := gen1.NextID()
+	early := snowflake.NewSnowflake(epoch, 1, snowflake.WithClock(clocktest.NewFakeClock(epoch.Add(time.Minute))))
+	old, _ := early.NextID()
+	r, _ := rogue.NextID()
+
+	var in strings.Builder
+	for _, id := range ids {
+		fmt.Fprintln(&in, id)
+	}
+	fmt.Fprintln(&in, snowflake.ID(ids[19]).Hex()) // duplicate in another encoding
+	fmt.Fprintln(&in, late)
+	fmt.Fprintln(&in, old) // machine 1 going back in time
+	fmt.Fprintln(&in, "")
+	fmt.Fprintln(&in, "not-an-id!")
+	fmt.Fprintln(&in, r)
+
+	rep, err := Scan(strings.NewReader(in.String()), decoder, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rep.Total != 24 || rep.Invalid != 1 {
+		t.Errorf("got %d ids and %d invalid lines", rep.Total, rep.Invalid)
+	}
+	if len(rep.Duplicates) != 1 || rep.Duplicates[0].ID != ids[19] || fmt.Sprint(rep.Duplicates[0].Lines) != "[20 21]" {
+		t.Errorf("unexpected duplicates %+v", rep.Duplicates)
+	}
+	if len(rep.Regressions) != 1 || rep.Regressions[0].ID != old || rep.Regressions[0].Previous != late || rep.Regressions[0].Line != 23 {
+		t.Errorf("unexpected regressions %+v", rep.Regressions)
+	}
+	if len(rep.UnknownMachines) != 1 || rep.UnknownMachines[7] != 1 {
+		t.Errorf("unexpected unknown machines %v", rep.UnknownMachines)
+	}
+	if rep.Clean() {
+		t.Error("report should not be clean")
+	}
+
+	// Machine 2 issued one ID every 300ms from 01:00:00 to 01:00:02.7,
+	// plus the duplicate: 4, 3 and 4 IDs in three one-second buckets.
+	m := rep.Machines[2]
+	if m.IDs != 11 || m.PeakPerBucket != 4 || fmt.Sprint(m.Histogram) != "[0 1 2]" {
+		t.Errorf("unexpected machine 2 stats %+v", m)
+	}
+
+	var out bytes.Buffer
+	if _, err := rep.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"duplicates: 1", "time regressions: 1", "machine 7: 1 ids"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report misses %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	decoder := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+
+	a := New(decoder, 0)
+	for i := 0; i < 1000; i++ {
+		id, _ := decoder.NextID()
+		a.Add(id)
+	}
+
+	if rep := a.Report(); !rep.Clean() || rep.Total != 1000 || rep.Bucket != time.Second {
+		t.Errorf("unexpected report %+v", rep)
+	}
+}
@@ -0,0 +1,73 @@
+package snowflakecloud
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+var ErrUnknownZone = errors.New("zone not mapped")
+
+// Derivation turns instance metadata into a machine ID: ZoneBits high-order
+// bits identify the zone or region, the remaining bits are a hash of the
+// instance ID.
+//
+// Hashed IDs are stable and well distributed but not guaranteed unique: with
+// n instances in a zone and b hash bits, a collision has a probability of
+// about n²/2^(b+1). Combine with a MachineIDProvider where that matters.
+type Derivation struct {
+	// MachineBits is the width of the machine ID. Defaults to the machine
+	// bits of snowflake.DefaultLayout.
+	MachineBits uint
+	ZoneBits    uint
+	// Zones maps zone names, or region names for instances whose zone is not
+	// listed, to zone field values. If nil, the zone name is hashed into
+	// the zone bits instead, which may map two zones to the same value.
+	Zones map[string]uint64
+}
+
+// Fields returns the machine ID structure of d, for WithMachineFields.
+func (d Derivation) Fields() snowflake.MachineFields {
+	bits := d.MachineBits
+	if bits == 0 {
+		bits = snowflake.DefaultLayout.MachineBits
+	}
+
+	return snowflake.MachineFields{{Name: "zone", Bits: d.ZoneBits}, {Name: "instance", Bits: bits - d.ZoneBits}}
+}
+
+// MachineID derives the machine ID of the instance described by md. It
+// returns ErrUnknownZone if Zones is set and maps neither md.Zone nor
+// md.Region.
+func (d Derivation) MachineID(md Metadata) (uint64, error) {
+	fields := d.Fields()
+	if d.ZoneBits >= fields.Bits() {
+		return 0, fmt.Errorf("%d zone bits leave no room for the instance in %d machine bits", d.ZoneBits, fields.Bits())
+	}
+	if md.InstanceID == "" {
+		return 0, errors.New("metadata has no instance id")
+	}
+
+	var zone uint64
+	if d.Zones != nil {
+		var ok bool
+		if zone, ok = d.Zones[md.Zone]; !ok {
+			if zone, ok = d.Zones[md.Region]; !ok {
+				return 0, fmt.Errorf("%w: %q (region %q)", ErrUnknownZone, md.Zone, md.Region)
+			}
+		}
+	} else if d.ZoneBits > 0 {
+		zone = hash(md.Zone) & (1<<d.ZoneBits - 1)
+	}
+
+	return fields.Compose(zone, hash(md.InstanceID)&(1<<fields[1].Bits-1))
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+
+	return h.Sum64()
+}
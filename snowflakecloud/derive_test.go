@@ -0,0 +1,65 @@
+package snowflakecloud
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+func TestDerivation(t *testing.T) {
+	d := Derivation{ZoneBits: 2, Zones: map[string]uint64{"eu-west-1a": 1, "eu-west-1b": 2, "us-east-1": 3}}
+	md := Metadata{InstanceID: "i-0123456789abcdef0", Zone: "eu-west-1b", Region: "eu-west-1"}
+
+	mid, err := d.MachineID(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := d.MachineID(md); again != mid {
+		t.Errorf("derivation is not stable: %d, %d", mid, again)
+	}
+	if mid > snowflake.DefaultLayout.MaxMachineID() {
+		t.Fatalf("machine id %d out of range", mid)
+	}
+
+	parts := d.Fields().Decompose(mid)
+	if parts["zone"] != 2 {
+		t.Errorf("expected zone 2 in the high bits, got %v", parts)
+	}
+
+	// Zones not listed fall back to their region.
+	md.Zone, md.Region = "us-east-1c", "us-east-1"
+	if mid, err := d.MachineID(md); err != nil || d.Fields().Decompose(mid)["zone"] != 3 {
+		t.Errorf("expected the region mapping, got %d, %v", mid, err)
+	}
+
+	md.Zone, md.Region = "ap-south-1a", "ap-south-1"
+	if _, err := d.MachineID(md); !errors.Is(err, ErrUnknownZone) {
+		t.Errorf("expected ErrUnknownZone, got %v", err)
+	}
+
+	if _, err := (Derivation{ZoneBits: 10}).MachineID(md); err == nil {
+		t.Error("expected an error when the zone takes all machine bits")
+	}
+	if _, err := (Derivation{ZoneBits: 1, Zones: map[string]uint64{"ap-south-1": 2}}).MachineID(md); !errors.Is(err, snowflake.ErrMachineFieldRange) {
+		t.Errorf("expected ErrMachineFieldRange for an oversized zone value, got %v", err)
+	}
+}
+
+func TestDerivationDistribution(t *testing.T) {
+	d := Derivation{MachineBits: 10, ZoneBits: 3}
+
+	// 64 instances over 128 slots per zone should mostly land apart.
+	seen := make(map[uint64]bool)
+	for i := 0; i < 64; i++ {
+		mid, err := d.MachineID(Metadata{InstanceID: fmt.Sprintf("i-%017x", i), Zone: "eu-west-1a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[mid] = true
+	}
+	if len(seen) < 40 {
+		t.Errorf("only %d distinct ids for 64 instances", len(seen))
+	}
+}
@@ -0,0 +1,150 @@
+// Package snowflakecloud derives snowflake machine IDs from cloud instance
+// metadata, so instances of an autoscaling group get stable IDs without a
+// coordination service.
+package snowflakecloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Metadata identifies a cloud instance.
+type Metadata struct {
+	// Provider is "ec2" or "gce".
+	Provider   string
+	InstanceID string
+	// Zone is the availability zone, e.g. "eu-west-1a" or "europe-west1-b".
+	Zone   string
+	Region string
+}
+
+// MetadataClient reads instance metadata. The zero value uses the standard
+// link-local endpoints with a two second timeout.
+type MetadataClient struct {
+	HTTPClient  *http.Client
+	EC2Endpoint string
+	GCEEndpoint string
+}
+
+const (
+	defaultEC2Endpoint = "http://169.254.169.254"
+	defaultGCEEndpoint = "http://metadata.google.internal"
+)
+
+// EC2 reads the instance ID and placement from the EC2 instance metadata
+// service, using an IMDSv2 session token.
+func (c *MetadataClient) EC2(ctx context.Context) (Metadata, error) {
+	base := c.EC2Endpoint
+	if base == "" {
+		base = defaultEC2Endpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, base+"/latest/api/token", nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := c.do(req)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("ec2 metadata token: %w", err)
+	}
+
+	md := Metadata{Provider: "ec2"}
+	for _, f := range []struct {
+		path string
+		dst  *string
+	}{
+		{"instance-id", &md.InstanceID},
+		{"placement/availability-zone", &md.Zone},
+		{"placement/region", &md.Region},
+	} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/latest/meta-data/"+f.path, nil)
+		if err != nil {
+			return Metadata{}, err
+		}
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+		if *f.dst, err = c.do(req); err != nil {
+			return Metadata{}, fmt.Errorf("ec2 metadata %s: %w", f.path, err)
+		}
+	}
+
+	return md, nil
+}
+
+// GCE reads the instance ID and zone from the GCE metadata server.
+func (c *MetadataClient) GCE(ctx context.Context) (Metadata, error) {
+	base := c.GCEEndpoint
+	if base == "" {
+		base = defaultGCEEndpoint
+	}
+
+	md := Metadata{Provider: "gce"}
+	for _, f := range []struct {
+		path string
+		dst  *string
+	}{
+		{"id", &md.InstanceID},
+		{"zone", &md.Zone},
+	} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/computeMetadata/v1/instance/"+f.path, nil)
+		if err != nil {
+			return Metadata{}, err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+		if *f.dst, err = c.do(req); err != nil {
+			return Metadata{}, fmt.Errorf("gce metadata %s: %w", f.path, err)
+		}
+	}
+
+	// The zone is reported as projects/<number>/zones/<zone>, and the region
+	// is the zone without its last dash-separated part.
+	md.Zone = path.Base(md.Zone)
+	if i := strings.LastIndexByte(md.Zone, '-'); i > 0 {
+		md.Region = md.Zone[:i]
+	}
+
+	return md, nil
+}
+
+// Detect returns the metadata of whichever of EC2 and GCE answers.
+func (c *MetadataClient) Detect(ctx context.Context) (Metadata, error) {
+	md, ec2Err := c.EC2(ctx)
+	if ec2Err == nil {
+		return md, nil
+	}
+	md, gceErr := c.GCE(ctx)
+	if gceErr == nil {
+		return md, nil
+	}
+
+	return Metadata{}, errors.Join(ec2Err, gceErr)
+}
+
+func (c *MetadataClient) do(req *http.Request) (string, error) {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 2 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", req.URL.Path, resp.Status)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,82 @@
+package snowflakecloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEC2(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest/api/token" {
+			if r.Method != http.MethodPut {
+				http.Error(w, "method", http.StatusMethodNotAllowed)
+				return
+			}
+			w.Write([]byte("token"))
+			return
+		}
+		if r.Header.Get("X-aws-ec2-metadata-token") != "token" {
+			http.Error(w, "no token", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/latest/meta-data/instance-id":
+			w.Write([]byte("i-0123456789abcdef0"))
+		case "/latest/meta-data/placement/availability-zone":
+			w.Write([]byte("eu-west-1a"))
+		case "/latest/meta-data/placement/region":
+			w.Write([]byte("eu-west-1\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := &MetadataClient{EC2Endpoint: srv.URL, GCEEndpoint: srv.URL}
+	md, err := c.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Metadata{Provider: "ec2", InstanceID: "i-0123456789abcdef0", Zone: "eu-west-1a", Region: "eu-west-1"}
+	if md != want {
+		t.Errorf("got %+v, want %+v", md, want)
+	}
+}
+
+func TestGCE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing header", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/id":
+			w.Write([]byte("4520031799277581759"))
+		case "/computeMetadata/v1/instance/zone":
+			w.Write([]byte("projects/123456/zones/europe-west1-b"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// The EC2 endpoint answers 404, so Detect falls back to GCE.
+	c := &MetadataClient{EC2Endpoint: srv.URL, GCEEndpoint: srv.URL}
+	md, err := c.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Metadata{Provider: "gce", InstanceID: "4520031799277581759", Zone: "europe-west1-b", Region: "europe-west1"}
+	if md != want {
+		t.Errorf("got %+v, want %+v", md, want)
+	}
+
+	srv.Close()
+	if _, err := c.Detect(context.Background()); err == nil {
+		t.Error("expected an error without a metadata service")
+	}
+}
@@ -0,0 +1,151 @@
+// Package snowflakeconsul leases snowflake machine IDs from Consul.
+package snowflakeconsul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/hashicorp/consul/api"
+)
+
+// Config configures a Provider.
+type Config struct {
+	// Prefix is the KV prefix under which one key per machine ID is locked,
+	// e.g. "service/orders/snowflake".
+	Prefix string
+	// MaxMachineID is the largest ID handed out. Defaults to the largest
+	// machine ID of snowflake.DefaultLayout.
+	MaxMachineID uint64
+	// Checks are health checks of the local node the lease depends on. When
+	// one of them turns critical, Consul invalidates the session and the ID
+	// becomes free for other nodes. The node's serfHealth check is always
+	// included.
+	Checks []string
+	// TTL bounds how long the lease survives the process without renewal.
+	// It is renewed every TTL/2. Defaults to 15 seconds.
+	TTL time.Duration
+	// OnLost, if not nil, is called on its own goroutine when the lease of
+	// machineID is lost, e.g. because a check failed. The generator using
+	// the ID must stop issuing IDs, since another node may now hold it.
+	OnLost func(machineID uint64, err error)
+}
+
+// Provider is a snowflake.MachineIDProvider that leases machine IDs by
+// locking KV keys with Consul sessions bound to the node's health checks.
+type Provider struct {
+	client *api.Client
+	cfg    Config
+
+	mu     sync.Mutex
+	leases map[uint64]*lease
+}
+
+var _ snowflake.MachineIDProvider = (*Provider)(nil)
+
+type lease struct {
+	session string
+	stop    chan struct{}
+}
+
+// New returns a Provider using client.
+func New(client *api.Client, cfg Config) *Provider {
+	if cfg.MaxMachineID == 0 {
+		cfg.MaxMachineID = snowflake.DefaultLayout.MaxMachineID()
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 15 * time.Second
+	}
+
+	return &Provider{client: client, cfg: cfg, leases: make(map[uint64]*lease)}
+}
+
+// Acquire creates a session and locks the key of the lowest machine ID not
+// locked by another session.
+func (p *Provider) Acquire(ctx context.Context) (uint64, error) {
+	host, _ := os.Hostname()
+	wo := (&api.WriteOptions{}).WithContext(ctx)
+
+	session, _, err := p.client.Session().Create(&api.SessionEntry{
+		Name:      "snowflake machine id",
+		Checks:    append([]string{"serfHealth"}, p.cfg.Checks...),
+		Behavior:  api.SessionBehaviorRelease,
+		TTL:       p.cfg.TTL.String(),
+		LockDelay: time.Second,
+	}, wo)
+	if err != nil {
+		return 0, fmt.Errorf("creating consul session: %w", err)
+	}
+
+	value := []byte(host + " " + strconv.Itoa(os.Getpid()))
+	for id := uint64(0); id <= p.cfg.MaxMachineID; id++ {
+		ok, _, err := p.client.KV().Acquire(&api.KVPair{Key: p.key(id), Value: value, Session: session}, wo)
+		if err != nil {
+			p.client.Session().Destroy(session, nil)
+			return 0, fmt.Errorf("locking %s: %w", p.key(id), err)
+		}
+		if !ok {
+			continue
+		}
+
+		l := &lease{session: session, stop: make(chan struct{})}
+		p.mu.Lock()
+		p.leases[id] = l
+		p.mu.Unlock()
+		go p.renew(id, l)
+
+		return id, nil
+	}
+
+	p.client.Session().Destroy(session, nil)
+
+	return 0, fmt.Errorf("all %d machine ids under %s are locked", p.cfg.MaxMachineID+1, p.cfg.Prefix)
+}
+
+// renew keeps the session alive until Release and reports a lost lease.
+// RenewPeriodic destroys the session itself once l.stop is closed.
+func (p *Provider) renew(id uint64, l *lease) {
+	err := p.client.Session().RenewPeriodic(p.cfg.TTL.String(), l.session, nil, l.stop)
+	if err == nil {
+		return
+	}
+
+	p.mu.Lock()
+	held := p.leases[id] == l
+	if held {
+		delete(p.leases, id)
+	}
+	p.mu.Unlock()
+
+	if held && p.cfg.OnLost != nil {
+		p.cfg.OnLost(id, err)
+	}
+}
+
+// Release unlocks the key of machineID and destroys its session.
+func (p *Provider) Release(ctx context.Context, machineID uint64) error {
+	p.mu.Lock()
+	l, ok := p.leases[machineID]
+	delete(p.leases, machineID)
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("machine id %d not held", machineID)
+	}
+	close(l.stop)
+
+	wo := (&api.WriteOptions{}).WithContext(ctx)
+	_, _, err := p.client.KV().Release(&api.KVPair{Key: p.key(machineID), Session: l.session}, wo)
+	_, derr := p.client.Session().Destroy(l.session, wo)
+
+	return errors.Join(err, derr)
+}
+
+func (p *Provider) key(id uint64) string {
+	return fmt.Sprintf("%s/%d", p.cfg.Prefix, id)
+}
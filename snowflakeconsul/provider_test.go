@@ -0,0 +1,164 @@
+package snowflakeconsul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// fakeConsul implements the session and KV lock endpoints the provider uses.
+type fakeConsul struct {
+	mu       sync.Mutex
+	next     int
+	sessions map[string][]string // session -> checks
+	locks    map[string]string   // key -> session
+}
+
+func newFakeConsul(t *testing.T) (*fakeConsul, *api.Client) {
+	f := &fakeConsul{sessions: make(map[string][]string), locks: make(map[string]string)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	client, err := api.NewClient(&api.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f, client
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := r.URL.Path
+	switch {
+	case path == "/v1/session/create":
+		var entry api.SessionEntry
+		json.NewDecoder(r.Body).Decode(&entry)
+		f.next++
+		id := "session-" + strconv.Itoa(f.next)
+		f.sessions[id] = entry.Checks
+		json.NewEncoder(w).Encode(map[string]string{"ID": id})
+	case strings.HasPrefix(path, "/v1/session/renew/"):
+		id := strings.TrimPrefix(path, "/v1/session/renew/")
+		if _, ok := f.sessions[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode([]api.SessionEntry{{ID: id, TTL: "1s"}})
+	case strings.HasPrefix(path, "/v1/session/destroy/"):
+		f.invalidate(strings.TrimPrefix(path, "/v1/session/destroy/"))
+		w.Write([]byte("true"))
+	case strings.HasPrefix(path, "/v1/kv/"):
+		key := strings.TrimPrefix(path, "/v1/kv/")
+		ok := false
+		if s := r.URL.Query().Get("acquire"); s != "" {
+			holder, held := f.locks[key]
+			if _, live := f.sessions[s]; live && (!held || holder == s) {
+				f.locks[key], ok = s, true
+			}
+		} else if s := r.URL.Query().Get("release"); s != "" && f.locks[key] == s {
+			delete(f.locks, key)
+			ok = true
+		}
+		json.NewEncoder(w).Encode(ok)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// invalidate drops a session and its locks, as Consul does when one of the
+// session's checks turns critical.
+func (f *fakeConsul) invalidate(session string) {
+	delete(f.sessions, session)
+	for k, s := range f.locks {
+		if s == session {
+			delete(f.locks, k)
+		}
+	}
+}
+
+func (f *fakeConsul) holder(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.locks[key]
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeConsul(t)
+
+	a := New(client, Config{Prefix: "snowflake", MaxMachineID: 1, Checks: []string{"service:orders"}})
+	b := New(client, Config{Prefix: "snowflake", MaxMachineID: 1})
+
+	if id, err := a.Acquire(ctx); err != nil || id != 0 {
+		t.Fatalf("first Acquire = %d, %v", id, err)
+	}
+	if id, err := b.Acquire(ctx); err != nil || id != 1 {
+		t.Fatalf("second Acquire = %d, %v", id, err)
+	}
+	if _, err := b.Acquire(ctx); err == nil {
+		t.Fatal("expected all ids to be taken")
+	}
+
+	f.mu.Lock()
+	checks := f.sessions[f.locks["snowflake/0"]]
+	f.mu.Unlock()
+	if strings.Join(checks, ",") != "serfHealth,service:orders" {
+		t.Errorf("session bound to checks %v", checks)
+	}
+
+	if err := a.Release(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if f.holder("snowflake/0") != "" {
+		t.Error("released id is still locked")
+	}
+	if err := a.Release(ctx, 0); err == nil {
+		t.Error("releasing an id twice should fail")
+	}
+	if id, err := b.Acquire(ctx); err != nil || id != 0 {
+		t.Errorf("expected the released id 0, got %d, %v", id, err)
+	}
+}
+
+func TestProviderLost(t *testing.T) {
+	f, client := newFakeConsul(t)
+
+	lost := make(chan uint64, 1)
+	p := New(client, Config{Prefix: "snowflake", TTL: 100 * time.Millisecond, OnLost: func(id uint64, err error) {
+		lost <- id
+	}})
+
+	id, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.mu.Lock()
+	f.invalidate(f.locks["snowflake/0"])
+	f.mu.Unlock()
+
+	select {
+	case got := <-lost:
+		if got != id {
+			t.Errorf("lost id %d, want %d", got, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("lost lease was not reported")
+	}
+
+	if err := p.Release(context.Background(), id); err == nil {
+		t.Error("releasing a lost id should fail")
+	}
+}
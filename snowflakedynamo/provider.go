@@ -0,0 +1,276 @@
+// Package snowflakedynamo leases snowflake machine IDs from a DynamoDB table.
+package snowflakedynamo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+// API is the subset of *dynamodb.Client used by Provider.
+type API interface {
+	PutItem(ctx context.Context, in *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, in *dynamodb.UpdateItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, in *dynamodb.DeleteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// Config configures a Provider.
+//
+// The table needs a string partition key named "pk". Enabling DynamoDB TTL
+// on the "expires" attribute removes the items of crashed processes, but is
+// not required: expired items are claimed over regardless.
+type Config struct {
+	Table string
+	// Namespace separates the IDs of different services sharing the table.
+	Namespace string
+	// MaxMachineID is the largest ID handed out. Defaults to the largest
+	// machine ID of snowflake.DefaultLayout.
+	MaxMachineID uint64
+	// TTL is how long a claim survives without a heartbeat. Heartbeats are
+	// written every TTL/3. Defaults to 30 seconds.
+	TTL time.Duration
+	// Attempts is the number of passes over all IDs Acquire makes before
+	// giving up. Passes are separated by exponential backoff with full
+	// jitter, starting at BaseBackoff and capped at MaxBackoff. Default to
+	// 5, 100ms and 5s.
+	Attempts    int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// OnLost, if not nil, is called on its own goroutine when the claim on
+	// machineID is lost: another owner took it over, or no heartbeat
+	// succeeded within TTL. The generator using the ID must stop issuing
+	// IDs.
+	OnLost func(machineID uint64, err error)
+}
+
+// Provider is a snowflake.MachineIDProvider claiming machine IDs with
+// conditional writes, for environments such as ECS or Lambda without a
+// coordination service.
+type Provider struct {
+	api   API
+	cfg   Config
+	owner string
+
+	mu     sync.Mutex
+	claims map[uint64]chan struct{}
+}
+
+var _ snowflake.MachineIDProvider = (*Provider)(nil)
+
+var errNotOwner = errors.New("claim taken over by another owner")
+
+// New returns a Provider writing to cfg.Table through api.
+func New(api API, cfg Config) *Provider {
+	if cfg.MaxMachineID == 0 {
+		cfg.MaxMachineID = snowflake.DefaultLayout.MaxMachineID()
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Second
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = 5
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 100 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Second
+	}
+
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s/%d/%016x", host, os.Getpid(), rand.Uint64())
+
+	return &Provider{api: api, cfg: cfg, owner: owner, claims: make(map[uint64]chan struct{})}
+}
+
+// Acquire claims a machine ID that is free or whose claim has expired.
+// Each pass starts at a random ID so that tasks starting together spread
+// out instead of all contending for the lowest IDs.
+func (p *Provider) Acquire(ctx context.Context) (uint64, error) {
+	n := p.cfg.MaxMachineID + 1
+
+	for attempt := 0; attempt < p.cfg.Attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, p.backoff(attempt)); err != nil {
+				return 0, err
+			}
+		}
+
+		start := rand.Uint64N(n)
+		for i := uint64(0); i < n; i++ {
+			id := (start + i) % n
+
+			p.mu.Lock()
+			_, held := p.claims[id]
+			p.mu.Unlock()
+			if held {
+				continue
+			}
+
+			err := p.claim(ctx, id)
+			if isConditionFailed(err) {
+				continue
+			}
+			if err != nil {
+				return 0, fmt.Errorf("claiming machine id %d: %w", id, err)
+			}
+
+			stop := make(chan struct{})
+			p.mu.Lock()
+			p.claims[id] = stop
+			p.mu.Unlock()
+			go p.heartbeat(id, stop)
+
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free machine id in %s/%s after %d attempts", p.cfg.Table, p.cfg.Namespace, p.cfg.Attempts)
+}
+
+func (p *Provider) claim(ctx context.Context, id uint64) error {
+	now := time.Now()
+	_, err := p.api.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(p.cfg.Table),
+		Item: map[string]types.AttributeValue{
+			"pk":      p.key(id),
+			"owner":   &types.AttributeValueMemberS{Value: p.owner},
+			"expires": unix(now.Add(p.cfg.TTL)),
+		},
+		ConditionExpression:       aws.String("attribute_not_exists(pk) OR expires < :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":now": unix(now)},
+	})
+
+	return err
+}
+
+// heartbeat extends the claim every TTL/3 until stop is closed.
+func (p *Provider) heartbeat(id uint64, stop chan struct{}) {
+	ticker := time.NewTicker(p.cfg.TTL / 3)
+	defer ticker.Stop()
+
+	valid := time.Now().Add(p.cfg.TTL)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), p.cfg.TTL/3)
+		_, err := p.api.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                aws.String(p.cfg.Table),
+			Key:                      map[string]types.AttributeValue{"pk": p.key(id)},
+			UpdateExpression:         aws.String("SET expires = :expires"),
+			ConditionExpression:      aws.String("#owner = :owner"),
+			ExpressionAttributeNames: map[string]string{"#owner": "owner"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":owner":   &types.AttributeValueMemberS{Value: p.owner},
+				":expires": unix(now.Add(p.cfg.TTL)),
+			},
+		})
+		cancel()
+
+		switch {
+		case err == nil:
+			valid = now.Add(p.cfg.TTL)
+			continue
+		case isConditionFailed(err):
+			err = errNotOwner
+		case now.Before(valid):
+			// Transient failure; the claim is still ours until it expires.
+			continue
+		}
+
+		if p.drop(id, stop) && p.cfg.OnLost != nil {
+			p.cfg.OnLost(id, err)
+		}
+		return
+	}
+}
+
+// Release deletes the claim on machineID.
+func (p *Provider) Release(ctx context.Context, machineID uint64) error {
+	p.mu.Lock()
+	stop, ok := p.claims[machineID]
+	p.mu.Unlock()
+	if !ok || !p.drop(machineID, stop) {
+		return fmt.Errorf("machine id %d not held", machineID)
+	}
+
+	_, err := p.api.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(p.cfg.Table),
+		Key:                       map[string]types.AttributeValue{"pk": p.key(machineID)},
+		ConditionExpression:       aws.String("#owner = :owner"),
+		ExpressionAttributeNames:  map[string]string{"#owner": "owner"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":owner": &types.AttributeValueMemberS{Value: p.owner}},
+	})
+	if isConditionFailed(err) {
+		return fmt.Errorf("releasing machine id %d: %w", machineID, errNotOwner)
+	}
+
+	return err
+}
+
+// drop forgets the claim and stops its heartbeat, reporting whether stop
+// was still the current claim.
+func (p *Provider) drop(id uint64, stop chan struct{}) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.claims[id] != stop {
+		return false
+	}
+	delete(p.claims, id)
+	close(stop)
+
+	return true
+}
+
+// backoff returns a random duration up to BaseBackoff*2^attempt, capped at
+// MaxBackoff.
+func (p *Provider) backoff(attempt int) time.Duration {
+	d := p.cfg.MaxBackoff
+	if attempt < 32 && p.cfg.BaseBackoff<<attempt < d && p.cfg.BaseBackoff<<attempt > 0 {
+		d = p.cfg.BaseBackoff << attempt
+	}
+
+	return rand.N(d) + 1
+}
+
+func (p *Provider) key(id uint64) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: p.cfg.Namespace + "#" + strconv.FormatUint(id, 10)}
+}
+
+func unix(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.Unix(), 10)}
+}
+
+func isConditionFailed(err error) bool {
+	var cf *types.ConditionalCheckFailedException
+
+	return errors.As(err, &cf)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,197 @@
+package snowflakedynamo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type item struct {
+	owner   string
+	expires int64
+}
+
+// fakeTable evaluates the three conditional writes Provider issues.
+type fakeTable struct {
+	mu    sync.Mutex
+	items map[string]item
+	puts  int
+}
+
+func newFakeTable() *fakeTable {
+	return &fakeTable{items: make(map[string]item)}
+}
+
+func str(av types.AttributeValue) string {
+	return av.(*types.AttributeValueMemberS).Value
+}
+
+func num(av types.AttributeValue) int64 {
+	n, _ := strconv.ParseInt(av.(*types.AttributeValueMemberN).Value, 10, 64)
+	return n
+}
+
+var errCondition = &types.ConditionalCheckFailedException{Message: aws.String("condition failed")}
+
+func (f *fakeTable) PutItem(ctx context.Context, in *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.puts++
+	pk := str(in.Item["pk"])
+	if cur, ok := f.items[pk]; ok && cur.expires >= num(in.ExpressionAttributeValues[":now"]) {
+		return nil, errCondition
+	}
+	f.items[pk] = item{owner: str(in.Item["owner"]), expires: num(in.Item["expires"])}
+
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeTable) UpdateItem(ctx context.Context, in *dynamodb.UpdateItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pk := str(in.Key["pk"])
+	cur, ok := f.items[pk]
+	if !ok || cur.owner != str(in.ExpressionAttributeValues[":owner"]) {
+		return nil, errCondition
+	}
+	cur.expires = num(in.ExpressionAttributeValues[":expires"])
+	f.items[pk] = cur
+
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (f *fakeTable) DeleteItem(ctx context.Context, in *dynamodb.DeleteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pk := str(in.Key["pk"])
+	if cur, ok := f.items[pk]; !ok || cur.owner != str(in.ExpressionAttributeValues[":owner"]) {
+		return nil, errCondition
+	}
+	delete(f.items, pk)
+
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (f *fakeTable) set(pk string, it item) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.items[pk] = it
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	table := newFakeTable()
+	cfg := Config{Table: "ids", Namespace: "orders", MaxMachineID: 3, Attempts: 2, BaseBackoff: time.Millisecond}
+
+	a, b := New(table, cfg), New(table, cfg)
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 4; i++ {
+		p := a
+		if i%2 == 1 {
+			p = b
+		}
+		id, err := p.Acquire(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("machine id %d handed out twice", id)
+		}
+		seen[id] = true
+	}
+
+	table.mu.Lock()
+	table.puts = 0
+	table.mu.Unlock()
+	if _, err := b.Acquire(ctx); err == nil {
+		t.Fatal("expected all ids to be taken")
+	}
+	// b skips the two ids it holds itself.
+	if table.puts != 4 {
+		t.Errorf("expected 2 passes over 2 ids, got %d writes", table.puts)
+	}
+
+	var held uint64
+	for id := range a.claims {
+		held = id
+	}
+	if err := a.Release(ctx, held); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Release(ctx, held); err == nil {
+		t.Error("releasing an id twice should fail")
+	}
+	if id, err := b.Acquire(ctx); err != nil || id != held {
+		t.Errorf("expected the released id %d, got %d, %v", held, id, err)
+	}
+}
+
+func TestProviderExpired(t *testing.T) {
+	table := newFakeTable()
+	p := New(table, Config{Table: "ids", Namespace: "orders", MaxMachineID: 1, Attempts: 1})
+
+	// A crashed task's claim is taken over once it has expired.
+	table.set("orders#0", item{owner: "crashed", expires: time.Now().Add(-time.Minute).Unix()})
+	table.set("orders#1", item{owner: "alive", expires: time.Now().Add(time.Minute).Unix()})
+
+	if id, err := p.Acquire(context.Background()); err != nil || id != 0 {
+		t.Fatalf("expected to take over the expired id 0, got %d, %v", id, err)
+	}
+}
+
+func TestProviderLost(t *testing.T) {
+	table := newFakeTable()
+
+	lost := make(chan error, 1)
+	p := New(table, Config{Table: "ids", Namespace: "orders", MaxMachineID: 1, TTL: 30 * time.Millisecond,
+		OnLost: func(id uint64, err error) { lost <- err }})
+
+	id, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table.set("orders#"+strconv.FormatUint(id, 10), item{owner: "thief", expires: time.Now().Add(time.Hour).Unix()})
+
+	select {
+	case err := <-lost:
+		if !errors.Is(err, errNotOwner) {
+			t.Errorf("unexpected loss reason %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("lost claim was not reported")
+	}
+
+	if err := p.Release(context.Background(), id); err == nil {
+		t.Error("releasing a lost id should fail")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := New(newFakeTable(), Config{BaseBackoff: 10 * time.Millisecond, MaxBackoff: time.Second})
+
+	for attempt, max := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond} {
+		for range 100 {
+			if d := p.backoff(attempt); d <= 0 || d > max {
+				t.Fatalf("backoff(%d) = %s, want (0, %s]", attempt, d, max)
+			}
+		}
+	}
+	for _, attempt := range []int{10, 40, 100} {
+		if d := p.backoff(attempt); d > time.Second {
+			t.Errorf("backoff(%d) = %s exceeds the cap", attempt, d)
+		}
+	}
+}
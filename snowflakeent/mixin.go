@@ -0,0 +1,89 @@
+// Package snowflakeent fills in ent IDs from a snowflake generator.
+package snowflakeent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+var ErrUnsupportedMutation = errors.New("mutation has no integer ID setter")
+
+// Mixin declares an immutable uint64 id field and sets it from a generator
+// when a node is created without one:
+//
+//	func (User) Mixin() []ent.Mixin {
+//		return []ent.Mixin{snowflakeent.Mixin{Source: ids.Generator}}
+//	}
+//
+// Generated code evaluates schema hooks when the ent runtime package is
+// initialized, usually before the generator exists, so Source is called
+// on every create instead.
+type Mixin struct {
+	mixin.Schema
+	Source func() *snowflake.Snowflake
+}
+
+func (m Mixin) Fields() []ent.Field {
+	return []ent.Field{field.Uint64("id").Immutable()}
+}
+
+func (m Mixin) Hooks() []ent.Hook {
+	return []ent.Hook{Hook(m.Source)}
+}
+
+// Hook returns a hook setting the ID of created nodes that have none, for
+// schemas declaring their own uint64 or int64 id field. Int64 IDs are
+// issued with NextID64.
+func Hook(source func() *snowflake.Snowflake) ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			if !m.Op().Is(ent.OpCreate) {
+				return next.Mutate(ctx, m)
+			}
+			if err := setID(source(), m); err != nil {
+				return nil, err
+			}
+
+			return next.Mutate(ctx, m)
+		})
+	}
+}
+
+func setID(gen *snowflake.Snowflake, m ent.Mutation) error {
+	switch m := m.(type) {
+	case interface {
+		ID() (uint64, bool)
+		SetID(uint64)
+	}:
+		if _, ok := m.ID(); ok {
+			return nil
+		}
+		id, err := gen.NextID()
+		if err != nil {
+			return err
+		}
+		m.SetID(id)
+	case interface {
+		ID() (int64, bool)
+		SetID(int64)
+	}:
+		if _, ok := m.ID(); ok {
+			return nil
+		}
+		id, err := gen.NextID64()
+		if err != nil {
+			return err
+		}
+		m.SetID(id)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedMutation, m.Type())
+	}
+
+	return nil
+}
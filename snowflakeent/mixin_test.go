@@ -0,0 +1,70 @@
+package snowflakeent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"entgo.io/ent"
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+// mutation stands in for a generated mutation with a uint64 or int64 ID.
+type mutation[T uint64 | int64] struct {
+	ent.Mutation
+	op  ent.Op
+	id  T
+	set bool
+}
+
+func (m *mutation[T]) Op() ent.Op    { return m.op }
+func (m *mutation[T]) Type() string  { return "User" }
+func (m *mutation[T]) ID() (T, bool) { return m.id, m.set }
+func (m *mutation[T]) SetID(id T)    { m.id, m.set = id, true }
+
+type untyped struct{ ent.Mutation }
+
+func (untyped) Op() ent.Op   { return ent.OpCreate }
+func (untyped) Type() string { return "Pet" }
+
+func TestHook(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := snowflake.NewSnowflake(epoch, 7, snowflake.WithClock(clocktest.NewFakeClock(epoch.Add(time.Hour))))
+
+	var mutated int
+	mutator := Mixin{Source: func() *snowflake.Snowflake { return gen }}.Hooks()[0](
+		ent.MutateFunc(func(context.Context, ent.Mutation) (ent.Value, error) {
+			mutated++
+			return nil, nil
+		}))
+	ctx := context.Background()
+
+	created := &mutation[uint64]{op: ent.OpCreate}
+	if _, err := mutator.Mutate(ctx, created); err != nil {
+		t.Fatal(err)
+	}
+	if !created.set || gen.Decompose(created.id).MachineID != 7 {
+		t.Errorf("unexpected ID %d", created.id)
+	}
+
+	kept := &mutation[uint64]{op: ent.OpCreate, id: 42, set: true}
+	signed := &mutation[int64]{op: ent.OpCreate}
+	updated := &mutation[uint64]{op: ent.OpUpdateOne}
+	for _, m := range []ent.Mutation{kept, signed, updated} {
+		if _, err := mutator.Mutate(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if kept.id != 42 || signed.id <= 0 || updated.set {
+		t.Errorf("unexpected IDs %d, %d, %v", kept.id, signed.id, updated.set)
+	}
+
+	if _, err := mutator.Mutate(ctx, untyped{}); !errors.Is(err, ErrUnsupportedMutation) {
+		t.Errorf("expected ErrUnsupportedMutation, got %v", err)
+	}
+	if mutated != 4 {
+		t.Errorf("next mutator called %d times, want 4", mutated)
+	}
+}
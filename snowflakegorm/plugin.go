@@ -0,0 +1,97 @@
+// Package snowflakegorm fills in primary keys from a snowflake generator
+// when GORM creates records.
+package snowflakegorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var ErrUnsupportedField = errors.New("primary key is not an integer")
+
+// Plugin is a GORM plugin setting the primary key of every created record
+// that does not have one yet:
+//
+//	db.Use(&snowflakegorm.Plugin{Generator: gen})
+//
+// Models without a primary key are left alone; models whose primary key is
+// not an integer type fail to create.
+type Plugin struct {
+	Generator *snowflake.Snowflake
+	// Field names the field to fill, by Go or column name. Defaults to the
+	// model's primary key.
+	Field string
+}
+
+func (p *Plugin) Name() string {
+	return "snowflake"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	if p.Generator == nil {
+		return errors.New("snowflake plugin needs a generator")
+	}
+
+	return db.Callback().Create().Before("gorm:create").Register("snowflake:assign_id", p.assign)
+}
+
+func (p *Plugin) assign(db *gorm.DB) {
+	s := db.Statement.Schema
+	if s == nil {
+		return
+	}
+	field := s.PrioritizedPrimaryField
+	if p.Field != "" {
+		field = s.LookUpField(p.Field)
+	}
+	if field == nil {
+		return
+	}
+	switch field.FieldType.Kind() {
+	case reflect.Int64, reflect.Uint64:
+	default:
+		db.AddError(fmt.Errorf("%w: %s.%s is %s", ErrUnsupportedField, s.Name, field.Name, field.FieldType))
+		return
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := p.set(db, field, reflect.Indirect(rv.Index(i))); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := p.set(db, field, rv); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// set gives the record rv an ID unless it has one.
+func (p *Plugin) set(db *gorm.DB, field *schema.Field, rv reflect.Value) error {
+	if _, zero := field.ValueOf(db.Statement.Context, rv); !zero {
+		return nil
+	}
+	var (
+		id  any
+		err error
+	)
+	if field.FieldType.Kind() == reflect.Int64 {
+		id, err = p.Generator.NextID64()
+	} else {
+		id, err = p.Generator.NextID()
+	}
+	if err != nil {
+		return err
+	}
+
+	return field.Set(db.Statement.Context, rv, id)
+}
@@ -0,0 +1,71 @@
+package snowflakegorm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/fethican/snowflake-go/clocktest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type order struct {
+	ID    uint64
+	Total int
+}
+
+type signedOrder struct {
+	ID int64
+}
+
+type namedOrder struct {
+	Code string `gorm:"primaryKey"`
+}
+
+func openDB(t *testing.T) (*gorm.DB, *snowflake.Snowflake) {
+	t.Helper()
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := snowflake.NewSnowflake(epoch, 7, snowflake.WithClock(clocktest.NewFakeClock(epoch.Add(time.Hour))))
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(&Plugin{Generator: gen}); err != nil {
+		t.Fatal(err)
+	}
+
+	return db, gen
+}
+
+func TestPlugin(t *testing.T) {
+	db, gen := openDB(t)
+
+	o := order{Total: 3}
+	if err := db.Create(&o).Error; err != nil {
+		t.Fatal(err)
+	}
+	if gen.Decompose(o.ID).MachineID != 7 {
+		t.Errorf("unexpected ID %d", o.ID)
+	}
+
+	kept := order{ID: 42}
+	if err := db.Create(&kept).Error; err != nil || kept.ID != 42 {
+		t.Errorf("existing ID overwritten: %d, %v", kept.ID, err)
+	}
+
+	batch := []*signedOrder{{}, {}, {ID: 5}}
+	if err := db.Create(batch).Error; err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].ID <= 0 || batch[1].ID <= batch[0].ID || batch[2].ID != 5 {
+		t.Errorf("unexpected batch IDs %d, %d, %d", batch[0].ID, batch[1].ID, batch[2].ID)
+	}
+
+	err := db.Create(&namedOrder{}).Error
+	if !errors.Is(err, ErrUnsupportedField) {
+		t.Errorf("expected ErrUnsupportedField, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package snowflakegrpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"google.golang.org/grpc"
+)
+
+// Client fetches IDs from an IDService in batches and hands them out from a
+// local cache. Cached IDs were issued when the batch was fetched, so their
+// embedded time may lag behind the time they are handed out.
+type Client struct {
+	IDServiceClient
+
+	batchSize uint32
+
+	mu     sync.Mutex
+	cache  []uint64
+	closed atomic.Bool
+}
+
+// NewClient returns a Client fetching batchSize IDs per round trip.
+func NewClient(cc grpc.ClientConnInterface, batchSize uint32) *Client {
+	if batchSize == 0 {
+		batchSize = 1
+	}
+
+	return &Client{IDServiceClient: NewIDServiceClient(cc), batchSize: batchSize}
+}
+
+// NextID returns the next cached ID, fetching a new batch when the cache is empty.
+func (c *Client) NextID(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed.Load() {
+		return 0, snowflake.ErrClosed
+	}
+	if len(c.cache) == 0 {
+		resp, err := c.GetIDBatch(ctx, &GetIDBatchRequest{Count: c.batchSize})
+		if err != nil {
+			return 0, err
+		}
+		c.cache = resp.GetIds()
+	}
+
+	id := c.cache[0]
+	c.cache = c.cache[1:]
+
+	return id, nil
+}
+
+// Close drops the cached IDs; afterwards NextID and ReserveBlock return
+// snowflake.ErrClosed. It does not close the underlying connection.
+func (c *Client) Close() error {
+	if c.closed.Swap(true) {
+		return snowflake.ErrClosed
+	}
+
+	c.mu.Lock()
+	c.cache = nil
+	c.mu.Unlock()
+
+	return nil
+}
+
+// ReserveBlock leases a block of n sequence numbers from the server.
+func (c *Client) ReserveBlock(ctx context.Context, n uint32) (snowflake.Block, error) {
+	if c.closed.Load() {
+		return snowflake.Block{}, snowflake.ErrClosed
+	}
+
+	resp, err := c.ReserveSequenceBlock(ctx, &ReserveSequenceBlockRequest{Count: n})
+	if err != nil {
+		return snowflake.Block{}, err
+	}
+
+	layout := snowflake.Layout{
+		TimeBits:     uint(resp.GetTimeBits()),
+		TagBits:      uint(resp.GetTagBits()),
+		MachineBits:  uint(resp.GetMachineBits()),
+		SequenceBits: uint(resp.GetSequenceBits()),
+		LittleEndian: resp.GetLittleEndian(),
+	}
+	if order := resp.GetFieldOrder(); len(order) > 0 {
+		if len(order) != len(layout.Order) {
+			return snowflake.Block{}, fmt.Errorf("%w: field order %v", snowflake.ErrInvalidLayout, order)
+		}
+		for i, f := range order {
+			layout.Order[i] = snowflake.Field(f)
+		}
+	}
+	if err := layout.Validate(); err != nil {
+		return snowflake.Block{}, err
+	}
+
+	return snowflake.Block{
+		Layout:        layout,
+		Timestamp:     resp.GetTimestamp(),
+		MachineID:     resp.GetMachineId(),
+		FirstSequence: uint16(resp.GetFirstSequence()),
+		LastSequence:  uint16(resp.GetLastSequence()),
+	}, nil
+}
+
+// LeaseClient mints IDs locally from sequence blocks leased from the server,
+// needing one round trip per block instead of one per ID. As with Client,
+// the embedded time is the time the block was leased.
+type LeaseClient struct {
+	*Client
+
+	mu        sync.Mutex
+	block     snowflake.Block
+	remaining int
+}
+
+// NewLeaseClient returns a LeaseClient leasing blockSize IDs per round trip.
+func NewLeaseClient(cc grpc.ClientConnInterface, blockSize uint32) *LeaseClient {
+	return &LeaseClient{Client: NewClient(cc, blockSize)}
+}
+
+// NextID mints the next ID of the current block, leasing a new block when
+// it is used up.
+func (c *LeaseClient) NextID(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed.Load() {
+		return 0, snowflake.ErrClosed
+	}
+	if c.remaining == 0 {
+		b, err := c.ReserveBlock(ctx, c.batchSize)
+		if err != nil {
+			return 0, err
+		}
+		c.block, c.remaining = b, b.Len()
+	}
+
+	id := c.block.ID(c.block.Len() - c.remaining)
+	c.remaining--
+
+	return id, nil
+}
@@ -0,0 +1,5 @@
+// Package snowflakegrpc exposes a snowflake generator as a gRPC IDService
+// and provides a client that caches batches of IDs locally.
+package snowflakegrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative idservice.proto
@@ -0,0 +1,589 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: idservice.proto
+
+package snowflakegrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIDRequest) Reset() {
+	*x = GetIDRequest{}
+	mi := &file_idservice_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIDRequest) ProtoMessage() {}
+
+func (x *GetIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIDRequest.ProtoReflect.Descriptor instead.
+func (*GetIDRequest) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{0}
+}
+
+type GetIDResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIDResponse) Reset() {
+	*x = GetIDResponse{}
+	mi := &file_idservice_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIDResponse) ProtoMessage() {}
+
+func (x *GetIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIDResponse.ProtoReflect.Descriptor instead.
+func (*GetIDResponse) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetIDResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetIDBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIDBatchRequest) Reset() {
+	*x = GetIDBatchRequest{}
+	mi := &file_idservice_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIDBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIDBatchRequest) ProtoMessage() {}
+
+func (x *GetIDBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIDBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetIDBatchRequest) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetIDBatchRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetIDBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIDBatchResponse) Reset() {
+	*x = GetIDBatchResponse{}
+	mi := &file_idservice_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIDBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIDBatchResponse) ProtoMessage() {}
+
+func (x *GetIDBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIDBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetIDBatchResponse) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetIDBatchResponse) GetIds() []uint64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type DecomposeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecomposeRequest) Reset() {
+	*x = DecomposeRequest{}
+	mi := &file_idservice_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecomposeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecomposeRequest) ProtoMessage() {}
+
+func (x *DecomposeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecomposeRequest.ProtoReflect.Descriptor instead.
+func (*DecomposeRequest) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DecomposeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DecomposeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Timestamp     uint64                 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MachineId     uint64                 `protobuf:"varint,4,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Tag           uint64                 `protobuf:"varint,6,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecomposeResponse) Reset() {
+	*x = DecomposeResponse{}
+	mi := &file_idservice_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecomposeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecomposeResponse) ProtoMessage() {}
+
+func (x *DecomposeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecomposeResponse.ProtoReflect.Descriptor instead.
+func (*DecomposeResponse) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DecomposeResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *DecomposeResponse) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetMachineId() uint64 {
+	if x != nil {
+		return x.MachineId
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetTag() uint64 {
+	if x != nil {
+		return x.Tag
+	}
+	return 0
+}
+
+type ReserveSequenceBlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveSequenceBlockRequest) Reset() {
+	*x = ReserveSequenceBlockRequest{}
+	mi := &file_idservice_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveSequenceBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveSequenceBlockRequest) ProtoMessage() {}
+
+func (x *ReserveSequenceBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveSequenceBlockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveSequenceBlockRequest) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReserveSequenceBlockRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ReserveSequenceBlockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MachineId     uint64                 `protobuf:"varint,2,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	FirstSequence uint32                 `protobuf:"varint,3,opt,name=first_sequence,json=firstSequence,proto3" json:"first_sequence,omitempty"`
+	LastSequence  uint32                 `protobuf:"varint,4,opt,name=last_sequence,json=lastSequence,proto3" json:"last_sequence,omitempty"`
+	TimeBits      uint32                 `protobuf:"varint,5,opt,name=time_bits,json=timeBits,proto3" json:"time_bits,omitempty"`
+	MachineBits   uint32                 `protobuf:"varint,6,opt,name=machine_bits,json=machineBits,proto3" json:"machine_bits,omitempty"`
+	SequenceBits  uint32                 `protobuf:"varint,7,opt,name=sequence_bits,json=sequenceBits,proto3" json:"sequence_bits,omitempty"`
+	TagBits       uint32                 `protobuf:"varint,8,opt,name=tag_bits,json=tagBits,proto3" json:"tag_bits,omitempty"`
+	FieldOrder    []uint32               `protobuf:"varint,9,rep,packed,name=field_order,json=fieldOrder,proto3" json:"field_order,omitempty"`
+	LittleEndian  bool                   `protobuf:"varint,10,opt,name=little_endian,json=littleEndian,proto3" json:"little_endian,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveSequenceBlockResponse) Reset() {
+	*x = ReserveSequenceBlockResponse{}
+	mi := &file_idservice_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveSequenceBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveSequenceBlockResponse) ProtoMessage() {}
+
+func (x *ReserveSequenceBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idservice_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveSequenceBlockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveSequenceBlockResponse) Descriptor() ([]byte, []int) {
+	return file_idservice_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReserveSequenceBlockResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetMachineId() uint64 {
+	if x != nil {
+		return x.MachineId
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetFirstSequence() uint32 {
+	if x != nil {
+		return x.FirstSequence
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetLastSequence() uint32 {
+	if x != nil {
+		return x.LastSequence
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetTimeBits() uint32 {
+	if x != nil {
+		return x.TimeBits
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetMachineBits() uint32 {
+	if x != nil {
+		return x.MachineBits
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetSequenceBits() uint32 {
+	if x != nil {
+		return x.SequenceBits
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetTagBits() uint32 {
+	if x != nil {
+		return x.TagBits
+	}
+	return 0
+}
+
+func (x *ReserveSequenceBlockResponse) GetFieldOrder() []uint32 {
+	if x != nil {
+		return x.FieldOrder
+	}
+	return nil
+}
+
+func (x *ReserveSequenceBlockResponse) GetLittleEndian() bool {
+	if x != nil {
+		return x.LittleEndian
+	}
+	return false
+}
+
+var File_idservice_proto protoreflect.FileDescriptor
+
+const file_idservice_proto_rawDesc = "" +
+	"\n" +
+	"\x0fidservice.proto\x12\fsnowflake.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x0e\n" +
+	"\fGetIDRequest\"\x1f\n" +
+	"\rGetIDResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\")\n" +
+	"\x11GetIDBatchRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\"&\n" +
+	"\x12GetIDBatchResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x04R\x03ids\"\"\n" +
+	"\x10DecomposeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xbe\x01\n" +
+	"\x11DecomposeResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x04R\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x04 \x01(\x04R\tmachineId\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\x04R\bsequence\x12\x10\n" +
+	"\x03tag\x18\x06 \x01(\x04R\x03tag\"3\n" +
+	"\x1bReserveSequenceBlockRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\"\xed\x02\n" +
+	"\x1cReserveSequenceBlockResponse\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x02 \x01(\x04R\tmachineId\x12%\n" +
+	"\x0efirst_sequence\x18\x03 \x01(\rR\rfirstSequence\x12#\n" +
+	"\rlast_sequence\x18\x04 \x01(\rR\flastSequence\x12\x1b\n" +
+	"\ttime_bits\x18\x05 \x01(\rR\btimeBits\x12!\n" +
+	"\fmachine_bits\x18\x06 \x01(\rR\vmachineBits\x12#\n" +
+	"\rsequence_bits\x18\a \x01(\rR\fsequenceBits\x12\x19\n" +
+	"\btag_bits\x18\b \x01(\rR\atagBits\x12\x1f\n" +
+	"\vfield_order\x18\t \x03(\rR\n" +
+	"fieldOrder\x12#\n" +
+	"\rlittle_endian\x18\n" +
+	" \x01(\bR\flittleEndian2\xdb\x02\n" +
+	"\tIDService\x12@\n" +
+	"\x05GetID\x12\x1a.snowflake.v1.GetIDRequest\x1a\x1b.snowflake.v1.GetIDResponse\x12O\n" +
+	"\n" +
+	"GetIDBatch\x12\x1f.snowflake.v1.GetIDBatchRequest\x1a .snowflake.v1.GetIDBatchResponse\x12L\n" +
+	"\tDecompose\x12\x1e.snowflake.v1.DecomposeRequest\x1a\x1f.snowflake.v1.DecomposeResponse\x12m\n" +
+	"\x14ReserveSequenceBlock\x12).snowflake.v1.ReserveSequenceBlockRequest\x1a*.snowflake.v1.ReserveSequenceBlockResponseB0Z.github.com/fethican/snowflake-go/snowflakegrpcb\x06proto3"
+
+var (
+	file_idservice_proto_rawDescOnce sync.Once
+	file_idservice_proto_rawDescData []byte
+)
+
+func file_idservice_proto_rawDescGZIP() []byte {
+	file_idservice_proto_rawDescOnce.Do(func() {
+		file_idservice_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_idservice_proto_rawDesc), len(file_idservice_proto_rawDesc)))
+	})
+	return file_idservice_proto_rawDescData
+}
+
+var file_idservice_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_idservice_proto_goTypes = []any{
+	(*GetIDRequest)(nil),                 // 0: snowflake.v1.GetIDRequest
+	(*GetIDResponse)(nil),                // 1: snowflake.v1.GetIDResponse
+	(*GetIDBatchRequest)(nil),            // 2: snowflake.v1.GetIDBatchRequest
+	(*GetIDBatchResponse)(nil),           // 3: snowflake.v1.GetIDBatchResponse
+	(*DecomposeRequest)(nil),             // 4: snowflake.v1.DecomposeRequest
+	(*DecomposeResponse)(nil),            // 5: snowflake.v1.DecomposeResponse
+	(*ReserveSequenceBlockRequest)(nil),  // 6: snowflake.v1.ReserveSequenceBlockRequest
+	(*ReserveSequenceBlockResponse)(nil), // 7: snowflake.v1.ReserveSequenceBlockResponse
+	(*timestamppb.Timestamp)(nil),        // 8: google.protobuf.Timestamp
+}
+var file_idservice_proto_depIdxs = []int32{
+	8, // 0: snowflake.v1.DecomposeResponse.time:type_name -> google.protobuf.Timestamp
+	0, // 1: snowflake.v1.IDService.GetID:input_type -> snowflake.v1.GetIDRequest
+	2, // 2: snowflake.v1.IDService.GetIDBatch:input_type -> snowflake.v1.GetIDBatchRequest
+	4, // 3: snowflake.v1.IDService.Decompose:input_type -> snowflake.v1.DecomposeRequest
+	6, // 4: snowflake.v1.IDService.ReserveSequenceBlock:input_type -> snowflake.v1.ReserveSequenceBlockRequest
+	1, // 5: snowflake.v1.IDService.GetID:output_type -> snowflake.v1.GetIDResponse
+	3, // 6: snowflake.v1.IDService.GetIDBatch:output_type -> snowflake.v1.GetIDBatchResponse
+	5, // 7: snowflake.v1.IDService.Decompose:output_type -> snowflake.v1.DecomposeResponse
+	7, // 8: snowflake.v1.IDService.ReserveSequenceBlock:output_type -> snowflake.v1.ReserveSequenceBlockResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_idservice_proto_init() }
+func file_idservice_proto_init() {
+	if File_idservice_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_idservice_proto_rawDesc), len(file_idservice_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_idservice_proto_goTypes,
+		DependencyIndexes: file_idservice_proto_depIdxs,
+		MessageInfos:      file_idservice_proto_msgTypes,
+	}.Build()
+	File_idservice_proto = out.File
+	file_idservice_proto_goTypes = nil
+	file_idservice_proto_depIdxs = nil
+}
@@ -0,0 +1,243 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: idservice.proto
+
+package snowflakegrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IDService_GetID_FullMethodName                = "/snowflake.v1.IDService/GetID"
+	IDService_GetIDBatch_FullMethodName           = "/snowflake.v1.IDService/GetIDBatch"
+	IDService_Decompose_FullMethodName            = "/snowflake.v1.IDService/Decompose"
+	IDService_ReserveSequenceBlock_FullMethodName = "/snowflake.v1.IDService/ReserveSequenceBlock"
+)
+
+// IDServiceClient is the client API for IDService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IDService issues and inspects snowflake IDs.
+type IDServiceClient interface {
+	GetID(ctx context.Context, in *GetIDRequest, opts ...grpc.CallOption) (*GetIDResponse, error)
+	GetIDBatch(ctx context.Context, in *GetIDBatchRequest, opts ...grpc.CallOption) (*GetIDBatchResponse, error)
+	Decompose(ctx context.Context, in *DecomposeRequest, opts ...grpc.CallOption) (*DecomposeResponse, error)
+	// ReserveSequenceBlock leases a range of sequence numbers within one tick
+	// so the client can mint the IDs itself.
+	ReserveSequenceBlock(ctx context.Context, in *ReserveSequenceBlockRequest, opts ...grpc.CallOption) (*ReserveSequenceBlockResponse, error)
+}
+
+type iDServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIDServiceClient(cc grpc.ClientConnInterface) IDServiceClient {
+	return &iDServiceClient{cc}
+}
+
+func (c *iDServiceClient) GetID(ctx context.Context, in *GetIDRequest, opts ...grpc.CallOption) (*GetIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIDResponse)
+	err := c.cc.Invoke(ctx, IDService_GetID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDServiceClient) GetIDBatch(ctx context.Context, in *GetIDBatchRequest, opts ...grpc.CallOption) (*GetIDBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIDBatchResponse)
+	err := c.cc.Invoke(ctx, IDService_GetIDBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDServiceClient) Decompose(ctx context.Context, in *DecomposeRequest, opts ...grpc.CallOption) (*DecomposeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecomposeResponse)
+	err := c.cc.Invoke(ctx, IDService_Decompose_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDServiceClient) ReserveSequenceBlock(ctx context.Context, in *ReserveSequenceBlockRequest, opts ...grpc.CallOption) (*ReserveSequenceBlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveSequenceBlockResponse)
+	err := c.cc.Invoke(ctx, IDService_ReserveSequenceBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IDServiceServer is the server API for IDService service.
+// All implementations must embed UnimplementedIDServiceServer
+// for forward compatibility.
+//
+// IDService issues and inspects snowflake IDs.
+type IDServiceServer interface {
+	GetID(context.Context, *GetIDRequest) (*GetIDResponse, error)
+	GetIDBatch(context.Context, *GetIDBatchRequest) (*GetIDBatchResponse, error)
+	Decompose(context.Context, *DecomposeRequest) (*DecomposeResponse, error)
+	// ReserveSequenceBlock leases a range of sequence numbers within one tick
+	// so the client can mint the IDs itself.
+	ReserveSequenceBlock(context.Context, *ReserveSequenceBlockRequest) (*ReserveSequenceBlockResponse, error)
+	mustEmbedUnimplementedIDServiceServer()
+}
+
+// UnimplementedIDServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIDServiceServer struct{}
+
+func (UnimplementedIDServiceServer) GetID(context.Context, *GetIDRequest) (*GetIDResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetID not implemented")
+}
+func (UnimplementedIDServiceServer) GetIDBatch(context.Context, *GetIDBatchRequest) (*GetIDBatchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetIDBatch not implemented")
+}
+func (UnimplementedIDServiceServer) Decompose(context.Context, *DecomposeRequest) (*DecomposeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Decompose not implemented")
+}
+func (UnimplementedIDServiceServer) ReserveSequenceBlock(context.Context, *ReserveSequenceBlockRequest) (*ReserveSequenceBlockResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReserveSequenceBlock not implemented")
+}
+func (UnimplementedIDServiceServer) mustEmbedUnimplementedIDServiceServer() {}
+func (UnimplementedIDServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeIDServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IDServiceServer will
+// result in compilation errors.
+type UnsafeIDServiceServer interface {
+	mustEmbedUnimplementedIDServiceServer()
+}
+
+func RegisterIDServiceServer(s grpc.ServiceRegistrar, srv IDServiceServer) {
+	// If the following call panics, it indicates UnimplementedIDServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IDService_ServiceDesc, srv)
+}
+
+func _IDService_GetID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).GetID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_GetID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).GetID(ctx, req.(*GetIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDService_GetIDBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIDBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).GetIDBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_GetIDBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).GetIDBatch(ctx, req.(*GetIDBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDService_Decompose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecomposeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).Decompose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_Decompose_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).Decompose(ctx, req.(*DecomposeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDService_ReserveSequenceBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveSequenceBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).ReserveSequenceBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_ReserveSequenceBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).ReserveSequenceBlock(ctx, req.(*ReserveSequenceBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IDService_ServiceDesc is the grpc.ServiceDesc for IDService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IDService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "snowflake.v1.IDService",
+	HandlerType: (*IDServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetID",
+			Handler:    _IDService_GetID_Handler,
+		},
+		{
+			MethodName: "GetIDBatch",
+			Handler:    _IDService_GetIDBatch_Handler,
+		},
+		{
+			MethodName: "Decompose",
+			Handler:    _IDService_Decompose_Handler,
+		},
+		{
+			MethodName: "ReserveSequenceBlock",
+			Handler:    _IDService_ReserveSequenceBlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "idservice.proto",
+}
@@ -0,0 +1,106 @@
+package snowflakegrpc
+
+import (
+	"context"
+	"errors"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const DefaultMaxBatch = 1000
+
+// Server implements IDServiceServer on top of a generator.
+type Server struct {
+	UnimplementedIDServiceServer
+
+	gen      *snowflake.Snowflake
+	maxBatch int
+}
+
+// NewServer returns a Server issuing IDs from gen. Batches are limited to
+// maxBatch IDs, or DefaultMaxBatch if maxBatch is not positive.
+func NewServer(gen *snowflake.Snowflake, maxBatch int) *Server {
+	if maxBatch <= 0 {
+		maxBatch = DefaultMaxBatch
+	}
+
+	return &Server{gen: gen, maxBatch: maxBatch}
+}
+
+func (s *Server) GetID(ctx context.Context, _ *GetIDRequest) (*GetIDResponse, error) {
+	id, err := s.gen.NextID()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &GetIDResponse{Id: id}, nil
+}
+
+func (s *Server) GetIDBatch(ctx context.Context, req *GetIDBatchRequest) (*GetIDBatchResponse, error) {
+	count := int(req.GetCount())
+	if count < 1 || count > s.maxBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "count must be between 1 and %d", s.maxBatch)
+	}
+
+	ids := make([]uint64, count)
+	for i := range ids {
+		id, err := s.gen.NextID()
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		ids[i] = id
+	}
+
+	return &GetIDBatchResponse{Ids: ids}, nil
+}
+
+func (s *Server) Decompose(ctx context.Context, req *DecomposeRequest) (*DecomposeResponse, error) {
+	id := req.GetId()
+	p := s.gen.Parser()
+	if err := p.Check(id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	parts := p.Parse(id)
+
+	return &DecomposeResponse{
+		Id:        id,
+		Time:      timestamppb.New(parts.Time),
+		Timestamp: parts.Timestamp,
+		MachineId: parts.MachineID,
+		Sequence:  parts.Sequence,
+		Tag:       parts.Tag,
+	}, nil
+}
+
+func (s *Server) ReserveSequenceBlock(ctx context.Context, req *ReserveSequenceBlockRequest) (*ReserveSequenceBlockResponse, error) {
+	b, err := s.gen.ReserveBlock(int(req.GetCount()))
+	if errors.Is(err, snowflake.ErrInvalidBlockSize) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	var order []uint32
+	if b.Layout.Order != (snowflake.FieldOrder{}) {
+		for _, f := range b.Layout.Order {
+			order = append(order, uint32(f))
+		}
+	}
+
+	return &ReserveSequenceBlockResponse{
+		Timestamp:     b.Timestamp,
+		MachineId:     b.MachineID,
+		FirstSequence: uint32(b.FirstSequence),
+		LastSequence:  uint32(b.LastSequence),
+		TimeBits:      uint32(b.Layout.TimeBits),
+		MachineBits:   uint32(b.Layout.MachineBits),
+		SequenceBits:  uint32(b.Layout.SequenceBits),
+		TagBits:       uint32(b.Layout.TagBits),
+		FieldOrder:    order,
+		LittleEndian:  b.Layout.LittleEndian,
+	}, nil
+}
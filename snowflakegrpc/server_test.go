@@ -0,0 +1,157 @@
+package snowflakegrpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func dial(t *testing.T, srv IDServiceServer) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	RegisterIDServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 9)
+	conn := dial(t, NewServer(gen, 10))
+	client := NewIDServiceClient(conn)
+
+	one, err := client.GetID(ctx, &GetIDRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts, err := client.Decompose(ctx, &DecomposeRequest{Id: one.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts.GetMachineId() != 9 || !parts.GetTime().AsTime().Equal(gen.IDToTime(one.GetId())) {
+		t.Errorf("unexpected decomposition: %v", parts)
+	}
+
+	if _, err := client.Decompose(ctx, &DecomposeRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a zero id, got %v", err)
+	}
+
+	tagged := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 9,
+		snowflake.WithLayout(snowflake.Layout{TimeBits: 41, TagBits: 2, MachineBits: 8, SequenceBits: 12}))
+	id, err := tagged.NextIDWithTag(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts, err = NewIDServiceClient(dial(t, NewServer(tagged, 10))).Decompose(ctx, &DecomposeRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts.GetTag() != 3 || parts.GetMachineId() != 9 {
+		t.Errorf("unexpected tagged decomposition: %v", parts)
+	}
+
+	_, err = client.GetIDBatch(ctx, &GetIDBatchRequest{Count: 11})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for oversized batch, got %v", err)
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 9)
+	c := NewClient(dial(t, NewServer(gen, 0)), 4)
+
+	var last uint64
+	for i := 0; i < 10; i++ {
+		id, err := c.NextID(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NextID(context.Background()); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("expected ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestLeaseClient(t *testing.T) {
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 9)
+	c := NewLeaseClient(dial(t, NewServer(gen, 0)), 3)
+
+	var last uint64
+	for i := 0; i < 10; i++ {
+		id, err := c.NextID(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+
+		// Interleave server-side issuance to make sure leases are respected.
+		if serverID, _ := gen.NextID(); serverID <= id {
+			t.Fatalf("server reissued leased id %d", serverID)
+		}
+	}
+
+	_, err := c.ReserveBlock(context.Background(), 0)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for empty block, got %v", err)
+	}
+
+	c.Close()
+	if _, err := c.NextID(context.Background()); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("expected ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestLeaseFieldOrder(t *testing.T) {
+	l := snowflake.Layout{
+		TimeBits: 41, MachineBits: 10, SequenceBits: 12, LittleEndian: true,
+		Order: snowflake.FieldOrder{snowflake.FieldTime, snowflake.FieldSequence, snowflake.FieldTag, snowflake.FieldMachine},
+	}
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 9, snowflake.WithLayout(l))
+	c := NewClient(dial(t, NewServer(gen, 0)), 4)
+
+	b, err := c.ReserveBlock(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Layout != l {
+		t.Errorf("leased layout %v, want %v", b.Layout, l)
+	}
+	if p := gen.Decompose(b.ID(2)); p.MachineID != 9 || p.Sequence != uint64(b.LastSequence) {
+		t.Errorf("leased id decodes to %+v", p)
+	}
+}
@@ -0,0 +1,47 @@
+package snowflakehttp
+
+import (
+	"expvar"
+	"net/http"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+// DebugHandler returns an http.Handler serving gen.Debug() as JSON.
+func DebugHandler(gen *snowflake.Snowflake) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, gen.Debug())
+	})
+}
+
+// JournalHandler returns an http.Handler serving gen.DumpJournal() as
+// plain text.
+func JournalHandler(gen *snowflake.Snowflake) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		gen.DumpJournal(w)
+	})
+}
+
+// HealthHandler returns an http.Handler serving gen.Health() as JSON, with
+// status 503 while the generator is failing.
+func HealthHandler(gen *snowflake.Snowflake) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := gen.Health()
+
+		status := http.StatusOK
+		if h.State == snowflake.HealthFailing {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, h)
+	})
+}
+
+// PublishExpvar publishes gen.Debug() as the expvar variable name, served
+// by the standard /debug/vars handler. Like expvar.Publish it panics if
+// name is already in use.
+func PublishExpvar(name string, gen *snowflake.Snowflake) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return gen.Debug()
+	}))
+}
@@ -0,0 +1,83 @@
+package snowflakehttp
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+func TestDebug(t *testing.T) {
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 12)
+	gen.NextID()
+
+	var info snowflake.DebugInfo
+
+	rec := httptest.NewRecorder()
+	NewHandler(gen).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/snowflake", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected the debug route to be off by default, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	NewHandler(gen, WithDebug()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/snowflake", nil))
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if info.MachineID != 12 || info.Issued != 1 || info.Layout.Layout != gen.Layout() {
+		t.Errorf("unexpected debug info %+v", info)
+	}
+
+	PublishExpvar("snowflake_test", gen)
+	if err := json.Unmarshal([]byte(expvar.Get("snowflake_test").String()), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.MachineID != 12 {
+		t.Errorf("unexpected expvar info %+v", info)
+	}
+}
+
+func TestJournal(t *testing.T) {
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 12, snowflake.WithJournal(4))
+	id, _ := gen.NextIDLabeled("checkout")
+
+	rec := httptest.NewRecorder()
+	NewHandler(gen, WithDebug()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/snowflake/journal", nil))
+	if want := "\t" + strconv.FormatUint(id, 10) + "\t1\tcheckout\n"; !strings.HasSuffix(rec.Body.String(), want) {
+		t.Errorf("unexpected journal %q", rec.Body.String())
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 12)
+	h := NewHandler(gen)
+
+	var status struct {
+		State   string   `json:"state"`
+		Reasons []string `json:"reasons"`
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || status.State != "ok" {
+		t.Errorf("expected a healthy generator, got %d %+v", rec.Code, status)
+	}
+
+	gen.Close()
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || status.State != "failing" || len(status.Reasons) != 1 {
+		t.Errorf("expected a failing generator, got %d %+v", rec.Code, status)
+	}
+}
@@ -0,0 +1,212 @@
+// Package snowflakehttp serves IDs from a snowflake generator over HTTP.
+//
+// Routes:
+//
+//	GET /id              {"id": "..."}
+//	GET /ids?count=N     {"ids": ["...", ...]}
+//	GET /decompose/{id}  {"id": "...", "time": "...", "timestamp": N, "machine_id": N, "sequence": N}
+//	GET /healthz         {"state": "ok|degraded|failing", "reasons": [...]}
+//	GET /debug/snowflake generator internals, with WithDebug only
+//	GET /debug/snowflake/journal recently issued IDs, with WithDebug only
+//
+// /healthz answers 503 Service Unavailable while the generator is failing,
+// so it can back a readiness probe; a degraded generator still answers 200.
+// IDs are encoded as JSON strings since they do not fit in a float64.
+package snowflakehttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+const DefaultMaxCount = 1000
+
+// Observer is called after every request with the matched route, the
+// response status and the time spent serving it.
+type Observer func(route string, status int, elapsed time.Duration)
+
+type config struct {
+	maxCount int
+	observer Observer
+	debug    bool
+}
+
+type Option func(*config)
+
+// WithMaxCount limits how many IDs a single /ids request may ask for.
+func WithMaxCount(n int) Option {
+	return func(c *config) {
+		c.maxCount = n
+	}
+}
+
+// WithObserver installs a per-route metrics hook.
+func WithObserver(o Observer) Option {
+	return func(c *config) {
+		c.observer = o
+	}
+}
+
+// WithDebug adds the /debug/snowflake route, which exposes the generator's
+// internals as returned by Snowflake.Debug, and /debug/snowflake/journal,
+// which dumps the journal kept with snowflake.WithJournal.
+func WithDebug() Option {
+	return func(c *config) {
+		c.debug = true
+	}
+}
+
+type handler struct {
+	gen *snowflake.Snowflake
+	config
+}
+
+// NewHandler returns an http.Handler issuing IDs from gen.
+func NewHandler(gen *snowflake.Snowflake, opts ...Option) http.Handler {
+	h := &handler{gen: gen, config: config{maxCount: DefaultMaxCount}}
+	for _, opt := range opts {
+		opt(&h.config)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/id", h.route("/id", h.serveID))
+	mux.Handle("/ids", h.route("/ids", h.serveIDs))
+	mux.Handle("/decompose/", h.route("/decompose", h.serveDecompose))
+	mux.Handle("/healthz", h.route("/healthz", HealthHandler(gen).ServeHTTP))
+	if h.debug {
+		mux.Handle("/debug/snowflake", h.route("/debug/snowflake", DebugHandler(gen).ServeHTTP))
+		mux.Handle("/debug/snowflake/journal", h.route("/debug/snowflake/journal", JournalHandler(gen).ServeHTTP))
+	}
+
+	return mux
+}
+
+// ListenAndServe serves h on addr until ctx is cancelled, then shuts the
+// server down gracefully, waiting for in-flight requests to finish.
+func ListenAndServe(ctx context.Context, addr string, h http.Handler) error {
+	srv := &http.Server{Addr: addr, Handler: h}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (h *handler) route(name string, fn http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		if r.Method != http.MethodGet {
+			writeError(sw, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		} else {
+			fn(sw, r)
+		}
+
+		if h.observer != nil {
+			h.observer(name, sw.status, time.Since(start))
+		}
+	})
+}
+
+func (h *handler) serveID(w http.ResponseWriter, r *http.Request) {
+	id, err := h.gen.NextID()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		ID string `json:"id"`
+	}{strconv.FormatUint(id, 10)})
+}
+
+func (h *handler) serveIDs(w http.ResponseWriter, r *http.Request) {
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 1 || count > h.maxCount {
+		writeError(w, http.StatusBadRequest, errors.New("count must be between 1 and "+strconv.Itoa(h.maxCount)))
+		return
+	}
+
+	ids := make([]string, count)
+	for i := range ids {
+		id, err := h.gen.NextID()
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		ids[i] = strconv.FormatUint(id, 10)
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		IDs []string `json:"ids"`
+	}{ids})
+}
+
+func (h *handler) serveDecompose(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/decompose/"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid id"))
+		return
+	}
+
+	p := h.gen.Parser()
+	if err := p.Check(id); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	parts := p.Parse(id)
+
+	writeJSON(w, http.StatusOK, struct {
+		ID        string    `json:"id"`
+		Time      time.Time `json:"time"`
+		Timestamp uint64    `json:"timestamp"`
+		Tag       uint64    `json:"tag,omitempty"`
+		MachineID uint64    `json:"machine_id"`
+		Sequence  uint64    `json:"sequence"`
+	}{strconv.FormatUint(id, 10), parts.Time.UTC(), parts.Timestamp, parts.Tag, parts.MachineID, parts.Sequence})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
@@ -0,0 +1,73 @@
+package snowflakehttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+func get(t *testing.T, h http.Handler, path string, v interface{}) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rec.Code
+}
+
+func TestHandler(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := snowflake.NewSnowflake(epoch, 12)
+
+	routes := map[string]int{}
+	h := NewHandler(gen, WithMaxCount(10), WithObserver(func(route string, status int, _ time.Duration) {
+		routes[route] = status
+	}))
+
+	var one struct{ ID string }
+	if code := get(t, h, "/id", &one); code != http.StatusOK {
+		t.Fatalf("/id returned %d", code)
+	}
+
+	var many struct{ IDs []string }
+	if code := get(t, h, "/ids?count=5", &many); code != http.StatusOK || len(many.IDs) != 5 {
+		t.Fatalf("/ids returned %d with %d ids", code, len(many.IDs))
+	}
+	if code := get(t, h, "/ids?count=11", nil); code != http.StatusBadRequest {
+		t.Errorf("count above the limit should be rejected, got %d", code)
+	}
+
+	var parts struct {
+		MachineID uint64 `json:"machine_id"`
+		Time      time.Time
+	}
+	if code := get(t, h, "/decompose/"+one.ID, &parts); code != http.StatusOK {
+		t.Fatalf("/decompose returned %d", code)
+	}
+	if parts.MachineID != 12 {
+		t.Errorf("expected machine id 12, got %d", parts.MachineID)
+	}
+	id, _ := strconv.ParseUint(one.ID, 10, 64)
+	if !parts.Time.Equal(gen.IDToTime(id)) {
+		t.Errorf("decomposed time %s does not match %s", parts.Time, gen.IDToTime(id))
+	}
+
+	if code := get(t, h, "/decompose/0", nil); code != http.StatusBadRequest {
+		t.Errorf("zero id should be rejected, got %d", code)
+	}
+
+	if routes["/id"] != http.StatusOK || routes["/ids"] != http.StatusBadRequest || routes["/decompose"] != http.StatusBadRequest {
+		t.Errorf("unexpected observed routes: %v", routes)
+	}
+}
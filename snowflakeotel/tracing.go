@@ -0,0 +1,85 @@
+// Package snowflakeotel records OpenTelemetry spans for the times a
+// snowflake generator blocks while issuing IDs.
+package snowflakeotel
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanName is the name of the spans recorded for waits.
+const SpanName = "snowflake.wait"
+
+// Wait reasons recorded in the snowflake.wait.reason attribute.
+const (
+	ReasonSequenceExhausted = "sequence_exhausted"
+	ReasonClockBehind       = "clock_behind"
+)
+
+// Tracer implements snowflake.Metrics by recording a span for every wait.
+// The generator does not pass a context through NextID, so the spans are
+// roots of their own traces; correlate them with request spans by time.
+type Tracer struct {
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+
+	sinceWait atomic.Int64
+	rollover  atomic.Bool
+	lag       atomic.Int64
+}
+
+var _ snowflake.Metrics = (*Tracer)(nil)
+
+// New returns a Tracer recording spans with tp. attrs are added to every
+// span, e.g. to tell several generators apart.
+func New(tp trace.TracerProvider, attrs ...attribute.KeyValue) *Tracer {
+	return &Tracer{
+		tracer: tp.Tracer("github.com/fethican/snowflake-go/snowflakeotel"),
+		attrs:  attrs,
+	}
+}
+
+func (t *Tracer) IDsGenerated(n int) {
+	t.sinceWait.Add(int64(n))
+}
+
+func (t *Tracer) SequenceRollover() {
+	t.rollover.Store(true)
+}
+
+func (t *Tracer) ClockBackwards(d time.Duration) {
+	t.lag.Store(int64(d))
+}
+
+// Waited records a span covering the sleep the generator is about to take.
+// Its attributes give the reason, the duration, the sequence pressure (the
+// number of IDs issued since the previous wait) and, for clock waits, how
+// far the clock last went backwards.
+func (t *Tracer) Waited(d time.Duration) {
+	reason := ReasonClockBehind
+	if t.rollover.Swap(false) {
+		reason = ReasonSequenceExhausted
+	}
+
+	start := time.Now()
+	attrs := append([]attribute.KeyValue{
+		attribute.String("snowflake.wait.reason", reason),
+		attribute.Int64("snowflake.wait.duration_ns", int64(d)),
+		attribute.Int64("snowflake.ids_since_last_wait", t.sinceWait.Swap(0)),
+	}, t.attrs...)
+	if reason == ReasonClockBehind {
+		attrs = append(attrs, attribute.Int64("snowflake.clock_lag_ns", t.lag.Load()))
+	}
+
+	_, span := t.tracer.Start(context.Background(), SpanName,
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attrs...),
+		trace.WithSpanKind(trace.SpanKindInternal),
+	)
+	span.End(trace.WithTimestamp(start.Add(d)))
+}
@@ -0,0 +1,56 @@
+package snowflakeotel
+
+import (
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/fethican/snowflake-go/clocktest"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracerRecordsWaits(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	tr := New(tp, attribute.String("generator", "test"))
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := snowflake.NewSnowflake(epoch, 1, snowflake.WithClock(clock), snowflake.WithMetrics(tr),
+		snowflake.WithLayout(snowflake.Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 2}))
+
+	for i := 0; i < 5; i++ {
+		if _, err := sf.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected one wait span, got %d", len(spans))
+	}
+
+	s := spans[0]
+	if s.Name() != SpanName {
+		t.Errorf("got span name %q", s.Name())
+	}
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range s.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["snowflake.wait.reason"].AsString(); got != ReasonSequenceExhausted {
+		t.Errorf("got reason %q, want %q", got, ReasonSequenceExhausted)
+	}
+	if got := attrs["snowflake.ids_since_last_wait"].AsInt64(); got != 4 {
+		t.Errorf("expected 4 ids before the wait, got %d", got)
+	}
+	if got := attrs["generator"].AsString(); got != "test" {
+		t.Errorf("expected the extra attribute, got %q", got)
+	}
+	if d := s.EndTime().Sub(s.StartTime()); d != time.Duration(attrs["snowflake.wait.duration_ns"].AsInt64()) {
+		t.Errorf("span length %s does not match the recorded wait", d)
+	}
+}
@@ -0,0 +1,40 @@
+package snowflakepb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+var ErrMismatch = errors.New("id value and text disagree")
+
+// New returns the message for id with both the value and the text set.
+func New(id snowflake.ID) *ID {
+	return &ID{Value: uint64(id), Text: strconv.FormatUint(uint64(id), 10)}
+}
+
+// NewCompact returns the message for id with only the value set.
+func NewCompact(id snowflake.ID) *ID {
+	return &ID{Value: uint64(id)}
+}
+
+// SnowflakeID returns the ID carried by x, taken from the value or, if the
+// value is zero, from the text. It fails if the text is malformed or
+// disagrees with a non-zero value. A nil message is ID 0.
+func (x *ID) SnowflakeID() (snowflake.ID, error) {
+	if x.GetText() == "" {
+		return snowflake.ID(x.GetValue()), nil
+	}
+
+	n, err := strconv.ParseUint(x.GetText(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", snowflake.ErrInvalidEncoding, err)
+	}
+	if x.GetValue() != 0 && x.GetValue() != n {
+		return 0, fmt.Errorf("%w: %d != %q", ErrMismatch, x.GetValue(), x.GetText())
+	}
+
+	return snowflake.ID(n), nil
+}
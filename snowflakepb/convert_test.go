@@ -0,0 +1,52 @@
+package snowflakepb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConvert(t *testing.T) {
+	id := snowflake.ID(1<<62 + 12345)
+
+	for _, m := range []*ID{New(id), NewCompact(id), {Text: "4611686018427400249"}} {
+		got, err := m.SnowflakeID()
+		if err != nil || got != id {
+			t.Errorf("%v: got %d, %v, want %d", m, got, err, id)
+		}
+
+		b, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back ID
+		if err := proto.Unmarshal(b, &back); err != nil || !proto.Equal(m, &back) {
+			t.Errorf("wire round trip of %v gave %v, %v", m, &back, err)
+		}
+	}
+
+	if got, err := (*ID)(nil).SnowflakeID(); got != 0 || err != nil {
+		t.Errorf("nil message: got %d, %v", got, err)
+	}
+	if _, err := (&ID{Value: 1, Text: "2"}).SnowflakeID(); !errors.Is(err, ErrMismatch) {
+		t.Errorf("expected ErrMismatch, got %v", err)
+	}
+	if _, err := (&ID{Text: "x"}).SnowflakeID(); !errors.Is(err, snowflake.ErrInvalidEncoding) {
+		t.Errorf("expected ErrInvalidEncoding, got %v", err)
+	}
+}
+
+func TestJSONMapping(t *testing.T) {
+	b, err := protojson.Marshal(NewCompact(1<<62 + 12345))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := strings.ReplaceAll(string(b), " ", ""); s != `{"value":"4611686018427400249"}` {
+		t.Errorf("expected the value as a JSON string, got %s", s)
+	}
+}
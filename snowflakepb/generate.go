@@ -0,0 +1,5 @@
+// Package snowflakepb defines a shared protobuf message for snowflake IDs
+// and converts it to and from snowflake.ID.
+package snowflakepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative id.proto
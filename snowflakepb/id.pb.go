@@ -0,0 +1,137 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: id.proto
+
+package snowflakepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ID is a snowflake identifier. The canonical JSON mapping renders value
+// as a string, so it survives JavaScript clients.
+type ID struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// value is the numeric ID. fixed64 is smaller than a varint for
+	// snowflakes, which use the high bits.
+	Value uint64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	// text optionally carries the decimal form for readers that cannot
+	// decode 64-bit integers. It must match value when both are set.
+	Text          string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ID) Reset() {
+	*x = ID{}
+	mi := &file_id_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ID) ProtoMessage() {}
+
+func (x *ID) ProtoReflect() protoreflect.Message {
+	mi := &file_id_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ID.ProtoReflect.Descriptor instead.
+func (*ID) Descriptor() ([]byte, []int) {
+	return file_id_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ID) GetValue() uint64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *ID) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+var File_id_proto protoreflect.FileDescriptor
+
+const file_id_proto_rawDesc = "" +
+	"\n" +
+	"\bid.proto\x12\fsnowflake.v1\".\n" +
+	"\x02ID\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x06R\x05value\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04textB.Z,github.com/fethican/snowflake-go/snowflakepbb\x06proto3"
+
+var (
+	file_id_proto_rawDescOnce sync.Once
+	file_id_proto_rawDescData []byte
+)
+
+func file_id_proto_rawDescGZIP() []byte {
+	file_id_proto_rawDescOnce.Do(func() {
+		file_id_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_id_proto_rawDesc), len(file_id_proto_rawDesc)))
+	})
+	return file_id_proto_rawDescData
+}
+
+var file_id_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_id_proto_goTypes = []any{
+	(*ID)(nil), // 0: snowflake.v1.ID
+}
+var file_id_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_id_proto_init() }
+func file_id_proto_init() {
+	if File_id_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_id_proto_rawDesc), len(file_id_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_id_proto_goTypes,
+		DependencyIndexes: file_id_proto_depIdxs,
+		MessageInfos:      file_id_proto_msgTypes,
+	}.Build()
+	File_id_proto = out.File
+	file_id_proto_goTypes = nil
+	file_id_proto_depIdxs = nil
+}
@@ -0,0 +1,112 @@
+// Package snowflakepg emits PostgreSQL objects that mint IDs compatible with
+// a snowflake generator, for rows inserted outside the Go service.
+package snowflakepg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"text/template"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+// Config names the emitted objects. Empty fields take the defaults.
+type Config struct {
+	// Function is the name of the ID function, default snowflake_next_id.
+	Function string
+	// Sequence feeds the sequence field, default <Function>_seq.
+	Sequence string
+	// ConfigTable holds the database's machine ID in its single row, in
+	// column machine_id; default snowflake_config. The machine ID must not
+	// be used by any Go generator.
+	ConfigTable string
+}
+
+var identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+var ErrInvalidConfig = errors.New("invalid postgres config")
+
+// Emit writes SQL creating the sequence, the config table and a function
+// returning IDs in gen's layout, epoch and time unit. Use the function as
+// a column default:
+//
+//	id bigint PRIMARY KEY DEFAULT snowflake_next_id()
+//
+// Like the generator, the function gives unique IDs as long as no more
+// than the sequence space is drawn per tick. It raises an exception once an
+// ID would no longer fit a signed bigint.
+func Emit(w io.Writer, gen *snowflake.Snowflake, cfg Config) error {
+	if cfg.Function == "" {
+		cfg.Function = "snowflake_next_id"
+	}
+	if cfg.Sequence == "" {
+		cfg.Sequence = cfg.Function + "_seq"
+	}
+	if cfg.ConfigTable == "" {
+		cfg.ConfigTable = "snowflake_config"
+	}
+	for _, name := range []string{cfg.Function, cfg.Sequence, cfg.ConfigTable} {
+		if !identifier.MatchString(name) {
+			return fmt.Errorf("%w: %q is not a plain identifier", ErrInvalidConfig, name)
+		}
+	}
+
+	unit := gen.TimeUnit()
+	if unit < time.Microsecond || unit%time.Microsecond != 0 {
+		return fmt.Errorf("%w: time unit %s is not a whole number of microseconds", ErrInvalidConfig, unit)
+	}
+
+	l := gen.Layout()
+
+	return functionTemplate.Execute(w, struct {
+		Config
+		EpochMicros   int64
+		UnitMicros    int64
+		TimeShift     uint
+		MachineBits   uint
+		SequenceBits  uint
+		MaxMachineID  uint64
+		SequenceSpace uint64
+		MaxTicks      uint64
+	}{
+		Config:        cfg,
+		EpochMicros:   gen.Epoch().UnixMicro(),
+		UnitMicros:    int64(unit / time.Microsecond),
+		TimeShift:     l.TagBits + l.MachineBits + l.SequenceBits,
+		MachineBits:   l.MachineBits,
+		SequenceBits:  l.SequenceBits,
+		MaxMachineID:  l.MaxMachineID(),
+		SequenceSpace: l.MaxSequence() + 1,
+		MaxTicks:      min(l.MaxTimestamp(), 1<<(63-l.TagBits-l.MachineBits-l.SequenceBits)-1),
+	})
+}
+
+var functionTemplate = template.Must(template.New("function").Parse(`CREATE SEQUENCE IF NOT EXISTS {{.Sequence}};
+
+CREATE TABLE IF NOT EXISTS {{.ConfigTable}} (
+    machine_id integer NOT NULL CHECK (machine_id BETWEEN 0 AND {{.MaxMachineID}})
+);
+
+CREATE OR REPLACE FUNCTION {{.Function}}() RETURNS bigint
+LANGUAGE plpgsql AS $$
+DECLARE
+    ticks bigint;
+    machine bigint;
+    seq bigint;
+BEGIN
+    -- {{.TimeShift}} low bits: {{.MachineBits}} machine bits, {{.SequenceBits}} sequence bits.
+    ticks := ((extract(epoch FROM clock_timestamp()) * 1000000)::bigint - {{.EpochMicros}}) / {{.UnitMicros}};
+    IF ticks < 0 OR ticks > {{.MaxTicks}} THEN
+        RAISE EXCEPTION 'snowflake timestamp % out of range', ticks;
+    END IF;
+
+    SELECT machine_id INTO STRICT machine FROM {{.ConfigTable}};
+    seq := nextval('{{.Sequence}}') % {{.SequenceSpace}};
+
+    RETURN (ticks << {{.TimeShift}}) | (machine << {{.SequenceBits}}) | seq;
+END;
+$$;
+`))
@@ -0,0 +1,39 @@
+package snowflakepg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+func TestEmit(t *testing.T) {
+	gen := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+
+	var b strings.Builder
+	if err := Emit(&b, gen, Config{Function: "app.next_id"}); err != nil {
+		t.Fatal(err)
+	}
+	sql := b.String()
+
+	for _, want := range []string{
+		"CREATE SEQUENCE IF NOT EXISTS app.next_id_seq;",
+		"CREATE TABLE IF NOT EXISTS snowflake_config",
+		"CHECK (machine_id BETWEEN 0 AND 1023)",
+		"CREATE OR REPLACE FUNCTION app.next_id() RETURNS bigint",
+		"- 1577836800000000) / 1000;",
+		"ticks > 2199023255551",
+		"nextval('app.next_id_seq') % 4096;",
+		"RETURN (ticks << 22) | (machine << 12) | seq;",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("emitted SQL lacks %q:\n%s", want, sql)
+		}
+	}
+
+	if err := Emit(&b, gen, Config{Function: "x(); DROP TABLE users; --"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a bad identifier, got %v", err)
+	}
+}
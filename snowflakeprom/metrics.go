@@ -0,0 +1,76 @@
+// Package snowflakeprom exports snowflake generator metrics to Prometheus.
+package snowflakeprom
+
+import (
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements snowflake.Metrics with Prometheus collectors.
+type Metrics struct {
+	generated prometheus.Counter
+	rollovers prometheus.Counter
+	wait      prometheus.Histogram
+	backwards prometheus.Counter
+	borrowed  prometheus.Gauge
+}
+
+var (
+	_ snowflake.Metrics       = (*Metrics)(nil)
+	_ snowflake.BorrowMetrics = (*Metrics)(nil)
+)
+
+// New creates the collectors and registers them with reg. constLabels are
+// attached to every series, e.g. to tell several generators apart.
+func New(reg prometheus.Registerer, constLabels prometheus.Labels) (*Metrics, error) {
+	m := &Metrics{
+		generated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   "snowflake",
+			Name:        "ids_generated_total",
+			Help:        "Number of IDs issued.",
+			ConstLabels: constLabels,
+		}),
+		rollovers: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   "snowflake",
+			Name:        "sequence_rollovers_total",
+			Help:        "Number of times a tick's sequence space was exhausted.",
+			ConstLabels: constLabels,
+		}),
+		wait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   "snowflake",
+			Name:        "wait_seconds",
+			Help:        "Time spent sleeping for the next tick after a rollover.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.ExponentialBuckets(1e-6, 4, 10),
+		}),
+		backwards: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   "snowflake",
+			Name:        "clock_backwards_total",
+			Help:        "Number of times the clock was read earlier than before.",
+			ConstLabels: constLabels,
+		}),
+		borrowed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   "snowflake",
+			Name:        "time_borrowed_seconds",
+			Help:        "How far the last ID is ahead of the clock.",
+			ConstLabels: constLabels,
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{m.generated, m.rollovers, m.wait, m.backwards, m.borrowed} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Metrics) IDsGenerated(n int)     { m.generated.Add(float64(n)) }
+func (m *Metrics) SequenceRollover()      { m.rollovers.Inc() }
+func (m *Metrics) Waited(d time.Duration) { m.wait.Observe(d.Seconds()) }
+
+func (m *Metrics) ClockBackwards(time.Duration) { m.backwards.Inc() }
+func (m *Metrics) TimeBorrowed(d time.Duration) { m.borrowed.Set(d.Seconds()) }
@@ -0,0 +1,36 @@
+package snowflakeprom
+
+import (
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg, prometheus.Labels{"machine": "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sf := snowflake.NewSnowflake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 7, snowflake.WithMetrics(m))
+	for i := 0; i < 10; i++ {
+		sf.NextID()
+	}
+
+	if got := testutil.ToFloat64(m.generated); got != 10 {
+		t.Errorf("expected 10 generated ids, got %v", got)
+	}
+
+	m.TimeBorrowed(3 * time.Millisecond)
+	if got := testutil.ToFloat64(m.borrowed); got != 0.003 {
+		t.Errorf("expected 3ms borrowed, got %v", got)
+	}
+
+	if _, err := New(reg, prometheus.Labels{"machine": "7"}); err == nil {
+		t.Error("registering the same collectors twice should fail")
+	}
+}
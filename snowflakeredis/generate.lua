@@ -0,0 +1,44 @@
+-- Issues a batch of snowflake sequence numbers from Redis' clock.
+--
+-- KEYS[1]  hash holding the tick and sequence last handed out
+-- ARGV[1]  epoch, in microseconds since the Unix epoch
+-- ARGV[2]  time unit, in microseconds
+-- ARGV[3]  largest timestamp of the layout
+-- ARGV[4]  largest sequence number of the layout
+-- ARGV[5]  number of IDs wanted
+--
+-- Returns {tick, first sequence, count}. If Redis' clock is behind the
+-- stored tick, or the tick's sequence is used up, the batch is taken from
+-- the stored tick or the next one, ahead of the clock.
+
+local epoch = tonumber(ARGV[1])
+local unit = tonumber(ARGV[2])
+local max_tick = tonumber(ARGV[3])
+local max_seq = tonumber(ARGV[4])
+local want = tonumber(ARGV[5])
+
+local now = redis.call('TIME')
+local tick = math.floor((tonumber(now[1]) * 1000000 + tonumber(now[2]) - epoch) / unit)
+if tick < 0 then
+	return redis.error_reply('clock is before the epoch')
+end
+
+local last = tonumber(redis.call('HGET', KEYS[1], 'tick') or -1)
+local seq = tonumber(redis.call('HGET', KEYS[1], 'seq') or -1)
+local first = 0
+if tick <= last then
+	tick = last
+	first = seq + 1
+	if first > max_seq then
+		tick = tick + 1
+		first = 0
+	end
+end
+if tick > max_tick then
+	return redis.error_reply('maximum timestamp has been reached')
+end
+
+local count = math.min(want, max_seq - first + 1)
+redis.call('HSET', KEYS[1], 'tick', tick, 'seq', first + count - 1)
+
+return {tick, first, count}
@@ -0,0 +1,133 @@
+// Package snowflakeredis issues snowflake IDs whose time and sequence come
+// from Redis, for app nodes whose clocks cannot be trusted.
+package snowflakeredis
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// Script is the bundled Lua script that hands out batches of sequence
+// numbers, timed with Redis' TIME. It needs Redis 5 or later.
+//
+//go:embed generate.lua
+var Script string
+
+var script = redis.NewScript(Script)
+
+var ErrInvalidConfig = errors.New("invalid redis generator config")
+
+// Config configures a Generator.
+type Config struct {
+	// LayoutDescriptor defines the IDs, like for a local generator. The
+	// time unit must be a whole number of microseconds.
+	snowflake.LayoutDescriptor
+	// MachineID is embedded in every ID. All nodes sharing it share the
+	// Redis key, which keeps their IDs unique.
+	MachineID uint64
+	// Key is the hash holding the generator state. Defaults to
+	// snowflake:<MachineID>.
+	Key string
+	// BatchSize is the number of IDs fetched per round trip, at most one
+	// tick's sequence space. Defaults to 1.
+	BatchSize int
+	// MaxBatchAge bounds how long a batch is handed out before the rest of
+	// it is abandoned, keeping embedded times close to Redis' clock.
+	// Defaults to one second.
+	MaxBatchAge time.Duration
+}
+
+// Generator issues IDs from batches reserved with Script. IDs from the same
+// machine ID never collide however many nodes share it, and their times
+// come from the Redis server rather than the node.
+type Generator struct {
+	client redis.Scripter
+	cfg    Config
+
+	mu        sync.Mutex
+	block     snowflake.Block
+	next      int
+	fetchedAt time.Time
+}
+
+// New returns a Generator running Script on client.
+func New(client redis.Scripter, cfg Config) (*Generator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.TimeUnit%time.Microsecond != 0 {
+		return nil, fmt.Errorf("%w: time unit %s is not a whole number of microseconds", ErrInvalidConfig, cfg.TimeUnit)
+	}
+	if cfg.MachineID > cfg.Layout.MaxMachineID() {
+		return nil, fmt.Errorf("%w: machine id %d does not fit in %d bits", ErrInvalidConfig, cfg.MachineID, cfg.Layout.MachineBits)
+	}
+	if cfg.Key == "" {
+		cfg.Key = "snowflake:" + strconv.FormatUint(cfg.MachineID, 10)
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1
+	}
+	if uint64(cfg.BatchSize) > cfg.Layout.MaxSequence()+1 {
+		return nil, fmt.Errorf("%w: batch size %d exceeds a tick", ErrInvalidConfig, cfg.BatchSize)
+	}
+	if cfg.MaxBatchAge <= 0 {
+		cfg.MaxBatchAge = time.Second
+	}
+
+	return &Generator{client: client, cfg: cfg}, nil
+}
+
+// NextID issues an ID from the current batch, fetching a new one from
+// Redis when it is used up or too old.
+func (g *Generator) NextID(ctx context.Context) (uint64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.fetchedAt.IsZero() || g.next >= g.block.Len() || time.Since(g.fetchedAt) > g.cfg.MaxBatchAge {
+		if err := g.fetch(ctx); err != nil {
+			return 0, err
+		}
+	}
+	id := g.block.ID(g.next)
+	g.next++
+
+	return id, nil
+}
+
+// Descriptor returns the layout of the generator's IDs, e.g. to decode
+// them with snowflake.NewFromLayout.
+func (g *Generator) Descriptor() snowflake.LayoutDescriptor {
+	return g.cfg.LayoutDescriptor
+}
+
+func (g *Generator) fetch(ctx context.Context) error {
+	l := g.cfg.Layout
+	res, err := script.Run(ctx, g.client, []string{g.cfg.Key},
+		g.cfg.Epoch.UnixMicro(), g.cfg.TimeUnit.Microseconds(), l.MaxTimestamp(), l.MaxSequence(), g.cfg.BatchSize).Int64Slice()
+	if err != nil {
+		return fmt.Errorf("reserve batch: %w", err)
+	}
+	if len(res) != 3 || res[2] < 1 {
+		return fmt.Errorf("reserve batch: unexpected reply %v", res)
+	}
+
+	g.block = snowflake.Block{
+		Layout:        l,
+		Timestamp:     res[0],
+		MachineID:     g.cfg.MachineID,
+		FirstSequence: uint16(res[1]),
+		LastSequence:  uint16(res[1] + res[2] - 1),
+	}
+	g.next = 0
+	g.fetchedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package snowflakeredis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	snowflake "github.com/fethican/snowflake-go"
+	"github.com/redis/go-redis/v9"
+)
+
+func newConfig() Config {
+	return Config{
+		LayoutDescriptor: snowflake.LayoutDescriptor{
+			Epoch:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Layout:   snowflake.Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 4},
+			TimeUnit: time.Millisecond,
+		},
+		MachineID: 7,
+		BatchSize: 5,
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	srv := miniredis.RunT(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	srv.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	ctx := context.Background()
+
+	// Two nodes share machine ID 7; Redis' time stands still, so the
+	// script runs ahead of it once a tick's 16 sequence numbers are used.
+	a, err := New(client, newConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := New(client, newConfig())
+	decoder := snowflake.NewFromLayout(a.Descriptor(), 0)
+
+	seen := make(map[uint64]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, g := range []*Generator{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				id, err := g.NextID(ctx)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for id := range seen {
+		p := decoder.Decompose(id)
+		if p.MachineID != 7 || p.Time.Before(now) || p.Time.After(now.Add(10*time.Millisecond)) {
+			t.Errorf("unexpected parts %+v", p)
+		}
+	}
+}
+
+func TestGeneratorRedisClockBackwards(t *testing.T) {
+	srv := miniredis.RunT(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	srv.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	ctx := context.Background()
+
+	cfg := newConfig()
+	cfg.BatchSize = 1
+	g, _ := New(client, cfg)
+	first, _ := g.NextID(ctx)
+
+	srv.SetTime(now.Add(-time.Minute))
+	next, err := g.NextID(ctx)
+	if err != nil || next <= first {
+		t.Errorf("expected an ID above %d, got %d, %v", first, next, err)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	for _, mutate := range []func(*Config){
+		func(c *Config) { c.TimeUnit = 1500 * time.Nanosecond },
+		func(c *Config) { c.MachineID = 1 << 10 },
+		func(c *Config) { c.BatchSize = 17 },
+		func(c *Config) { c.Layout.SequenceBits = 0 },
+	} {
+		cfg := newConfig()
+		mutate(&cfg)
+		if _, err := New(client, cfg); err == nil {
+			t.Errorf("expected an error for %+v", cfg)
+		}
+	}
+
+	cfg := newConfig()
+	cfg.MachineID = 1 << 10
+	if _, err := New(client, cfg); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
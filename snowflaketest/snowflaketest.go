@@ -0,0 +1,124 @@
+// Package snowflaketest provides property checks for snowflake generators.
+// They can be run against any generator configuration, e.g. from the tests
+// of a package that wraps snowflake.
+package snowflaketest
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+// Generator is the part of a generator exercised by the checks. It is
+// satisfied by *snowflake.Snowflake and by wrappers around it.
+type Generator interface {
+	NextID() (uint64, error)
+}
+
+// CheckUnique calls gen.NextID perGoroutine times from each of goroutines
+// concurrent goroutines and fails t if any ID is returned twice.
+func CheckUnique(t testing.TB, gen Generator, goroutines, perGoroutine int) {
+	t.Helper()
+
+	results := make([][]uint64, goroutines)
+	errs := make([]error, goroutines)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			ids := make([]uint64, 0, perGoroutine)
+			for i := 0; i < perGoroutine; i++ {
+				id, err := gen.NextID()
+				if err != nil {
+					errs[g] = err
+					break
+				}
+				ids = append(ids, id)
+			}
+			results[g] = ids
+		}(g)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("NextID: %v", err)
+		}
+	}
+
+	seen := make(map[uint64]struct{}, goroutines*perGoroutine)
+	for _, ids := range results {
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				t.Fatalf("duplicate id %d", id)
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+// CheckMonotonic calls gen.NextID n times and fails t unless the IDs issued
+// for each machine ID, as decoded with l, are strictly increasing.
+func CheckMonotonic(t testing.TB, gen Generator, l snowflake.Layout, n int) {
+	t.Helper()
+
+	last := make(map[uint64]uint64)
+	for i := 0; i < n; i++ {
+		id, err := gen.NextID()
+		if err != nil {
+			t.Fatalf("NextID: %v", err)
+		}
+
+		_, mid, _ := l.Decompose(id)
+		if prev, ok := last[mid]; ok && id <= prev {
+			t.Fatalf("machine %d issued %d after %d", mid, id, prev)
+		}
+		last[mid] = id
+	}
+}
+
+// CheckRoundTrip fails t unless composing ts, machineID and seq with l and
+// decomposing the result gives back the same fields. Out-of-range inputs
+// are masked to l's field widths first, which makes it suitable as the body
+// of a fuzz target.
+func CheckRoundTrip(t testing.TB, l snowflake.Layout, ts, machineID, seq uint64) {
+	t.Helper()
+
+	ts &= l.MaxTimestamp()
+	machineID &= l.MaxMachineID()
+	seq &= l.MaxSequence()
+
+	id := l.Compose(ts, machineID, seq)
+	gotTS, gotMID, gotSeq := l.Decompose(id)
+	if gotTS != ts || gotMID != machineID || gotSeq != seq {
+		t.Fatalf("%+v: Decompose(Compose(%d, %d, %d)) = %d, %d, %d", l, ts, machineID, seq, gotTS, gotMID, gotSeq)
+	}
+}
+
+// CheckLayout runs CheckRoundTrip on the boundary values of every field of
+// l and on samples pseudo-random combinations.
+func CheckLayout(t testing.TB, l snowflake.Layout, samples int) {
+	t.Helper()
+
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ts := range []uint64{0, l.MaxTimestamp()} {
+		for _, mid := range []uint64{0, l.MaxMachineID()} {
+			for _, seq := range []uint64{0, l.MaxSequence()} {
+				CheckRoundTrip(t, l, ts, mid, seq)
+			}
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < samples; i++ {
+		CheckRoundTrip(t, l, r.Uint64(), r.Uint64(), r.Uint64())
+	}
+}
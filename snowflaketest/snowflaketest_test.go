@@ -0,0 +1,59 @@
+package snowflaketest
+
+import (
+	"testing"
+	"time"
+
+	snowflake "github.com/fethican/snowflake-go"
+)
+
+func TestChecks(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, l := range []snowflake.Layout{snowflake.DefaultLayout, snowflake.InstagramLayout} {
+		sf := snowflake.NewSnowflake(epoch, 5, snowflake.WithLayout(l))
+
+		CheckUnique(t, sf, 8, 1000)
+		CheckMonotonic(t, sf, l, 10000)
+		CheckLayout(t, l, 1000)
+	}
+}
+
+type repeating struct{}
+
+func (repeating) NextID() (uint64, error) { return 42, nil }
+
+func TestCheckMonotonicFails(t *testing.T) {
+	ft := &fakeT{TB: t}
+	func() {
+		defer func() { recover() }()
+		CheckMonotonic(ft, repeating{}, snowflake.DefaultLayout, 2)
+	}()
+
+	if !ft.failed {
+		t.Error("expected CheckMonotonic to fail on a repeating generator")
+	}
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	f.Add(uint64(0), uint64(0), uint64(0))
+	f.Add(uint64(1<<63), uint64(1023), uint64(4095))
+
+	f.Fuzz(func(t *testing.T, ts, mid, seq uint64) {
+		CheckRoundTrip(t, snowflake.DefaultLayout, ts, mid, seq)
+		CheckRoundTrip(t, snowflake.InstagramLayout, ts, mid, seq)
+	})
+}
+
+// fakeT records fatal failures instead of ending the test.
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.failed = true
+	panic("fatal")
+}
@@ -0,0 +1,157 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrNoState       = errors.New("no persisted state")
+	ErrStateMismatch = errors.New("state belongs to a different generator")
+)
+
+// State is the part of a generator that must survive a restart so that no
+// ID is issued twice.
+type State struct {
+	StartTime     int64  `json:"start_time"`
+	MachineID     uint64 `json:"machine_id"`
+	LastTimestamp int64  `json:"last_timestamp"`
+	Sequence      uint16 `json:"sequence"`
+}
+
+// StateStore persists generator state. LoadState returns ErrNoState when
+// nothing has been saved yet.
+type StateStore interface {
+	LoadState() (State, error)
+	SaveState(State) error
+}
+
+// WithStateStore restores the generator from store on creation; NewSnowflake
+// returns nil if the stored state cannot be loaded or belongs to another
+// generator. Call SaveState to persist the current state.
+func WithStateStore(store StateStore) Option {
+	return func(sf *Snowflake) {
+		sf.store = store
+	}
+}
+
+// Snapshot returns the current generator state.
+func (sf *Snowflake) Snapshot() State {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	return State{
+		StartTime:     sf.startTime,
+		MachineID:     sf.machineID,
+		LastTimestamp: sf.lastTimestamp,
+		Sequence:      sf.sequence,
+	}
+}
+
+// Restore fast-forwards the generator to st so that subsequent IDs are
+// greater than any ID issued before st was taken. A state older than the
+// generator's own is ignored.
+func (sf *Snowflake) Restore(st State) error {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+
+	if st.StartTime != sf.startTime || st.MachineID != sf.machineID {
+		return ErrStateMismatch
+	}
+
+	if st.LastTimestamp > sf.lastTimestamp ||
+		(st.LastTimestamp == sf.lastTimestamp && st.Sequence > sf.sequence) {
+		sf.lastTimestamp = st.LastTimestamp
+		sf.sequence = st.Sequence
+		sf.seqIndex = st.Sequence
+		if sf.randomizedSequence() {
+			// The position in the random order is unknown; skip the tick
+			sf.seqIndex = sf.seqMask
+		}
+		sf.current.Store(uint64(sf.lastTimestamp)<<16 | uint64(sf.seqIndex))
+	}
+
+	return nil
+}
+
+// SaveState writes the current state to the configured StateStore.
+func (sf *Snowflake) SaveState() error {
+	if sf.store == nil {
+		return errors.New("no state store configured")
+	}
+
+	return sf.store.SaveState(sf.Snapshot())
+}
+
+func (sf *Snowflake) loadState() error {
+	st, err := sf.store.LoadState()
+	if errors.Is(err, ErrNoState) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return sf.Restore(st)
+}
+
+// FileStore is a StateStore keeping state as JSON in a single file.
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (fs *FileStore) LoadState() (State, error) {
+	var st State
+
+	data, err := os.ReadFile(fs.path)
+	if os.IsNotExist(err) {
+		return st, ErrNoState
+	}
+	if err != nil {
+		return st, err
+	}
+
+	err = json.Unmarshal(data, &st)
+
+	return st, err
+}
+
+// SaveState atomically replaces the state file.
+func (fs *FileStore) SaveState(st State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(fs.path, data)
+}
+
+// writeFileAtomic replaces path with data through a synced temporary file,
+// so readers never see a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
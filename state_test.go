@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+
+	sf := NewSnowflake(epoch, 7, WithClock(clock))
+	var lastID uint64
+	for i := 0; i < 10; i++ {
+		lastID, _ = sf.NextID()
+	}
+	st := sf.Snapshot()
+
+	// Restart within the same millisecond, then again after the clock stepped back.
+	for _, d := range []time.Duration{0, -time.Second} {
+		clock.Advance(d)
+
+		restarted := NewSnowflake(epoch, 7, WithClock(clock))
+		if err := restarted.Restore(st); err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := restarted.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= lastID {
+			t.Errorf("restored generator reissued id %d (last %d)", id, lastID)
+		}
+	}
+
+	other := NewSnowflake(epoch, 8, WithClock(clock))
+	if err := other.Restore(st); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("expected ErrStateMismatch, got %v", err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	store := NewFileStore(filepath.Join(t.TempDir(), "snowflake.json"))
+
+	sf := NewSnowflake(epoch, 7, WithClock(clock), WithStateStore(store))
+	if sf == nil {
+		t.Fatal("missing state file should not prevent startup")
+	}
+
+	lastID, _ := sf.NextID()
+	if err := sf.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := NewSnowflake(epoch, 7, WithClock(clock), WithStateStore(store))
+	if id, _ := restarted.NextID(); id <= lastID {
+		t.Errorf("restored generator reissued id %d (last %d)", id, lastID)
+	}
+
+	if NewSnowflake(epoch, 8, WithClock(clock), WithStateStore(store)) != nil {
+		t.Error("generator with a different machine id should refuse foreign state")
+	}
+}
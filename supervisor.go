@@ -0,0 +1,269 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownGenerator = errors.New("unknown generator")
+	ErrGeneratorFailed  = errors.New("generator failed")
+)
+
+// SupervisionPolicy decides what a Supervisor does with a generator that
+// keeps returning errors.
+type SupervisionPolicy int
+
+const (
+	// PolicyRestart shuts the generator down and replaces it with a new one
+	// from its factory. The new generator is restored from the old one's
+	// state, so it never reissues IDs; if that fails, the generator is marked
+	// as failed.
+	PolicyRestart SupervisionPolicy = iota
+	// PolicyFailover routes requests to the fallback generator.
+	PolicyFailover
+	// PolicyFail marks the generator as failed; NextID returns ErrGeneratorFailed.
+	PolicyFail
+)
+
+// Supervised describes a generator owned by a Supervisor.
+type Supervised struct {
+	Factory func() *Snowflake
+	Policy  SupervisionPolicy
+	// MaxErrors is the number of consecutive errors that triggers the
+	// policy. Zero means 1.
+	MaxErrors int
+	// Fallback names the generator used by PolicyFailover.
+	Fallback string
+}
+
+type SupervisorEventKind int
+
+const (
+	EventError SupervisorEventKind = iota
+	EventRestarted
+	EventFailedOver
+	EventFailed
+)
+
+// SupervisorEvent reports a change in the health of a supervised generator.
+type SupervisorEvent struct {
+	Name string
+	Kind SupervisorEventKind
+	Err  error
+	Time time.Time
+}
+
+// GeneratorStats is the health of one supervised generator.
+type GeneratorStats struct {
+	Issued    uint64
+	Errors    uint64
+	Restarts  uint64
+	Failed    bool
+	LastError error
+}
+
+// SupervisorStats aggregates the health of all supervised generators.
+type SupervisorStats struct {
+	Generators map[string]GeneratorStats
+	Issued     uint64
+	Errors     uint64
+	Failed     int
+}
+
+// Healthy reports whether no generator has failed.
+func (s SupervisorStats) Healthy() bool {
+	return s.Failed == 0
+}
+
+type supervised struct {
+	Supervised
+
+	mu          sync.Mutex
+	gen         *Snowflake
+	consecutive int
+	stats       GeneratorStats
+}
+
+// Supervisor owns several named generators, applies a SupervisionPolicy to
+// generators that keep failing and reports what it does as events.
+type Supervisor struct {
+	onEvent func(SupervisorEvent)
+
+	mu      sync.RWMutex
+	members map[string]*supervised
+}
+
+// NewSupervisor returns an empty Supervisor. onEvent, if not nil, is called
+// synchronously for every event.
+func NewSupervisor(onEvent func(SupervisorEvent)) *Supervisor {
+	return &Supervisor{onEvent: onEvent, members: make(map[string]*supervised)}
+}
+
+// Add creates a generator from cfg.Factory and places it under supervision.
+func (s *Supervisor) Add(name string, cfg Supervised) error {
+	gen := cfg.Factory()
+	if gen == nil {
+		return fmt.Errorf("generator %q could not be created", name)
+	}
+	if cfg.MaxErrors <= 0 {
+		cfg.MaxErrors = 1
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.members[name]; ok {
+		return fmt.Errorf("generator %q already supervised", name)
+	}
+	s.members[name] = &supervised{Supervised: cfg, gen: gen}
+
+	return nil
+}
+
+// NextID issues an ID from the named generator, or from its fallback once it
+// has failed over.
+func (s *Supervisor) NextID(name string) (uint64, error) {
+	var visited map[string]bool
+	var m *supervised
+	for {
+		if m = s.member(name); m == nil {
+			return 0, fmt.Errorf("%w: %q", ErrUnknownGenerator, name)
+		}
+
+		m.mu.Lock()
+		if !m.stats.Failed {
+			break
+		}
+		m.mu.Unlock()
+
+		// Follow the fallback chain until it reaches a healthy generator,
+		// giving up if it loops back to one already tried.
+		if visited == nil {
+			visited = make(map[string]bool)
+		}
+		visited[name] = true
+		if m.Policy != PolicyFailover || visited[m.Fallback] {
+			return 0, fmt.Errorf("%w: %q", ErrGeneratorFailed, name)
+		}
+		name = m.Fallback
+	}
+
+	id, err := m.gen.NextID()
+	if err == nil {
+		m.consecutive = 0
+		m.stats.Issued++
+		m.mu.Unlock()
+		return id, nil
+	}
+
+	m.consecutive++
+	m.stats.Errors++
+	m.stats.LastError = err
+	events := []SupervisorEvent{{Name: name, Kind: EventError, Err: err}}
+	if m.consecutive >= m.MaxErrors {
+		events = append(events, s.applyPolicy(name, m))
+	}
+	m.mu.Unlock()
+
+	for _, ev := range events {
+		s.emit(ev)
+	}
+
+	return 0, err
+}
+
+// applyPolicy must be called with m.mu held.
+func (s *Supervisor) applyPolicy(name string, m *supervised) SupervisorEvent {
+	m.consecutive = 0
+
+	switch m.Policy {
+	case PolicyRestart:
+		// Shut the old generator down first so that its machine ID lease is
+		// released before the factory acquires one for the replacement.
+		st := m.gen.Snapshot()
+		if err := m.gen.Shutdown(context.Background()); err != nil && !errors.Is(err, ErrClosed) {
+			m.stats.LastError = errors.Join(m.stats.LastError, err)
+		}
+		gen := m.Factory()
+		if gen == nil {
+			break
+		}
+		if err := gen.Restore(st); err != nil {
+			gen.Close()
+			m.stats.LastError = errors.Join(m.stats.LastError, fmt.Errorf("restore: %w", err))
+			break
+		}
+		m.gen = gen
+		m.stats.Restarts++
+		return SupervisorEvent{Name: name, Kind: EventRestarted}
+	case PolicyFailover:
+		if s.member(m.Fallback) != nil {
+			m.stats.Failed = true
+			return SupervisorEvent{Name: name, Kind: EventFailedOver, Err: m.stats.LastError}
+		}
+	}
+
+	m.stats.Failed = true
+
+	return SupervisorEvent{Name: name, Kind: EventFailed, Err: m.stats.LastError}
+}
+
+// Stats returns the health of all supervised generators.
+func (s *Supervisor) Stats() SupervisorStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st := SupervisorStats{Generators: make(map[string]GeneratorStats, len(s.members))}
+	for name, m := range s.members {
+		m.mu.Lock()
+		gs := m.stats
+		m.mu.Unlock()
+
+		st.Generators[name] = gs
+		st.Issued += gs.Issued
+		st.Errors += gs.Errors
+		if gs.Failed {
+			st.Failed++
+		}
+	}
+
+	return st
+}
+
+func (s *Supervisor) member(name string) *supervised {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.members[name]
+}
+
+func (s *Supervisor) emit(ev SupervisorEvent) {
+	if s.onEvent != nil {
+		ev.Time = time.Now()
+		s.onEvent(ev)
+	}
+}
+
+// Shutdown shuts down every supervised generator, see Snowflake.Shutdown.
+// Generators that are already closed are skipped.
+func (s *Supervisor) Shutdown(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var errs []error
+	for name, m := range s.members {
+		m.mu.Lock()
+		gen := m.gen
+		m.mu.Unlock()
+
+		if err := gen.Shutdown(ctx); err != nil && !errors.Is(err, ErrClosed) {
+			errs = append(errs, fmt.Errorf("generator %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestSupervisor(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	factory := func(machineID int) func() *Snowflake {
+		return func() *Snowflake {
+			return NewSnowflake(epoch, machineID, WithClock(clock), WithRuntimeChecks())
+		}
+	}
+
+	var events []SupervisorEventKind
+	s := NewSupervisor(func(ev SupervisorEvent) { events = append(events, ev.Kind) })
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(s.Add("eu", Supervised{Factory: factory(1), Policy: PolicyRestart}))
+	must(s.Add("us", Supervised{Factory: factory(2), Policy: PolicyFailover, Fallback: "eu"}))
+	must(s.Add("ap", Supervised{Factory: factory(3), Policy: PolicyFail, MaxErrors: 2}))
+
+	for _, name := range []string{"eu", "us", "ap"} {
+		if _, err := s.NextID(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Corrupted state makes the runtime checks reject the next id.
+	corrupt := func(names ...string) {
+		for _, name := range names {
+			s.members[name].gen.lastTimestamp -= 10
+		}
+	}
+	corrupt("eu", "us", "ap")
+
+	if _, err := s.NextID("eu"); err == nil {
+		t.Fatal("expected an error from the corrupted generator")
+	}
+	if _, err := s.NextID("eu"); err != nil {
+		t.Errorf("restarted generator should work, got %v", err)
+	}
+
+	s.NextID("us")
+	id, err := s.NextID("us")
+	if err != nil {
+		t.Fatalf("failed over generator should use its fallback, got %v", err)
+	}
+	if _, mid, _ := DecomposeParts(id); mid != 1 {
+		t.Errorf("expected id from fallback machine 1, got machine %d", mid)
+	}
+
+	s.NextID("ap")
+	corrupt("ap")
+	s.NextID("ap")
+	if _, err := s.NextID("ap"); !errors.Is(err, ErrGeneratorFailed) {
+		t.Errorf("expected ErrGeneratorFailed, got %v", err)
+	}
+
+	if _, err := s.NextID("sa"); !errors.Is(err, ErrUnknownGenerator) {
+		t.Errorf("expected ErrUnknownGenerator, got %v", err)
+	}
+
+	want := []SupervisorEventKind{EventError, EventRestarted, EventError, EventFailedOver, EventError, EventError, EventFailed}
+	if len(events) != len(want) {
+		t.Fatalf("events %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("events %v, want %v", events, want)
+		}
+	}
+
+	st := s.Stats()
+	if st.Healthy() || st.Failed != 2 || st.Generators["eu"].Restarts != 1 {
+		t.Errorf("unexpected stats %+v", st)
+	}
+}
+
+func TestSupervisorFallbackCycle(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	factory := func(machineID int) func() *Snowflake {
+		return func() *Snowflake {
+			return NewSnowflake(epoch, machineID, WithClock(clock), WithRuntimeChecks())
+		}
+	}
+
+	s := NewSupervisor(nil)
+	if err := s.Add("a", Supervised{Factory: factory(1), Policy: PolicyFailover, Fallback: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("b", Supervised{Factory: factory(2), Policy: PolicyFailover, Fallback: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		s.NextID(name)
+		s.members[name].gen.lastTimestamp -= 10
+		s.NextID(name)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		if _, err := s.NextID(name); !errors.Is(err, ErrGeneratorFailed) {
+			t.Errorf("%s: expected ErrGeneratorFailed, got %v", name, err)
+		}
+	}
+}
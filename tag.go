@@ -0,0 +1,20 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidTag = errors.New("tag does not fit the layout")
+
+// NextIDWithTag is NextID with tag in the layout's tag field, which
+// WithLayout must declare. Decompose returns the tag in Parts.Tag. It
+// returns ErrInvalidTag if tag does not fit the field.
+func (sf *Snowflake) NextIDWithTag(tag uint8) (uint64, error) {
+	if uint64(tag) > sf.layout.MaxTag() {
+		return 0, fmt.Errorf("%w: %d needs more than %d bits", ErrInvalidTag, tag, sf.layout.TagBits)
+	}
+
+	return sf.issue(context.Background(), idRequest{tag: uint64(tag)})
+}
@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestNextIDWithTag(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	l := Layout{TimeBits: 41, TagBits: 2, MachineBits: 9, SequenceBits: 12}
+	sf := NewSnowflake(epoch, 300, WithClock(clock), WithLayout(l))
+
+	var last uint64
+	for _, tag := range []uint8{3, 0, 2, 1} {
+		id, err := sf.NextIDWithTag(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := sf.Decompose(id)
+		if p.Tag != uint64(tag) || p.MachineID != 300 || !p.Time.Equal(clock.Now()) {
+			t.Errorf("tag %d: unexpected parts %+v", tag, p)
+		}
+		last = id
+		clock.Advance(time.Millisecond)
+	}
+
+	// Untagged IDs have tag 0 and still sort by time after tagged ones.
+	id, _ := sf.NextID()
+	if sf.Decompose(id).Tag != 0 || id <= last {
+		t.Errorf("untagged id %d after %d has tag %d", id, last, sf.Decompose(id).Tag)
+	}
+
+	if _, err := sf.NextIDWithTag(4); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+	if _, err := NewSnowflake(epoch, 1, WithClock(clock)).NextIDWithTag(1); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag without tag bits, got %v", err)
+	}
+
+	if got := l.String(); got != "41/2/9/12" {
+		t.Errorf("unexpected layout string %q", got)
+	}
+	if err := (Layout{TimeBits: 40, TagBits: 9, MachineBits: 3, SequenceBits: 12}).Validate(); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("expected 9 tag bits to be rejected, got %v", err)
+	}
+	if err := (Layout{TimeBits: 42, TagBits: 1, MachineBits: 10, SequenceBits: 12}).Validate(); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("expected 65 bits to be rejected, got %v", err)
+	}
+
+	b, _ := json.Marshal(sf.Descriptor())
+	var d LayoutDescriptor
+	if err := json.Unmarshal(b, &d); err != nil || d.Layout != l {
+		t.Errorf("descriptor round trip lost the tag bits: %s", b)
+	}
+}
@@ -0,0 +1,32 @@
+package snowflake
+
+import "time"
+
+// FirstIDForTime returns the smallest ID the generator's epoch can produce
+// for the tick containing t. Times before the epoch map to 0.
+//
+// Together with LastIDForTime it turns a time range into an ID range:
+//
+//	WHERE id BETWEEN sf.FirstIDForTime(from) AND sf.LastIDForTime(to)
+func (sf *Snowflake) FirstIDForTime(t time.Time) uint64 {
+	ts := sf.toUnit(t) - sf.startTime
+	if ts < 0 {
+		return 0
+	}
+	if uint64(ts) > sf.layout.MaxTimestamp() {
+		ts = int64(sf.layout.MaxTimestamp())
+	}
+
+	return sf.layout.Compose(uint64(ts), 0, 0)
+}
+
+// LastIDForTime returns the largest ID the generator's epoch can produce for
+// the tick containing t, with any tag. Times before the epoch map to 0,
+// times after the last representable tick to the largest ID.
+func (sf *Snowflake) LastIDForTime(t time.Time) uint64 {
+	if sf.toUnit(t) < sf.startTime {
+		return 0
+	}
+
+	return sf.FirstIDForTime(t) | sf.layout.ComposeTagged(0, sf.layout.MaxTag(), sf.layout.MaxMachineID(), sf.layout.MaxSequence())
+}
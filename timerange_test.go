@@ -0,0 +1,67 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestIDForTime(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour + 500*time.Microsecond))
+	sf := NewSnowflake(epoch, 1023, WithClock(clock))
+
+	var ids []uint64
+	for i := 0; i < 10; i++ {
+		id, _ := sf.NextID()
+		ids = append(ids, id)
+	}
+
+	now := clock.Now()
+	first, last := sf.FirstIDForTime(now), sf.LastIDForTime(now)
+	for _, id := range ids {
+		if id < first || id > last {
+			t.Errorf("id %d outside [%d, %d]", id, first, last)
+		}
+	}
+
+	if sf.LastIDForTime(now.Add(-time.Millisecond)) >= first {
+		t.Error("previous tick must end before the current one starts")
+	}
+	if sf.FirstIDForTime(now.Add(time.Millisecond)) <= last {
+		t.Error("next tick must start after the current one ends")
+	}
+
+	before := epoch.Add(-time.Hour)
+	if sf.FirstIDForTime(before) != 0 || sf.LastIDForTime(before) != 0 {
+		t.Error("times before the epoch should map to 0")
+	}
+	if sf.LastIDForTime(epoch.Add(200*365*24*time.Hour)) != 1<<64-1 {
+		t.Error("times past the last tick should map to the largest id")
+	}
+}
+
+func TestIDForTimeTagged(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock),
+		WithLayout(Layout{TimeBits: 41, TagBits: 2, MachineBits: 9, SequenceBits: 12}))
+
+	id, err := sf.NextIDWithTag(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := clock.Now()
+	if last := sf.LastIDForTime(now); id > last {
+		t.Errorf("tagged id %d above LastIDForTime %d", id, last)
+	}
+	if after := sf.MinIDAfter(now); id >= after {
+		t.Errorf("tagged id %d not below MinIDAfter %d", id, after)
+	}
+	b := sf.Bucket(id, time.Hour)
+	if first, last := sf.BucketIDs(b, time.Hour); id < first || id > last {
+		t.Errorf("tagged id %d outside its bucket [%d, %d]", id, first, last)
+	}
+}
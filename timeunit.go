@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"math"
+	"time"
+)
+
+// Time units for WithTimeUnit. Coarser units stretch the lifetime of the
+// timestamp field; finer ones spread IDs over more ticks.
+const (
+	TimeUnit100Microseconds = 100 * time.Microsecond
+	TimeUnitMillisecond     = time.Millisecond
+	TimeUnit10Milliseconds  = 10 * time.Millisecond
+	TimeUnit100Milliseconds = 100 * time.Millisecond
+	TimeUnitSecond          = time.Second
+)
+
+// WithTimeUnit sets the length of one tick of the timestamp field. The
+// default is TimeUnitMillisecond. The unit must divide a second evenly or
+// be a whole number of seconds, otherwise NewSnowflake returns nil; it also
+// returns nil if the time bits, counted in the unit from the epoch, have
+// already run out. Use Layout.Lifetime to check that they last long enough.
+//
+// ID methods and the other package-level helpers assume milliseconds, so
+// IDs from other units should be decoded with the generator's methods.
+func WithTimeUnit(d time.Duration) Option {
+	return func(sf *Snowflake) {
+		sf.unit = d
+	}
+}
+
+// TimeUnit returns the length of one tick of the generator's timestamps.
+func (sf *Snowflake) TimeUnit() time.Duration {
+	return sf.unit
+}
+
+// Lifetime returns how long after the epoch the time field of l runs out
+// when each tick lasts unit. It saturates at the largest Duration.
+func (l Layout) Lifetime(unit time.Duration) time.Duration {
+	ticks := l.MaxTimestamp() + 1
+	switch {
+	case unit <= 0:
+		return 0
+	case ticks > uint64(math.MaxInt64/unit):
+		return math.MaxInt64
+	}
+
+	return time.Duration(ticks) * unit
+}
+
+func validUnit(d time.Duration) bool {
+	switch {
+	case d <= 0:
+		return false
+	case d <= time.Second:
+		return time.Second%d == 0
+	default:
+		return d%time.Second == 0
+	}
+}
+
+// toUnit converts t to ticks since the Unix epoch.
+func (sf *Snowflake) toUnit(t time.Time) int64 {
+	return t.UnixNano() / int64(sf.unit)
+}
+
+// fromUnit converts ticks since the Unix epoch to a time. It works on
+// seconds so that coarse units do not overflow nanosecond arithmetic.
+func (sf *Snowflake) fromUnit(ticks int64) time.Time {
+	if sf.unit > time.Second {
+		return time.Unix(ticks*int64(sf.unit/time.Second), 0)
+	}
+
+	perSecond := int64(time.Second / sf.unit)
+
+	return time.Unix(ticks/perSecond, ticks%perSecond*int64(sf.unit))
+}
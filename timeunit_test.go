@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestTimeUnits(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(100*time.Hour + 1234567*time.Microsecond)
+
+	for _, unit := range []time.Duration{TimeUnit100Microseconds, TimeUnitMillisecond, TimeUnit10Milliseconds, TimeUnitSecond, time.Minute} {
+		clock := clocktest.NewFakeClock(now)
+		sf := NewSnowflake(epoch, 3, WithClock(clock), WithTimeUnit(unit))
+		if sf.TimeUnit() != unit {
+			t.Fatalf("got unit %s, want %s", sf.TimeUnit(), unit)
+		}
+
+		a, _ := sf.NextID()
+		if p := sf.Decompose(a); !p.Time.Equal(now.Truncate(unit)) || p.MachineID != 3 {
+			t.Errorf("%s: Decompose = %+v, want time %s", unit, p, now.Truncate(unit))
+		}
+
+		clock.Advance(unit)
+		b, _ := sf.NextID()
+		if ts, _, _ := sf.Layout().Decompose(b - a); ts != 1 {
+			t.Errorf("%s: expected one tick between ids, got %d", unit, ts)
+		}
+
+		if got := sf.FirstIDForTime(now.Add(unit)); got != b&^sf.Layout().Compose(0, sf.Layout().MaxMachineID(), sf.Layout().MaxSequence()) {
+			t.Errorf("%s: FirstIDForTime = %d, id %d", unit, got, b)
+		}
+	}
+
+	for _, unit := range []time.Duration{0, -time.Millisecond, 7 * time.Millisecond, 1500 * time.Millisecond} {
+		if NewSnowflake(epoch, 1, WithTimeUnit(unit)) != nil {
+			t.Errorf("expected unit %s to be rejected", unit)
+		}
+	}
+}
+
+func TestLayoutLifetime(t *testing.T) {
+	if got := DefaultLayout.Lifetime(TimeUnitMillisecond); got/(24*time.Hour*365) != 139 {
+		t.Errorf("expected about 139 years, got %s", got)
+	}
+	if got := DefaultLayout.Lifetime(TimeUnitSecond); got != math.MaxInt64 {
+		t.Errorf("expected lifetime to saturate, got %s", got)
+	}
+	if got := (Layout{TimeBits: 10, SequenceBits: 1}).Lifetime(time.Second); got != 1024*time.Second {
+		t.Errorf("got %s, want 1024s", got)
+	}
+}
+
+func TestTimeUnitLifetimeExhausted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := Layout{TimeBits: 30, MachineBits: 10, SequenceBits: 12}
+	clock := clocktest.NewFakeClock(epoch.Add(l.Lifetime(TimeUnit100Microseconds)))
+
+	if NewSnowflake(epoch, 1, WithClock(clock), WithLayout(l), WithTimeUnit(TimeUnit100Microseconds)) != nil {
+		t.Error("expected a unit whose time bits already ran out to be rejected")
+	}
+	if NewSnowflake(epoch, 1, WithClock(clock), WithLayout(l), WithTimeUnit(TimeUnitMillisecond)) == nil {
+		t.Error("expected a coarser unit with time left to be accepted")
+	}
+}
@@ -0,0 +1,21 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+)
+
+// errWouldBlock is returned by nextID in place of sleeping when called by
+// TryNextID.
+var errWouldBlock = errors.New("would block")
+
+// TryNextID is a NextID that never sleeps. It returns false instead of
+// waiting for the next tick when the current tick's sequence is exhausted,
+// for the clock to catch up after moving backwards, or for rate limit
+// tokens, and on any error NextID would return, so callers on latency
+// critical paths can fall back or queue the request.
+func (sf *Snowflake) TryNextID() (uint64, bool) {
+	id, err := sf.issue(context.Background(), idRequest{noWait: true})
+
+	return id, err == nil
+}
@@ -0,0 +1,74 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestTryNextID(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(time.Hour))
+	sf := NewSnowflake(epoch, 1, WithClock(clock))
+
+	var last uint64
+	for i := 0; i < 1<<SequenceBits; i++ {
+		id, ok := sf.TryNextID()
+		if !ok || id <= last {
+			t.Fatalf("TryNextID %d = %d, %v after %d", i, id, ok, last)
+		}
+		last = id
+	}
+
+	start := clock.Now()
+	if _, ok := sf.TryNextID(); ok {
+		t.Fatal("expected an exhausted tick to fail")
+	}
+	if !clock.Now().Equal(start) {
+		t.Errorf("TryNextID slept for %s", clock.Now().Sub(start))
+	}
+	if st := sf.Stats(); st.Rollovers != 0 || st.Issued != 1<<SequenceBits {
+		t.Errorf("a failed TryNextID changed the stats: %+v", st)
+	}
+
+	clock.Advance(time.Millisecond)
+	if id, ok := sf.TryNextID(); !ok || id <= last {
+		t.Errorf("expected an ID in the next tick, got %d, %v", id, ok)
+	}
+
+	// Waiting for a clock that moved backwards.
+	sf = NewSnowflake(epoch, 1, WithClock(clock), WithMaxBackwardTolerance(10*time.Millisecond))
+	sf.NextID()
+	clock.Advance(-5 * time.Millisecond)
+	if _, ok := sf.TryNextID(); ok {
+		t.Error("expected TryNextID not to wait for the clock")
+	}
+
+	// Waiting for rate limit tokens.
+	sf = NewSnowflake(epoch, 1, WithClock(clock), WithMaxIDsPerSecond(1000, 1, RateLimitWait))
+	if _, ok := sf.TryNextID(); !ok {
+		t.Fatal("expected the first token to be available")
+	}
+	if _, ok := sf.TryNextID(); ok {
+		t.Error("expected TryNextID not to wait for a token")
+	}
+
+	// A TryNextID that would wait for the clock keeps its token.
+	sf = NewSnowflake(epoch, 1, WithClock(clock), WithMaxBackwardTolerance(10*time.Millisecond),
+		WithMaxIDsPerSecond(1000, 2, RateLimitReject))
+	sf.NextID()
+	clock.Advance(-5 * time.Millisecond)
+	if _, ok := sf.TryNextID(); ok {
+		t.Fatal("expected TryNextID not to wait for the clock")
+	}
+	clock.Advance(5 * time.Millisecond)
+	if _, ok := sf.TryNextID(); !ok {
+		t.Error("expected the failed TryNextID not to have taken a token")
+	}
+
+	sf.Close()
+	if _, ok := sf.TryNextID(); ok {
+		t.Error("expected a closed generator to fail")
+	}
+}
@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ULID is a 128-bit Universally Unique Lexicographically Sortable Identifier:
+// a 48-bit Unix millisecond timestamp followed by 80 bits of randomness.
+type ULID [16]byte
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID converts id, interpreted against the package epoch, into a ULID with
+// the same millisecond timestamp. The machine ID and sequence are stored in
+// the first 22 bits of the random part, so converted IDs keep their order
+// and FromULID can restore them; the remaining 58 bits are read from entropy.
+func (id ID) ULID(entropy io.Reader) (ULID, error) {
+	var u ULID
+
+	ms := uint64(id.Time().UnixMilli())
+	_, mid, seq := DefaultLayout.Decompose(uint64(id))
+
+	var rnd [8]byte
+	if _, err := io.ReadFull(entropy, rnd[:]); err != nil {
+		return u, err
+	}
+	r := binary.BigEndian.Uint64(rnd[:])
+	low := (mid<<SequenceBits|seq)<<(64-MachineIDBits-SequenceBits) | r>>(MachineIDBits+SequenceBits)
+
+	binary.BigEndian.PutUint16(u[0:], uint16(ms>>32))
+	binary.BigEndian.PutUint32(u[2:], uint32(ms))
+	binary.BigEndian.PutUint64(u[6:], low)
+	binary.BigEndian.PutUint16(u[14:], uint16(r))
+
+	return u, nil
+}
+
+// FromULID converts a ULID created by ID.ULID back into an ID under the
+// package epoch. It returns ErrEpochOverflow for ULIDs whose time cannot be
+// represented.
+func FromULID(u ULID) (ID, error) {
+	ms := int64(binary.BigEndian.Uint16(u[0:]))<<32 | int64(binary.BigEndian.Uint32(u[2:]))
+	low := binary.BigEndian.Uint64(u[6:]) >> (64 - MachineIDBits - SequenceBits)
+
+	ts := ms - epochStart.UnixMilli()
+	if ts < 0 || uint64(ts) > DefaultLayout.MaxTimestamp() {
+		return 0, fmt.Errorf("%w: ulid time %d", ErrEpochOverflow, ms)
+	}
+
+	return ID(DefaultLayout.Compose(uint64(ts), 0, 0) | low), nil
+}
+
+// String returns the canonical 26-character Crockford base32 form.
+func (u ULID) String() string {
+	hi, lo := binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+
+	var buf [26]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(buf[:])
+}
+
+// ParseULID parses the canonical string form of a ULID, case-insensitively.
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != 26 {
+		return u, fmt.Errorf("%w: ulid must be 26 characters", ErrInvalidEncoding)
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(crockfordAlphabet, upper(s[i]))
+		if d < 0 || (i == 0 && d > 7) {
+			return u, fmt.Errorf("%w: invalid ulid character %q", ErrInvalidEncoding, s[i])
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(d)
+	}
+
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+
+	return u, nil
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+
+	return c
+}
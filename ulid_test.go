@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestULIDRoundTrip(t *testing.T) {
+	sf := NewSnowflake(time.Time{}, 300)
+
+	var prev ULID
+	for i := 0; i < 100; i++ {
+		n, _ := sf.NextID()
+		id := ID(n)
+
+		u, err := id.ULID(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := time.UnixMilli(int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])); !got.Equal(id.Time()) {
+			t.Errorf("ulid time %s, want %s", got, id.Time())
+		}
+
+		back, err := FromULID(u)
+		if err != nil || back != id {
+			t.Fatalf("FromULID returned %d, %v, want %d", back, err, id)
+		}
+
+		if i > 0 && (bytes.Compare(prev[:], u[:]) >= 0 || prev.String() >= u.String()) {
+			t.Errorf("ulids out of order: %s >= %s", prev, u)
+		}
+		prev = u
+	}
+}
+
+func TestULIDString(t *testing.T) {
+	u := ULID{0x01, 0x8f, 0x2a, 0x11, 0x22, 0x33, 0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa, 0x99, 0x88, 0x77, 0x66}
+
+	s := u.String()
+	if len(s) != 26 {
+		t.Fatalf("unexpected length %d", len(s))
+	}
+
+	got, err := ParseULID(strings.ToLower(s))
+	if err != nil || got != u {
+		t.Errorf("ParseULID(%q) = %x, %v", s, got, err)
+	}
+
+	if ULID([16]byte{15: 1}).String() != "00000000000000000000000001" {
+		t.Errorf("unexpected encoding %s", ULID([16]byte{15: 1}))
+	}
+
+	for _, s := range []string{"", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "0000000000000000000000000U"} {
+		if _, err := ParseULID(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("ParseULID(%q) should fail, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is an RFC 9562 UUID.
+type UUID [16]byte
+
+// UUIDv7 converts id, interpreted against the package epoch, into a
+// version 7 UUID whose unix_ts_ms field is the ID's wall-clock millisecond.
+// The 22 machine and sequence bits fill rand_a and the top of rand_b, so
+// UUIDs sort like the IDs they came from; the rest of rand_b is zero.
+func (id ID) UUIDv7() UUID {
+	var u UUID
+
+	ms := uint64(id.Time().UnixMilli())
+	_, mid, seq := DefaultLayout.Decompose(uint64(id))
+	low := mid<<SequenceBits | seq // 22 bits
+
+	binary.BigEndian.PutUint16(u[0:], uint16(ms>>32))
+	binary.BigEndian.PutUint32(u[2:], uint32(ms))
+	binary.BigEndian.PutUint16(u[6:], 0x7000|uint16(low>>10))
+	binary.BigEndian.PutUint64(u[8:], 0x8000000000000000|(low&0x3ff)<<52)
+
+	return u
+}
+
+// FromUUIDv7 extracts the ID packed by ID.UUIDv7. For other version 7 UUIDs
+// it returns an ID with the UUID's time and whatever random bits occupy the
+// machine and sequence positions.
+func FromUUIDv7(u UUID) (ID, error) {
+	if u.Version() != 7 || u[8]&0xc0 != 0x80 {
+		return 0, fmt.Errorf("%w: not a version 7 uuid", ErrInvalidEncoding)
+	}
+
+	ms := int64(binary.BigEndian.Uint16(u[0:]))<<32 | int64(binary.BigEndian.Uint32(u[2:]))
+	randA := uint64(binary.BigEndian.Uint16(u[6:]) & 0x0fff)
+	randB := binary.BigEndian.Uint64(u[8:]) >> 52 & 0x3ff
+
+	ts := ms - epochStart.UnixMilli()
+	if ts < 0 || uint64(ts) > DefaultLayout.MaxTimestamp() {
+		return 0, fmt.Errorf("%w: uuid time %d", ErrEpochOverflow, ms)
+	}
+
+	return ID(DefaultLayout.Compose(uint64(ts), 0, 0) | randA<<10 | randB), nil
+}
+
+// Version returns the UUID version number.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// String returns the canonical xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx form.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
+
+// ParseUUID parses the canonical string form of a UUID.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("%w: malformed uuid", ErrInvalidEncoding)
+	}
+
+	groups := [][2]int{{0, 8}, {9, 13}, {14, 18}, {19, 23}, {24, 36}}
+	n := 0
+	for _, g := range groups {
+		m, err := hex.Decode(u[n:], []byte(s[g[0]:g[1]]))
+		if err != nil {
+			return u, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+		}
+		n += m
+	}
+
+	return u, nil
+}
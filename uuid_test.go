@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUUIDv7(t *testing.T) {
+	sf := NewSnowflake(time.Time{}, 1023)
+
+	var prev UUID
+	for i := 0; i < 100; i++ {
+		n, _ := sf.NextID()
+		id := ID(n)
+		u := id.UUIDv7()
+
+		if u.Version() != 7 || u[8]&0xc0 != 0x80 {
+			t.Fatalf("%s is not a version 7 uuid", u)
+		}
+
+		back, err := FromUUIDv7(u)
+		if err != nil || back != id {
+			t.Fatalf("FromUUIDv7(%s) = %d, %v, want %d", u, back, err, id)
+		}
+
+		parsed, err := ParseUUID(u.String())
+		if err != nil || parsed != u {
+			t.Fatalf("ParseUUID(%s) = %s, %v", u, parsed, err)
+		}
+
+		if i > 0 && bytes.Compare(prev[:], u[:]) >= 0 {
+			t.Errorf("uuids out of order: %s >= %s", prev, u)
+		}
+		prev = u
+	}
+
+	v4, _ := ParseUUID("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	if _, err := FromUUIDv7(v4); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("expected ErrInvalidEncoding for a v4 uuid, got %v", err)
+	}
+	if _, err := ParseUUID("f47ac10b58cc-4372-a567-0e02b2c3d4790"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("expected ErrInvalidEncoding for a malformed uuid, got %v", err)
+	}
+}
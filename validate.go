@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidID        = errors.New("invalid id")
+	ErrImplausibleTime  = errors.New("id timestamp is implausible")
+	ErrUntrustedMachine = errors.New("id was not issued by a trusted machine")
+
+	// ErrIDFromFuture and ErrIDTooOld classify ErrImplausibleTime.
+	ErrIDFromFuture = fmt.Errorf("%w: from the future", ErrImplausibleTime)
+	ErrIDTooOld     = fmt.Errorf("%w: too old", ErrImplausibleTime)
+)
+
+type validateConfig struct {
+	trusted   map[uint64]struct{}
+	notBefore time.Time
+	maxFuture time.Duration
+	future    bool
+	maxAge    time.Duration
+}
+
+// ValidateOption configures the checks performed by Validate.
+type ValidateOption func(*validateConfig)
+
+// WithTrustedMachines restricts valid IDs to the given machine IDs.
+// IDs claiming any other machine ID are rejected with ErrUntrustedMachine.
+func WithTrustedMachines(machineIDs ...uint64) ValidateOption {
+	return func(c *validateConfig) {
+		if c.trusted == nil {
+			c.trusted = make(map[uint64]struct{}, len(machineIDs))
+		}
+		for _, mid := range machineIDs {
+			c.trusted[mid] = struct{}{}
+		}
+	}
+}
+
+// WithNotBefore rejects IDs whose embedded time is before t, e.g. the time
+// the service was first deployed, with ErrIDTooOld.
+func WithNotBefore(t time.Time) ValidateOption {
+	return func(c *validateConfig) {
+		c.notBefore = t
+	}
+}
+
+// WithMaxFuture rejects IDs whose embedded time is more than d after the
+// generator's current time with ErrIDFromFuture. d is the clock skew
+// tolerated between issuers and the validator.
+func WithMaxFuture(d time.Duration) ValidateOption {
+	return func(c *validateConfig) {
+		c.maxFuture = d
+		c.future = true
+	}
+}
+
+// WithMaxAge rejects IDs whose embedded time is more than d before the
+// generator's current time with ErrIDTooOld, e.g. to refuse replayed
+// requests.
+func WithMaxAge(d time.Duration) ValidateOption {
+	return func(c *validateConfig) {
+		c.maxAge = d
+	}
+}
+
+// Validate checks that id could have been issued under the given
+// constraints: it must be non-zero and fit the layout, its machine ID must
+// be allowed by the generator's WithAllowedMachineIDs list if it has one,
+// and its timestamp and machine ID must pass the checks enabled by opts. It
+// is meant for IDs received from external clients; it does not prove the
+// ID was actually issued.
+func (sf *Snowflake) Validate(id uint64, opts ...ValidateOption) error {
+	var c validateConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	p := sf.Parser()
+	if err := p.Check(id); err != nil {
+		return err
+	}
+
+	parts := p.Parse(id)
+	mid := parts.MachineID
+
+	t, now := parts.Time, sf.clock.Now()
+	if t.Before(c.notBefore) {
+		return fmt.Errorf("%w: %s is before %s", ErrIDTooOld, t.UTC(), c.notBefore.UTC())
+	}
+	if limit := now.Add(-c.maxAge); c.maxAge > 0 && t.Before(limit) {
+		return fmt.Errorf("%w: %s is before %s", ErrIDTooOld, t.UTC(), limit.UTC())
+	}
+	if limit := now.Add(c.maxFuture); c.future && t.After(limit) {
+		return fmt.Errorf("%w: %s is after %s", ErrIDFromFuture, t.UTC(), limit.UTC())
+	}
+
+	if !sf.machineAllowed(mid) {
+		return fmt.Errorf("%w: machine id %d is not deployed", ErrUntrustedMachine, mid)
+	}
+	if c.trusted != nil {
+		if _, ok := c.trusted[mid]; !ok {
+			return fmt.Errorf("%w: machine id %d", ErrUntrustedMachine, mid)
+		}
+	}
+
+	return nil
+}
+
+// WithAllowedMachineIDs registers the machine IDs that are legitimately
+// deployed. NewSnowflake returns nil if its own machine ID is not listed,
+// Validate rejects IDs from other machines with ErrUntrustedMachine and
+// Decompose marks them as UnknownMachine.
+func WithAllowedMachineIDs(machineIDs ...uint64) Option {
+	return func(sf *Snowflake) {
+		if sf.allowed == nil {
+			sf.allowed = make(map[uint64]struct{}, len(machineIDs))
+		}
+		for _, mid := range machineIDs {
+			sf.allowed[mid] = struct{}{}
+		}
+	}
+}
+
+func (sf *Snowflake) machineAllowed(mid uint64) bool {
+	if sf.allowed == nil {
+		return true
+	}
+	_, ok := sf.allowed[mid]
+
+	return ok
+}
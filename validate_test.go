@@ -0,0 +1,124 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fethican/snowflake-go/clocktest"
+)
+
+func TestValidateTrustedMachines(t *testing.T) {
+	sf := NewSnowflake(time.Now().Add(-time.Hour), 34)
+
+	id, err := sf.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sf.Validate(id); err != nil {
+		t.Errorf("unexpected error without options: %v", err)
+	}
+
+	if err := sf.Validate(id, WithTrustedMachines(1, 34)); err != nil {
+		t.Errorf("machine 34 should be trusted: %v", err)
+	}
+
+	err = sf.Validate(id, WithTrustedMachines(1, 2))
+	if !errors.Is(err, ErrUntrustedMachine) {
+		t.Errorf("expected ErrUntrustedMachine, got %v", err)
+	}
+}
+
+func TestValidateTime(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(24 * time.Hour))
+	sf := NewSnowflake(epoch, 34, WithClock(clock))
+
+	id, _ := sf.NextID()
+
+	if err := sf.Validate(0); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID for zero, got %v", err)
+	}
+
+	if err := sf.Validate(id, WithNotBefore(epoch.Add(time.Hour)), WithMaxFuture(0)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := sf.Validate(id, WithNotBefore(clock.Now().Add(time.Second))); !errors.Is(err, ErrImplausibleTime) {
+		t.Errorf("expected ErrImplausibleTime for an id before the lower bound, got %v", err)
+	}
+
+	future := sf.TimeToSnowflakeID(clock.Now().Add(time.Minute)) | 1
+	if err := sf.Validate(future, WithMaxFuture(time.Second)); !errors.Is(err, ErrImplausibleTime) {
+		t.Errorf("expected ErrImplausibleTime for an id from the future, got %v", err)
+	}
+	if err := sf.Validate(future, WithMaxFuture(time.Hour)); err != nil {
+		t.Errorf("id within the future window should pass, got %v", err)
+	}
+}
+
+func TestValidateLayoutBits(t *testing.T) {
+	sf := NewSnowflake(time.Now().Add(-time.Hour), 34,
+		WithLayout(Layout{TimeBits: 41, MachineBits: 10, SequenceBits: 12}))
+
+	id, _ := sf.NextID()
+	if err := sf.Validate(id | 1<<63); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("expected ErrInvalidID for bits above the layout, got %v", err)
+	}
+}
+
+func TestValidateClassifiesTime(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFakeClock(epoch.Add(24 * time.Hour))
+	sf := NewSnowflake(epoch, 34, WithClock(clock))
+
+	now := clock.Now()
+	for _, tc := range []struct {
+		at   time.Time
+		opts []ValidateOption
+		want error
+	}{
+		{now.Add(4 * time.Second), []ValidateOption{WithMaxFuture(5 * time.Second)}, nil},
+		{now.Add(6 * time.Second), []ValidateOption{WithMaxFuture(5 * time.Second)}, ErrIDFromFuture},
+		{now.Add(-time.Minute), []ValidateOption{WithMaxAge(time.Hour)}, nil},
+		{now.Add(-2 * time.Hour), []ValidateOption{WithMaxAge(time.Hour)}, ErrIDTooOld},
+		{now.Add(-2 * time.Hour), []ValidateOption{WithNotBefore(now.Add(-time.Hour))}, ErrIDTooOld},
+	} {
+		id := sf.TimeToSnowflakeID(tc.at) | 34<<SequenceBits
+		err := sf.Validate(id, tc.opts...)
+
+		if tc.want == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.at.Sub(now), err)
+		}
+		if tc.want != nil && (!errors.Is(err, tc.want) || !errors.Is(err, ErrImplausibleTime)) {
+			t.Errorf("%s: expected %v, got %v", tc.at.Sub(now), tc.want, err)
+		}
+	}
+}
+
+func TestAllowedMachineIDs(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	allow := WithAllowedMachineIDs(3, 5)
+
+	if NewSnowflake(epoch, 4, allow) != nil {
+		t.Fatal("expected an unlisted machine id to be refused")
+	}
+
+	sf := NewSnowflake(epoch, 3, allow)
+	other := NewSnowflake(epoch, 5)
+	rogue := NewSnowflake(epoch, 9)
+
+	for _, tc := range []struct {
+		gen     *Snowflake
+		unknown bool
+	}{{sf, false}, {other, false}, {rogue, true}} {
+		id, _ := tc.gen.NextID()
+
+		if p := sf.Decompose(id); p.UnknownMachine != tc.unknown {
+			t.Errorf("machine %d: got UnknownMachine %t, want %t", p.MachineID, p.UnknownMachine, tc.unknown)
+		}
+		if err := sf.Validate(id); errors.Is(err, ErrUntrustedMachine) != tc.unknown {
+			t.Errorf("machine %d: unexpected Validate result %v", tc.gen.MachineID(), err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+var ErrVectorMismatch = errors.New("test vector mismatch")
+
+// TestVector is one ID together with the inputs it was composed from and
+// its encodings, for checking other implementations against this package.
+// IDs are strings in JSON since many languages lose precision above 2^53.
+type TestVector struct {
+	Layout    LayoutDescriptor `json:"layout"`
+	Time      time.Time        `json:"time"`
+	Timestamp uint64           `json:"timestamp"` // ticks since the epoch
+	Tag       uint64           `json:"tag,omitempty"`
+	MachineID uint64           `json:"machine_id"`
+	Sequence  uint64           `json:"sequence"`
+
+	ID     string `json:"id"`
+	Padded string `json:"padded"`
+	Hex    string `json:"hex"`
+	Base62 string `json:"base62"`
+	Base32 string `json:"base32"`
+}
+
+// NewTestVector composes the vector for the given fields, which must fit
+// d.
+func NewTestVector(d LayoutDescriptor, ts, tag, machineID, seq uint64) TestVector {
+	id := ID(d.ComposeTagged(ts, tag, machineID, seq))
+
+	return TestVector{
+		Layout:    d,
+		Time:      d.Epoch.Add(time.Duration(ts) * d.TimeUnit).UTC(),
+		Timestamp: ts,
+		Tag:       tag,
+		MachineID: machineID,
+		Sequence:  seq,
+		ID:        strconv.FormatUint(uint64(id), 10),
+		Padded:    id.StringPadded(),
+		Hex:       id.Hex(),
+		Base62:    id.Base62(),
+		Base32:    id.Base32(),
+	}
+}
+
+// GenerateTestVectors returns, for each descriptor, vectors for the edge
+// values of every field followed by n random ones drawn from seed, so the
+// output is reproducible.
+func GenerateTestVectors(descs []LayoutDescriptor, n int, seed uint64) []TestVector {
+	r := rand.New(rand.NewPCG(seed, seed))
+
+	var vs []TestVector
+	for _, d := range descs {
+		// Keep times within what time.Duration can add to the epoch.
+		maxTS := min(d.MaxTimestamp(), uint64(1<<63-1)/uint64(d.TimeUnit))
+		edges := [][4]uint64{
+			{0, 0, 0, 0},
+			{maxTS, d.MaxTag(), d.MaxMachineID(), d.MaxSequence()},
+			{1, 0, 0, 1},
+			{maxTS, 0, 0, 0},
+			{0, d.MaxTag(), 0, 0},
+			{0, 0, d.MaxMachineID(), 0},
+			{0, 0, 0, d.MaxSequence()},
+		}
+		for _, e := range edges {
+			vs = append(vs, NewTestVector(d, e[0], e[1], e[2], e[3]))
+		}
+		for range n {
+			vs = append(vs, NewTestVector(d,
+				r.Uint64N(maxTS+1), r.Uint64()&d.MaxTag(), r.Uint64()&d.MaxMachineID(), r.Uint64()&d.MaxSequence()))
+		}
+	}
+
+	return vs
+}
+
+// Verify checks that v's ID is composed from its fields, decomposes back
+// into them, and that the time and every encoding match. It returns
+// ErrVectorMismatch describing the first difference.
+func (v TestVector) Verify() error {
+	if err := v.Layout.Validate(); err != nil {
+		return err
+	}
+	want := NewTestVector(v.Layout, v.Timestamp, v.Tag, v.MachineID, v.Sequence)
+
+	id, err := strconv.ParseUint(v.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: id %q: %v", ErrVectorMismatch, v.ID, err)
+	}
+	ts, mid, seq := v.Layout.Decompose(id)
+
+	for _, c := range []struct {
+		field     string
+		got, want any
+	}{
+		{"id", v.ID, want.ID},
+		{"timestamp", ts, v.Timestamp},
+		{"tag", v.Layout.Tag(id), v.Tag},
+		{"machine_id", mid, v.MachineID},
+		{"sequence", seq, v.Sequence},
+		{"padded", v.Padded, want.Padded},
+		{"hex", v.Hex, want.Hex},
+		{"base62", v.Base62, want.Base62},
+		{"base32", v.Base32, want.Base32},
+	} {
+		if c.got != c.want {
+			return fmt.Errorf("%w: id %s: %s is %v, want %v", ErrVectorMismatch, v.ID, c.field, c.got, c.want)
+		}
+	}
+	if !v.Time.Equal(want.Time) {
+		return fmt.Errorf("%w: id %s: time is %s, want %s", ErrVectorMismatch, v.ID, v.Time, want.Time)
+	}
+
+	return nil
+}
+
+// WriteTestVectors writes vs as an indented JSON array.
+func WriteTestVectors(w io.Writer, vs []TestVector) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(vs)
+}
+
+// VerifyTestVectors reads a JSON array of vectors from r and verifies each,
+// returning all mismatches.
+func VerifyTestVectors(r io.Reader) (int, error) {
+	var vs []TestVector
+	if err := json.NewDecoder(r).Decode(&vs); err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, v := range vs {
+		errs = append(errs, v.Verify())
+	}
+
+	return len(vs), errors.Join(errs...)
+}
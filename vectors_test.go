@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTestVectors(t *testing.T) {
+	descs := []LayoutDescriptor{
+		{Epoch: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Layout: DefaultLayout, TimeUnit: time.Millisecond},
+		{Epoch: TwitterEpoch.Start, Layout: Layout{TimeBits: 39, TagBits: 2, MachineBits: 8, SequenceBits: 14}, TimeUnit: 10 * time.Millisecond},
+	}
+
+	vs := GenerateTestVectors(descs, 20, 1)
+	if len(vs) != 2*(7+20) {
+		t.Fatalf("expected %d vectors, got %d", 2*27, len(vs))
+	}
+	if again := GenerateTestVectors(descs, 20, 1); again[30] != vs[30] {
+		t.Error("vectors are not reproducible from the seed")
+	}
+
+	// A known vector, cross-checked with the package's generator.
+	v := NewTestVector(descs[0], 1, 0, 5, 7)
+	if v.ID != "4214791" || v.Hex != "0000000000405007" || !v.Time.Equal(descs[0].Epoch.Add(time.Millisecond)) {
+		t.Errorf("unexpected vector %+v", v)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTestVectors(&buf, vs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"id": "`) {
+		t.Errorf("ids should be JSON strings:\n%.300s", buf.String())
+	}
+	n, err := VerifyTestVectors(bytes.NewReader(buf.Bytes()))
+	if err != nil || n != len(vs) {
+		t.Fatalf("verified %d vectors: %v", n, err)
+	}
+
+	bad := strings.Replace(buf.String(), `"base62": "`+vs[1].Base62, `"base62": "x`+vs[1].Base62, 1)
+	if _, err := VerifyTestVectors(strings.NewReader(bad)); !errors.Is(err, ErrVectorMismatch) {
+		t.Errorf("expected ErrVectorMismatch, got %v", err)
+	}
+	v.Sequence = 8
+	if err := v.Verify(); !errors.Is(err, ErrVectorMismatch) {
+		t.Errorf("expected ErrVectorMismatch for a wrong sequence, got %v", err)
+	}
+}